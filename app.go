@@ -1,42 +1,79 @@
 package main
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
-	"sort"
 	"strings"
-	"syscall"
+	"sync"
 	"time"
-	"unsafe"
+
+	"zh-tool/pkg/config"
+	"zh-tool/pkg/gameinstall"
+	"zh-tool/pkg/ini"
+	"zh-tool/pkg/installer"
+	"zh-tool/pkg/installtx"
+	"zh-tool/pkg/localestore"
+	"zh-tool/pkg/p4k"
+	"zh-tool/pkg/patchwatch"
+	"zh-tool/pkg/vehicleorder"
 
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
+// 綁定給前端呼叫的方法；實際邏輯位於 pkg/ 下的套件
 type App struct {
-	ctx context.Context
+	ctx     context.Context
+	locales *localestore.Store
+	orders  *vehicleorder.Store
+
+	watchMu     sync.Mutex
+	watchCancel context.CancelFunc // 遊戲更新監看執行中時不為 nil
+
+	scanMu  sync.Mutex
+	scans   map[int]context.CancelFunc // 執行中的磁碟機掃描
+	scanSeq int
+
+	configMu      sync.Mutex
+	configWarning string           // 最近一次讀取設定檔的錯誤（例如檔案損毀已備份），前端啟動時讀取
+	cfg           *config.Settings // 設定的快取；第一次使用時讀取，之後由 updateSettings 更新
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{}
+	return &App{
+		locales: localestore.Default(),
+		orders:  vehicleorder.Default(),
+	}
 }
 
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	// 上次開啟了遊戲更新監看時自動恢復
+	if enabled, auto := a.patchWatchSettings(); enabled {
+		if scPath := a.GetSavedStarCitizenPath(); scPath != "" {
+			a.startPatchWatcher(scPath, auto)
+		}
+	}
 }
 
-// DetectStarCitizenPath 自動偵測 Star Citizen 安裝路徑
+// DetectStarCitizenPath 自動偵測 Star Citizen 安裝路徑，並保存偵測到的路徑
 func (a *App) DetectStarCitizenPath() string {
+	detectedPath := a.detectStarCitizenPath()
+	if detectedPath != "" && detectedPath != a.GetSavedStarCitizenPath() {
+		a.SaveStarCitizenPath(detectedPath)
+	}
+	return detectedPath
+}
+
+// detectStarCitizenPath 回傳仍然有效的已保存路徑，否則掃描磁碟機偵測；不保存結果
+func (a *App) detectStarCitizenPath() string {
 	// 首先檢查已保存的路徑
 	savedPath := a.GetSavedStarCitizenPath()
 	if savedPath != "" {
@@ -48,158 +85,61 @@ func (a *App) DetectStarCitizenPath() string {
 		}
 	}
 
-	var possiblePaths []string
-
-	if runtime.GOOS == "windows" {
-		// 先檢查常見安裝路徑
-		possiblePaths = []string{
-			filepath.Join(os.Getenv("ProgramFiles"), "Roberts Space Industries", "StarCitizen"),
-			filepath.Join(os.Getenv("ProgramFiles(x86)"), "Roberts Space Industries", "StarCitizen"),
-			filepath.Join("C:", "Program Files", "Roberts Space Industries", "StarCitizen"),
-		}
-
-		// 檢查常見路徑是否存在
-		for _, path := range possiblePaths {
-			if _, err := os.Stat(path); err == nil {
-				if a.ValidateStarCitizenPath(path) {
-					a.SaveStarCitizenPath(path)
-					return path
-				}
-			}
-		}
-
-		// 如果常見路徑都沒找到，掃描所有磁碟機
-		detectedPath := a.scanDrivesForStarCitizen()
-		if detectedPath != "" {
-			a.SaveStarCitizenPath(detectedPath)
-			return detectedPath
-		}
-	} else if runtime.GOOS == "linux" {
-		// Linux 常見路徑
-		homeDir, _ := os.UserHomeDir()
-		possiblePaths = []string{
-			filepath.Join(homeDir, ".wine", "drive_c", "Program Files", "Roberts Space Industries", "StarCitizen"),
-			filepath.Join(homeDir, "Games", "StarCitizen"),
-		}
+	ctx, done := a.beginDriveScan()
+	defer done()
+	return gameinstall.Detect(ctx, a.driveScanOptions())
+}
 
-		// 檢查路徑是否存在
-		for _, path := range possiblePaths {
-			if _, err := os.Stat(path); err == nil {
-				if a.ValidateStarCitizenPath(path) {
-					a.SaveStarCitizenPath(path)
-					return path
-				}
-			}
-		}
-	}
+// DiscoverStarCitizenInstalls 列出所有找到的安裝及其版本、建置版本、磁碟可用空間與寫入權限，供使用者選擇
+// 已保存的路徑標記為 preferred；選擇後以 SaveStarCitizenPath 保存
+// 掃描磁碟機期間送出 drivescan:progress 事件，可以 CancelDriveScan 取消
+func (a *App) DiscoverStarCitizenInstalls() []gameinstall.Install {
+	ctx, done := a.beginDriveScan()
+	defer done()
+	return installer.Discover(ctx, a.GetSavedStarCitizenPath(), a.driveScanOptions())
+}
 
-	return ""
+// GetDriveScanSettings 讀取磁碟機掃描設定
+func (a *App) GetDriveScanSettings() config.DriveScanSettings {
+	return a.settings().DriveScan
 }
 
-// scanDrivesForStarCitizen 掃描所有磁碟機尋找 Star Citizen 安裝路徑
-func (a *App) scanDrivesForStarCitizen() string {
-	if runtime.GOOS != "windows" {
-		return ""
+// SetDriveScanSettings 保存磁碟機掃描設定
+func (a *App) SetDriveScanSettings(ds config.DriveScanSettings) error {
+	if ds.Depth < 0 || ds.Depth > gameinstall.MaxScanDepth {
+		return fmt.Errorf("scan depth must be between 0 and %d", gameinstall.MaxScanDepth)
 	}
-
-	// 獲取所有邏輯磁碟機
-	drives := a.getLogicalDrives()
-	targetPath := filepath.Join("Roberts Space Industries", "StarCitizen")
-
-	// 常見的遊戲安裝目錄名稱
-	commonGameDirs := []string{
-		"Games",
-		"Game",
-		"Steam",
-		"SteamLibrary",
-		"Epic Games",
-		"GOG Games",
-		"Program Files",
-		"Program Files (x86)",
+	if ds.TimeoutSeconds < 0 {
+		return fmt.Errorf("scan timeout cannot be negative")
 	}
-
-	for _, drive := range drives {
-		// 跳過 A: 和 B:（通常是軟碟機）
-		if drive == "A:" || drive == "B:" {
-			continue
-		}
-
-		// 檢查該磁碟機是否可訪問
-		if _, err := os.Stat(drive); err != nil {
-			continue
-		}
-
-		// 1. 直接在磁碟機根目錄下尋找
-		searchPath := filepath.Join(drive, targetPath)
-		if _, err := os.Stat(searchPath); err == nil {
-			if a.ValidateStarCitizenPath(searchPath) {
-				return searchPath
-			}
-		}
-
-		// 2. 在常見的遊戲目錄中尋找
-		for _, gameDir := range commonGameDirs {
-			gamePath := filepath.Join(drive, gameDir)
-			if _, err := os.Stat(gamePath); err != nil {
-				continue
-			}
-
-			// 在遊戲目錄下尋找目標路徑
-			searchPath := filepath.Join(gamePath, targetPath)
-			if _, err := os.Stat(searchPath); err == nil {
-				if a.ValidateStarCitizenPath(searchPath) {
-					return searchPath
-				}
-			}
-
-			// 也在遊戲目錄的直接子目錄中搜索（例如 D:\Games\StarCitizen）
-			directPath := filepath.Join(gamePath, "StarCitizen")
-			if _, err := os.Stat(directPath); err == nil {
-				if a.ValidateStarCitizenPath(directPath) {
-					return directPath
-				}
-			}
-		}
-
-		// 3. 在用戶目錄下尋找（如果磁碟機是系統碟）
-		if drive == "C:" {
-			homeDir, err := os.UserHomeDir()
-			if err == nil {
-				userPaths := []string{
-					filepath.Join(homeDir, "Games", targetPath),
-					filepath.Join(homeDir, "Documents", "StarCitizen"),
-					filepath.Join(homeDir, "Desktop", targetPath),
-				}
-				for _, up := range userPaths {
-					if _, err := os.Stat(up); err == nil {
-						if a.ValidateStarCitizenPath(up) {
-							return up
-						}
-					}
-				}
-			}
+	folders := []string{}
+	for _, p := range ds.LibraryFolders {
+		if p = strings.TrimSpace(p); p != "" {
+			folders = append(folders, p)
 		}
 	}
-
-	return ""
+	ds.LibraryFolders = folders
+	return a.updateSettings(func(s *config.Settings) error {
+		s.DriveScan = ds
+		return nil
+	})
 }
 
-// getLogicalDrives 獲取所有邏輯磁碟機（Windows）
-func (a *App) getLogicalDrives() []string {
-	if runtime.GOOS != "windows" {
-		return []string{}
-	}
+// ScanDrivesForStarCitizen 依掃描設定平行掃描磁碟機與自訂遊戲庫，回傳找到的安裝
+// 掃描期間送出 drivescan:progress 事件；被 CancelDriveScan 取消時回傳已找到的安裝與錯誤
+func (a *App) ScanDrivesForStarCitizen() ([]string, error) {
+	ctx, done := a.beginDriveScan()
+	defer done()
+	return gameinstall.Scan(ctx, a.driveScanOptions())
+}
 
-	var drives []string
-	// 從 C: 到 Z: 檢查
-	for i := 'C'; i <= 'Z'; i++ {
-		drive := string(i) + ":"
-		// 嘗試訪問磁碟機根目錄以確認其存在
-		if _, err := os.Stat(drive); err == nil {
-			drives = append(drives, drive)
-		}
+// CancelDriveScan 取消所有執行中的磁碟機掃描（自動偵測、列出安裝與 ScanDrivesForStarCitizen）
+func (a *App) CancelDriveScan() {
+	a.scanMu.Lock()
+	defer a.scanMu.Unlock()
+	for _, cancel := range a.scans {
+		cancel()
 	}
-	return drives
 }
 
 // SaveStarCitizenPath 保存 Star Citizen 路徑到配置文件
@@ -208,64 +148,114 @@ func (a *App) SaveStarCitizenPath(path string) error {
 		return fmt.Errorf("path cannot be empty")
 	}
 
-	configPath := a.getConfigPath()
-	configDir := filepath.Dir(configPath)
+	return a.updateSettings(func(s *config.Settings) error {
+		s.StarCitizenPath = path
+		return nil
+	})
+}
 
-	// 確保配置目錄存在
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
+// GetSavedStarCitizenPath 從配置文件讀取已保存的 Star Citizen 路徑
+func (a *App) GetSavedStarCitizenPath() string {
+	return a.settings().StarCitizenPath
+}
+
+// GetAppSettings 讀取所有設定（config.json）
+func (a *App) GetAppSettings() config.Settings {
+	return *a.settings()
+}
 
-	// 讀取現有配置（如果存在）
-	config := make(map[string]interface{})
-	if data, err := os.ReadFile(configPath); err == nil {
-		if err := json.Unmarshal(data, &config); err != nil {
-			// 如果解析失敗，使用空配置
-			config = make(map[string]interface{})
+// SaveAppSettings 保存所有設定；版本與下載位置等欄位會先檢查
+func (a *App) SaveAppSettings(in config.Settings) error {
+	if in.GameChannel != "" {
+		ch, err := gameinstall.NormalizeChannel(in.GameChannel)
+		if err != nil {
+			return err
 		}
+		in.GameChannel = ch
+	}
+	in.INIFormat = in.INIFormat.Normalize()
+	if err := in.INIFormat.Validate(); err != nil {
+		return err
 	}
+	if in.DriveScan.Depth < 0 || in.DriveScan.Depth > gameinstall.MaxScanDepth || in.DriveScan.TimeoutSeconds < 0 {
+		return fmt.Errorf("invalid drive scan settings")
+	}
+	if src := strings.TrimSpace(in.DownloadSource); src != "" &&
+		!strings.HasPrefix(src, "https://") && !strings.HasPrefix(src, "http://") {
+		return fmt.Errorf("download source must be an http(s) URL: %s", src)
+	}
+	return a.updateSettings(func(s *config.Settings) error {
+		s.Set(in)
+		return nil
+	})
+}
 
-	// 更新路徑
-	config["starCitizenPath"] = path
-	config["lastUpdated"] = time.Now().Format(time.RFC3339)
+// GetDownloadSource 回傳中文化檔案的下載位置（未設定時為官方網站）
+func (a *App) GetDownloadSource() string {
+	if src := strings.TrimSpace(a.settings().DownloadSource); src != "" {
+		return src
+	}
+	return config.DefaultDownloadSource
+}
 
-	// 寫入配置文件
-	data, err := json.MarshalIndent(config, "", "  ")
+// settings 回傳快取設定的複本；第一次呼叫時讀取配置文件
+// 檔案損毀時原檔已備份，改用預設設定並通知前端
+func (a *App) settings() *config.Settings {
+	a.configMu.Lock()
+	cached := a.cfg
+	a.configMu.Unlock()
+	if cached != nil {
+		return cached.Clone()
+	}
+	s, err := config.Load(config.Path())
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+		a.reportConfigError(err)
 	}
-
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+	if a.cfg == nil {
+		a.cfg = s
 	}
-
-	return nil
+	return a.cfg.Clone()
 }
 
-// GetSavedStarCitizenPath 從配置文件讀取已保存的 Star Citizen 路徑
-func (a *App) GetSavedStarCitizenPath() string {
-	configPath := a.getConfigPath()
-	data, err := os.ReadFile(configPath)
+// updateSettings 讀取配置文件、修改後以原子方式寫回，並以寫入的內容更新快取
+func (a *App) updateSettings(fn func(s *config.Settings) error) error {
+	// 先讀取一次，檔案損毀時由 settings 備份並通知
+	a.settings()
+	saved, err := config.Update(config.Path(), fn)
 	if err != nil {
-		return ""
+		return err
 	}
+	a.configMu.Lock()
+	a.cfg = saved.Clone()
+	a.configMu.Unlock()
+	return nil
+}
 
-	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return ""
+// reportConfigError 送出 config:error 事件（命令列模式印在標準錯誤輸出）
+func (a *App) reportConfigError(err error) {
+	data := map[string]string{"error": err.Error()}
+	var ce *config.CorruptError
+	if errors.As(err, &ce) {
+		data["backup"] = ce.Backup
 	}
-
-	if path, ok := config["starCitizenPath"].(string); ok {
-		return path
+	a.configMu.Lock()
+	a.configWarning = err.Error()
+	a.configMu.Unlock()
+	if a.ctx == nil {
+		fmt.Fprintln(os.Stderr, "WARNING:", err.Error())
 	}
-
-	return ""
+	a.emit("config:error", data)
 }
 
-// getConfigPath 獲取配置文件路徑
-func (a *App) getConfigPath() string {
-	base := getLocalDataBase()
-	return filepath.Join(base, "config.json")
+// GetConfigWarning 回傳最近一次讀取設定檔的錯誤並清除（前端載入前發生的錯誤不會收到 config:error 事件）
+func (a *App) GetConfigWarning() string {
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+	w := a.configWarning
+	a.configWarning = ""
+	return w
 }
 
 // SelectDirectory 開啟目錄選擇對話框
@@ -284,246 +274,184 @@ func (a *App) SelectDirectory() (string, error) {
 
 // ValidateStarCitizenPath 驗證選擇的路徑是否為有效的 Star Citizen 目錄
 func (a *App) ValidateStarCitizenPath(path string) bool {
-	if path == "" {
-		return false
-	}
-
-	// 支援三種版本資料夾：LIVE / PTU / EPTU
-	candidates := []string{
-		path,
-		filepath.Join(path, "LIVE"),
-		filepath.Join(path, "PTU"),
-		filepath.Join(path, "EPTU"),
-	}
-
-	hasIndicators := func(base string) bool {
-		indicators := []string{
-			filepath.Join(base, "Bin64"),
-			filepath.Join(base, "Data"),
-			filepath.Join(base, "Data", "data.p4k"),
-			filepath.Join(base, "data.p4k"),
-		}
-		for _, p := range indicators {
-			if _, err := os.Stat(p); err == nil {
-				return true
-			}
-		}
-		return false
-	}
-
-	for _, c := range candidates {
-		if hasIndicators(c) {
-			return true
-		}
-	}
-	return false
+	return gameinstall.Validate(path)
 }
 
 // GetLocalizationPath 獲取中文化檔案應該放置的路徑（已改為本機資料夾）
 func (a *App) GetLocalizationPath(scPath string) string {
-	return getLocalLocalizationBase()
+	return a.locales.Root
 }
 
 // ListInstalledLocalizations 列出目前可能已安裝的語系資料夾名稱（去重）
 func (a *App) ListInstalledLocalizations(scPath string) []string {
-	base := getLocalLocalizationBase()
-	entries, err := os.ReadDir(base)
-	if err != nil {
-		return []string{}
-	}
-	var result []string
-	for _, e := range entries {
-		if e.IsDir() {
-			name := e.Name()
-			if strings.HasPrefix(name, ".") {
-				continue
-			}
-			result = append(result, name)
-		}
-	}
-	return result
+	return a.locales.List()
 }
 
 // CheckLocalizationExists 檢查中文化檔案是否已存在
 func (a *App) CheckLocalizationExists(scPath string) bool {
-	locBase := getLocalLocalizationBase()
-	entries, err := os.ReadDir(locBase)
-	if err != nil {
-		return false
-	}
-	for _, e := range entries {
-		if !e.IsDir() {
-			continue
-		}
-		name := strings.ToLower(e.Name())
-		if strings.Contains(name, "chinese_(traditional)") || strings.Contains(name, "chinese_(simplified)") || strings.Contains(name, "chinese") || strings.Contains(name, "zh") {
-			return true
-		}
-	}
-	return false
+	return a.locales.HasChinese()
 }
 
 // CreateLocalizationDir 創建中文化目錄
 func (a *App) CreateLocalizationDir(scPath string) error {
 	// 建立本機 Localization 基底資料夾
-	base := getLocalLocalizationBase()
-	return os.MkdirAll(base, 0755)
+	return a.locales.EnsureRoot()
 }
 
 // HasLocalizationBase 檢查是否存在 Localization 基底資料夾
 func (a *App) HasLocalizationBase(scPath string) bool {
-	base := getLocalLocalizationBase()
-	if _, err := os.Stat(base); err == nil {
-		return true
-	}
-	return false
+	return a.locales.HasRoot()
 }
 
-// DownloadAndInstallLocalization 從指定 URL 下載 global.ini 並安裝到 LIVE/Localization/chinese_tranditional
-func (a *App) DownloadAndInstallLocalization(scPath string, url string) (string, error) {
-	if scPath == "" || !a.ValidateStarCitizenPath(scPath) {
-		return "", fmt.Errorf("invalid Star Citizen path")
-	}
-
-	// 下載檔案
-	resp, err := http.Get(url)
-	if err != nil {
-		return "", fmt.Errorf("download failed: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("download failed: status %d", resp.StatusCode)
-	}
+// ListGameChannels 列出安裝根目錄下存在的版本資料夾（LIVE / PTU / EPTU / TECH-PREVIEW）
+func (a *App) ListGameChannels(scPath string) []string {
+	return gameinstall.ListChannels(scPath)
+}
 
-	// 目標路徑：<scPath>/LIVE/data/Localization/chinese_(traditional)/global.ini
-	targetDir := filepath.Join(scPath, "LIVE", "data", "Localization", "chinese_(traditional)")
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return "", fmt.Errorf("mkdir failed: %w", err)
+// GetGameChannel 讀取目前選擇的版本（未設定時為 LIVE）
+func (a *App) GetGameChannel() string {
+	if ch, err := gameinstall.NormalizeChannel(a.settings().GameChannel); err == nil {
+		return ch
 	}
-	targetFile := filepath.Join(targetDir, "global.ini")
+	return gameinstall.DefaultChannel
+}
 
-	f, err := os.Create(targetFile)
+// SetGameChannel 保存目前選擇的版本；未指定版本的安裝、語系切換與重設都會使用此版本
+func (a *App) SetGameChannel(channel string) error {
+	ch, err := gameinstall.NormalizeChannel(channel)
 	if err != nil {
-		return "", fmt.Errorf("create file failed: %w", err)
+		return err
 	}
-	defer f.Close()
-	if _, err := io.Copy(f, resp.Body); err != nil {
-		return "", fmt.Errorf("write file failed: %w", err)
+	return a.updateSettings(func(s *config.Settings) error {
+		s.GameChannel = ch
+		return nil
+	})
+}
+
+// GetGameBuildInfo 讀取指定版本（空字串為目前版本）的 build_manifest.id
+func (a *App) GetGameBuildInfo(scPath, channel string) (gameinstall.BuildInfo, error) {
+	if strings.TrimSpace(channel) == "" {
+		channel = a.GetGameChannel()
 	}
+	return gameinstall.ReadBuildInfo(scPath, channel)
+}
 
-	return targetFile, nil
+// RecordLocaleGameBuild 以目前版本的遊戲建置記錄為語系檔的對應版本（本機儲存區與 config.json）
+func (a *App) RecordLocaleGameBuild(scPath, localeName string) (localestore.Meta, error) {
+	return a.recordLocaleGameBuild(scPath, "", localeName)
 }
 
-// SetUserLanguage 設定使用者語系：在 <scPath>/LIVE/data/system.cfg 和 user.cfg 寫入 sys_languages 與 g_language（若檔案不存在則建立）
-func (a *App) SetUserLanguage(scPath string, locale string) (string, error) {
-	if scPath == "" || !a.ValidateStarCitizenPath(scPath) {
-		return "", fmt.Errorf("invalid Star Citizen path")
+func (a *App) recordLocaleGameBuild(scPath, channel, localeName string) (localestore.Meta, error) {
+	build, err := a.GetGameBuildInfo(scPath, channel)
+	if err != nil {
+		return localestore.Meta{}, err
 	}
-	if strings.TrimSpace(locale) == "" {
-		return "", fmt.Errorf("invalid locale")
+	meta, err := a.locales.SetGameBuild(localeName, build)
+	if err != nil {
+		return meta, err
 	}
+	return meta, a.updateSettings(func(s *config.Settings) error {
+		s.LocaleBuilds[localeName] = build
+		return nil
+	})
+}
 
-	liveDir := filepath.Join(scPath, "LIVE", "data")
-	if _, err := os.Stat(liveDir); err != nil {
-		return "", fmt.Errorf("LIVE directory not found")
-	}
+// GetLocaleGameBuild 讀取語系檔記錄的遊戲建置
+func (a *App) GetLocaleGameBuild(localeName string) (localestore.Meta, error) {
+	return a.locales.Meta(localeName)
+}
 
-	systemCfgPath := filepath.Join(liveDir, "system.cfg")
-	userCfgPath := filepath.Join(scPath, "LIVE", "user.cfg")
-	desiredLangLine := "g_language=" + locale
-	desiredSysLangLine := "sys_languages=" + locale
+// CheckLocaleGameBuild 安裝前比對語系檔記錄的遊戲建置與目前遊戲版本，不一致時 Warning 有值
+func (a *App) CheckLocaleGameBuild(scPath, localeName string) (localestore.BuildCheck, error) {
+	return a.checkLocaleGameBuild(scPath, "", localeName)
+}
 
-	// 寫入 system.cfg
-	content, err := a.updateOrCreateCfgFile(systemCfgPath, desiredLangLine, desiredSysLangLine)
+func (a *App) checkLocaleGameBuild(scPath, channel, localeName string) (localestore.BuildCheck, error) {
+	meta, err := a.locales.Meta(localeName)
 	if err != nil {
-		return "", fmt.Errorf("write system.cfg failed: %w", err)
+		return localestore.BuildCheck{}, err
 	}
+	// 讀不到 build_manifest.id 時無從比對，視為相符
+	game, _ := a.GetGameBuildInfo(scPath, channel)
+	return localestore.CheckBuild(localeName, meta, game), nil
+}
 
-	// 寫入 user.cfg（相同內容）
-	if err := os.WriteFile(userCfgPath, []byte(content), 0644); err != nil {
-		return "", fmt.Errorf("write user.cfg failed: %w", err)
-	}
+// DownloadAndInstallLocalization 從指定 URL 下載 global.ini 並安裝到目前版本的 Localization/chinese_(traditional)
+func (a *App) DownloadAndInstallLocalization(scPath string, url string) (string, error) {
+	return a.DownloadAndInstallLocalizationForChannel(scPath, a.GetGameChannel(), url)
+}
 
-	return systemCfgPath, nil
+// DownloadAndInstallLocalizationForChannel 同 DownloadAndInstallLocalization，安裝到指定版本
+func (a *App) DownloadAndInstallLocalizationForChannel(scPath, channel, url string) (string, error) {
+	return installer.DownloadAndInstall(scPath, channel, url)
 }
 
-// updateOrCreateCfgFile 更新或建立配置檔案，回傳最終內容
-func (a *App) updateOrCreateCfgFile(cfgPath, desiredLangLine, desiredSysLangLine string) (string, error) {
-	// 如果不存在就直接建立
-	if _, err := os.Stat(cfgPath); os.IsNotExist(err) {
-		content := desiredSysLangLine + "\n" + desiredLangLine + "\n" + "g_languageAudio=english\n"
-		if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
-			return "", err
-		}
-		return content, nil
-	}
+// SetUserLanguage 設定使用者語系：在目前版本的 data/system.cfg 和 user.cfg 寫入 sys_languages 與 g_language（若檔案不存在則建立）
+func (a *App) SetUserLanguage(scPath string, locale string) (string, error) {
+	return a.SetUserLanguageForChannel(scPath, a.GetGameChannel(), locale)
+}
 
-	// 讀取並更新
-	data, err := os.ReadFile(cfgPath)
+// SetUserLanguageForChannel 同 SetUserLanguage，寫入指定版本（Windows 透過提權拷貝程式寫入）
+func (a *App) SetUserLanguageForChannel(scPath, channel, locale string) (string, error) {
+	ch, err := gameinstall.NormalizeChannel(channel)
 	if err != nil {
 		return "", err
 	}
-	lines := strings.Split(string(data), "\n")
-	foundLang := false
-	foundSys := false
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "g_language=") || strings.HasPrefix(trimmed, "g_language ") {
-			lines[i] = desiredLangLine
-			foundLang = true
-		} else if strings.HasPrefix(trimmed, "sys_languages=") || strings.HasPrefix(trimmed, "sys_languages ") {
-			lines[i] = desiredSysLangLine
-			foundSys = true
-		}
-	}
-	if !foundLang {
-		lines = append(lines, desiredLangLine)
-	}
-	if !foundSys {
-		lines = append(lines, desiredSysLangLine)
-	}
-	updated := strings.Join(lines, "\n")
-	if err := os.WriteFile(cfgPath, []byte(updated), 0644); err != nil {
+	locale = strings.TrimSpace(locale)
+	if err := a.reportCopier(installer.RunElevated(scPath, installtx.Request{Channel: ch, Language: locale})); err != nil {
 		return "", err
 	}
-	return updated, nil
+	a.updatePatchState(ch, func(st *patchwatch.State, ch string) error {
+		st.RecordLanguage(ch, locale)
+		return nil
+	})
+	return filepath.Join(gameinstall.DataDir(scPath, ch), "system.cfg"), nil
 }
 
-// GetUserLanguage 讀取 <scPath>/LIVE/data/system.cfg 的 g_language 值，若不存在或讀取失敗回傳空字串
+// GetUserLanguage 讀取目前版本 data/system.cfg 的 g_language 值，若不存在或讀取失敗回傳空字串
 func (a *App) GetUserLanguage(scPath string) string {
-	if scPath == "" || !a.ValidateStarCitizenPath(scPath) {
-		return ""
-	}
-	cfgPath := filepath.Join(scPath, "LIVE", "data", "system.cfg")
-	data, err := os.ReadFile(cfgPath)
-	if err != nil {
-		return ""
-	}
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "g_language ") || strings.HasPrefix(trimmed, "g_language=") || strings.HasPrefix(trimmed, "g_language=") {
-			// 允許有或沒有空白，抓 '=' 之後的值
-			parts := strings.SplitN(strings.ReplaceAll(trimmed, " ", ""), "=", 2)
-			if len(parts) == 2 {
-				return strings.TrimSpace(parts[1])
-			}
-		}
-	}
-	return ""
+	return a.GetUserLanguageForChannel(scPath, a.GetGameChannel())
+}
+
+// GetUserLanguageForChannel 同 GetUserLanguage，讀取指定版本
+func (a *App) GetUserLanguageForChannel(scPath, channel string) string {
+	return installer.GetLanguage(scPath, channel)
 }
 
-// ResetToDefaultLanguage 刪除 <scPath>/LIVE/data/system.cfg（若存在）以回復原版語系
+// ResetToDefaultLanguage 刪除目前版本的 data/system.cfg（若存在），並移除 user.cfg 的語系設定以回復原版語系
 func (a *App) ResetToDefaultLanguage(scPath string) error {
-	if scPath == "" || !a.ValidateStarCitizenPath(scPath) {
-		return fmt.Errorf("invalid Star Citizen path")
+	return a.ResetToDefaultLanguageForChannel(scPath, a.GetGameChannel())
+}
+
+// ResetToDefaultLanguageForChannel 同 ResetToDefaultLanguage，重設指定版本
+func (a *App) ResetToDefaultLanguageForChannel(scPath, channel string) error {
+	if err := a.reportCopier(installer.RunElevated(scPath, installtx.Request{Channel: channel, ResetLanguage: true})); err != nil {
+		return err
 	}
-	cfgPath := filepath.Join(scPath, "LIVE", "data", "system.cfg")
-	if _, err := os.Stat(cfgPath); err == nil {
-		if err := os.Remove(cfgPath); err != nil {
-			return fmt.Errorf("remove system.cfg failed: %w", err)
-		}
+	// 使用者主動回復原版語系，之後不再視為被遊戲更新還原
+	a.updatePatchState(channel, func(st *patchwatch.State, ch string) error {
+		st.RecordLanguage(ch, "")
+		return nil
+	})
+	return nil
+}
+
+// UninstallLocalization 解除安裝目前版本的語系：還原原始 global.ini（沒有原檔則移除語系資料夾），
+// 並將 system.cfg / user.cfg 還原為第一次修改前的內容；Windows 透過提權拷貝程式執行
+func (a *App) UninstallLocalization(scPath, localeName string) error {
+	return a.UninstallLocalizationForChannel(scPath, a.GetGameChannel(), localeName)
+}
+
+// UninstallLocalizationForChannel 同 UninstallLocalization，處理指定版本
+func (a *App) UninstallLocalizationForChannel(scPath, channel, localeName string) error {
+	if err := a.reportCopier(installer.UninstallElevated(scPath, channel, localeName)); err != nil {
+		return err
 	}
+	// 已解除安裝，不再視為被遊戲更新還原
+	a.updatePatchState(channel, func(st *patchwatch.State, ch string) error {
+		st.Forget(ch)
+		return nil
+	})
 	return nil
 }
 
@@ -535,191 +463,261 @@ func (a *App) GetSystemInfo() map[string]string {
 	}
 }
 
-// INIKeyValue 表示 INI 檔案中的鍵值對
-type INIKeyValue struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+// ReadINIFile 讀取 INI 檔案並回傳所有鍵值對（保持順序）
+func (a *App) ReadINIFile(filePath string) ([]ini.KeyValue, error) {
+	return ini.ReadEntries(filePath)
 }
 
-// ReadINIFile 讀取 INI 檔案並回傳所有鍵值對（保持順序）
-func (a *App) ReadINIFile(filePath string) ([]INIKeyValue, error) {
-	data, err := os.ReadFile(filePath)
+// CompareINIFiles 比對兩個 INI 檔案，回傳 currentFile 中缺少的項目（相對於 referenceFile）
+func (a *App) CompareINIFiles(currentPath, referencePath string) ([]ini.KeyValue, error) {
+	result, err := a.CompareINIFilesDetailed(currentPath, referencePath)
 	if err != nil {
-		return nil, fmt.Errorf("read ini file failed: %w", err)
-	}
-
-	// 移除 UTF-8 BOM（如果存在）
-	content := string(data)
-	content = strings.TrimPrefix(content, "\uFEFF")
-
-	// 統一處理換行符（Windows \r\n 轉為 Unix \n）
-	content = strings.ReplaceAll(content, "\r\n", "\n")
-	content = strings.ReplaceAll(content, "\r", "\n")
-
-	var result []INIKeyValue
-	lines := strings.Split(content, "\n")
-	for _, line := range lines {
-		// 移除所有類型的空白字符（包括不可見字符）
-		line = strings.TrimSpace(line)
-
-		// 跳過空行和註解
-		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// 解析 key=value
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 {
-			// 清理 key 和 value，移除所有不可見字符
-			key := cleanString(parts[0])
-			value := cleanString(parts[1])
-
-			if key != "" {
-				result = append(result, INIKeyValue{
-					Key:   key,
-					Value: value,
-				})
-			}
-		}
+		return nil, err
 	}
-	return result, nil
+	return result.Missing, nil
 }
 
-// cleanString 清理字串，移除前後空白和不可見字符
-func cleanString(s string) string {
-	// 先做基本的 trim
-	s = strings.TrimSpace(s)
-
-	// 移除常見的不可見字符
-	s = strings.Trim(s, "\u200B\u200C\u200D\uFEFF") // Zero-width spaces and BOM
-
-	return s
+// CompareINIFilesDetailed 比對兩個 INI 檔案並回傳詳細資訊（用於除錯）
+// referencePath 為空時，以已保存遊戲路徑目前版本 Data.p4k 中的英文 global.ini 為參考
+func (a *App) CompareINIFilesDetailed(currentPath, referencePath string) (ini.CompareResult, error) {
+	referencePath, err := a.defaultEnglishReference(referencePath)
+	if err != nil {
+		return ini.CompareResult{}, err
+	}
+	return ini.Compare(currentPath, referencePath)
 }
 
-// CompareResult 比對結果詳細資訊
-type CompareResult struct {
-	Missing        []INIKeyValue `json:"missing"`        // 缺少的項目
-	CurrentCount   int           `json:"currentCount"`   // 當前檔案的項目數量
-	ReferenceCount int           `json:"referenceCount"` // 參考檔案的項目數量
+// CompareINIFilesStale 比對模式：列出譯文檔中英文原文在翻譯後已變動的鍵
+// currentPath 須為本機儲存區中的語系檔（指紋記錄於語系資料夾）；englishPath 為空時同 CompareINIFilesDetailed
+func (a *App) CompareINIFilesStale(currentPath, englishPath string) (ini.StaleCompareResult, error) {
+	localeName, ok := a.locales.LocaleOf(currentPath)
+	if !ok {
+		return ini.StaleCompareResult{}, fmt.Errorf("source fingerprints are only tracked for locales in the local store: %s", currentPath)
+	}
+	englishPath, err := a.defaultEnglishReference(englishPath)
+	if err != nil {
+		return ini.StaleCompareResult{}, err
+	}
+	return a.locales.CompareStale(localeName, englishPath)
 }
 
-// CompareINIFiles 比對兩個 INI 檔案，回傳 currentFile 中缺少的項目（相對於 referenceFile）
-func (a *App) CompareINIFiles(currentPath, referencePath string) ([]INIKeyValue, error) {
-	current, err := a.ReadINIFile(currentPath)
+// ExtractEnglishINIFromP4K 自遊戲指定版本（空字串為目前版本）的 Data.p4k 取出英文 global.ini，回傳取出的檔案路徑
+// 檔案快取於 <資料目錄>/Reference/<channel>/global.ini，Data.p4k 沒有更新時直接使用快取
+func (a *App) ExtractEnglishINIFromP4K(scPath, channel string) (string, error) {
+	if strings.TrimSpace(channel) == "" {
+		channel = a.GetGameChannel()
+	}
+	channel, err := gameinstall.NormalizeChannel(channel)
 	if err != nil {
-		return nil, fmt.Errorf("read current file failed: %w", err)
+		return "", err
 	}
-
-	reference, err := a.ReadINIFile(referencePath)
+	archive, err := gameinstall.P4KPath(scPath, channel)
 	if err != nil {
-		return nil, fmt.Errorf("read reference file failed: %w", err)
+		return "", err
+	}
+	dest := filepath.Join(config.DataDir(), "Reference", channel, localestore.INIFileName)
+	if cached, err := os.Stat(dest); err == nil {
+		if src, err := os.Stat(archive); err == nil && cached.ModTime().After(src.ModTime()) {
+			return dest, nil
+		}
+	}
+	if _, err := p4k.ExtractFile(archive, p4k.EnglishGlobalINI, dest); err != nil {
+		return "", fmt.Errorf("extract english global.ini failed: %w", err)
 	}
+	return dest, nil
+}
 
-	// 建立 current 的 key map
-	currentKeys := make(map[string]bool)
-	for _, item := range current {
-		currentKeys[item.Key] = true
+// defaultEnglishReference 未指定參考檔時，自已保存遊戲路徑的 Data.p4k 取出英文 global.ini
+func (a *App) defaultEnglishReference(referencePath string) (string, error) {
+	if strings.TrimSpace(referencePath) != "" {
+		return referencePath, nil
 	}
+	scPath := a.GetSavedStarCitizenPath()
+	if scPath == "" {
+		return "", fmt.Errorf("reference file is required (no saved game path to read Data.p4k from)")
+	}
+	return a.ExtractEnglishINIFromP4K(scPath, "")
+}
 
-	// 找出缺少的項目（保持參考檔案的順序）
-	var missing []INIKeyValue
-	for _, item := range reference {
-		if !currentKeys[item.Key] {
-			missing = append(missing, item)
+// RecordSourceFingerprints 記錄本機語系中鍵所對應的英文原文指紋
+// keys 為空時只替尚無記錄的鍵建立基準；指定 keys 時覆寫這些鍵（表示已依目前英文重新翻譯）
+// englishPath 為空時自動尋找英文原文（見 englishSource）
+// 修改譯文的操作（編輯、匯入、合併、個人覆寫）會自動替變動的鍵記錄指紋，此方法用於替既有譯文建立基準
+func (a *App) RecordSourceFingerprints(localeName, englishPath string, keys []string) (int, error) {
+	if strings.TrimSpace(englishPath) == "" {
+		if englishPath = a.englishSource(); englishPath == "" {
+			return 0, fmt.Errorf("english file not found; specify the english global.ini")
 		}
 	}
-
-	return missing, nil
+	return a.locales.RecordFingerprints(localeName, englishPath, keys)
 }
 
-// CompareINIFilesDetailed 比對兩個 INI 檔案並回傳詳細資訊（用於除錯）
-func (a *App) CompareINIFilesDetailed(currentPath, referencePath string) (CompareResult, error) {
-	current, err := a.ReadINIFile(currentPath)
-	if err != nil {
-		return CompareResult{}, fmt.Errorf("read current file failed: %w", err)
+// englishSource 指紋所依據的英文 global.ini：本機或遊戲資料夾中的英文語系（見 findEnglishReference），
+// 其次為已保存路徑目前版本 Data.p4k 中的英文檔；都找不到時回傳空字串
+func (a *App) englishSource() string {
+	scPath, channel := a.GetSavedStarCitizenPath(), a.GetGameChannel()
+	if p := a.findEnglishReference(scPath, channel); p != "" {
+		return p
 	}
-
-	reference, err := a.ReadINIFile(referencePath)
+	if scPath == "" {
+		return ""
+	}
+	p, err := a.ExtractEnglishINIFromP4K(scPath, channel)
 	if err != nil {
-		return CompareResult{}, fmt.Errorf("read reference file failed: %w", err)
+		return ""
 	}
+	return p
+}
 
-	// 建立 current 的 key map
-	currentKeys := make(map[string]bool)
-	for _, item := range current {
-		currentKeys[item.Key] = true
+// trackFingerprints 比對語系修改前的譯文（a.locales.Values），替變動的鍵記錄目前英文原文的指紋
+// 指紋只用於提示過時譯文，找不到英文原文或寫入失敗時略過，不影響已完成的修改
+func (a *App) trackFingerprints(localeName string, before map[string]string) {
+	if english := a.englishSource(); english != "" {
+		_, _ = a.locales.RecordChanges(localeName, before, english)
 	}
+}
 
-	// 找出缺少的項目（保持參考檔案的順序）
-	var missing []INIKeyValue
-	for _, item := range reference {
-		if !currentKeys[item.Key] {
-			missing = append(missing, item)
-		}
+// UpdateINIFile 更新 INI 檔案：既有鍵就地改值，新鍵依參考檔案的順序插入，其餘行維持原樣
+func (a *App) UpdateINIFile(targetPath, referencePath string, updates []ini.KeyValue) error {
+	doc, err := ini.Load(targetPath)
+	if err != nil {
+		return fmt.Errorf("read target file failed: %w", err)
 	}
-
-	return CompareResult{
-		Missing:        missing,
-		CurrentCount:   len(current),
-		ReferenceCount: len(reference),
-	}, nil
+	if err := ini.ApplyUpdates(doc, referencePath, updates); err != nil {
+		return err
+	}
+	// 寫回檔案（保留原檔的行尾與 BOM）
+	return a.writeINIDocument(targetPath, doc)
 }
 
-// UpdateINIFile 更新 INI 檔案，將新項目按照參考檔案的順序插入
-func (a *App) UpdateINIFile(targetPath, referencePath string, updates []INIKeyValue) error {
-	// 強制使用 Windows CRLF 與 UTF-8 BOM
-	eol, hasBOM := "\r\n", true
-
-	// 讀取參考檔案以獲取正確的順序
-	reference, err := a.ReadINIFile(referencePath)
+// MergeEnglishUpdate 遊戲改版時，以舊英文、新英文與現有譯文進行三方合併
+// outputPath 為空時覆寫 translatedPath
+func (a *App) MergeEnglishUpdate(oldEnglishPath, newEnglishPath, translatedPath, outputPath string) (ini.MergeReport, error) {
+	if strings.TrimSpace(oldEnglishPath) == "" || strings.TrimSpace(newEnglishPath) == "" || strings.TrimSpace(translatedPath) == "" {
+		return ini.MergeReport{}, fmt.Errorf("old english, new english and translated file paths are required")
+	}
+	if strings.TrimSpace(outputPath) == "" {
+		outputPath = translatedPath
+	}
+	doc, report, err := ini.MergeEnglish(oldEnglishPath, newEnglishPath, translatedPath)
+	report.OutputPath = outputPath
 	if err != nil {
-		return fmt.Errorf("read reference file failed: %w", err)
+		return report, err
 	}
+	if err := a.writeINIDocument(outputPath, doc); err != nil {
+		return report, err
+	}
+	return report, nil
+}
 
-	// 讀取目標檔案的現有內容
-	current, err := a.ReadINIFile(targetPath)
+// WriteINIFile 寫入 INI 檔案：以既有檔案為基礎就地套用差異，保留註解、空行與原始順序
+func (a *App) WriteINIFile(filePath string, items []ini.KeyValue) error {
+	// 既有檔案沿用原本的行尾與 BOM；新檔案使用預設格式
+	doc, err := ini.LoadOrEmpty(filePath)
 	if err != nil {
-		return fmt.Errorf("read target file failed: %w", err)
+		return err
 	}
+	doc.SyncEntries(items)
+	return a.writeINIDocument(filePath, doc)
+}
 
-	// 建立更新內容的 map
-	updateMap := make(map[string]string)
-	for _, item := range updates {
-		updateMap[item.Key] = item.Value
+// GetINIDiagnostics 解析 INI 檔案並回傳所有格式問題（無 '='、空鍵名、控制字元等）
+func (a *App) GetINIDiagnostics(filePath string) ([]ini.Warning, error) {
+	warnings, err := ini.ScanFile(filePath, func(*ini.Line) {})
+	if err != nil {
+		return nil, err
 	}
-
-	// 建立現有內容的 map
-	currentMap := make(map[string]string)
-	for _, item := range current {
-		currentMap[item.Key] = item.Value
+	if warnings == nil {
+		warnings = []ini.Warning{}
 	}
+	return warnings, nil
+}
 
-	// 合併：按參考檔案的順序，使用更新值（若有）或現有值
-	var merged []INIKeyValue
-	for _, refItem := range reference {
-		if curVal, exists := currentMap[refItem.Key]; exists {
-			if newVal, hasUpdate := updateMap[refItem.Key]; hasUpdate {
-				// 對既有鍵：若提供更新則覆蓋
-				merged = append(merged, INIKeyValue{Key: refItem.Key, Value: newVal})
-			} else {
-				// 無更新則保留原值
-				merged = append(merged, INIKeyValue{Key: refItem.Key, Value: curVal})
-			}
-		} else if newVal, hasUpdate := updateMap[refItem.Key]; hasUpdate {
-			// 新增鍵：使用更新值
-			merged = append(merged, INIKeyValue{Key: refItem.Key, Value: newVal})
-		}
+// DetectFileEncoding 偵測檔案的文字編碼（utf-8 / utf-16le / utf-16be / big5 / gbk）
+func (a *App) DetectFileEncoding(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
 	}
+	return ini.DetectEncoding(data), nil
+}
+
+// GetINIFormatOverride 讀取 config.json 中的 INI 格式覆寫設定
+func (a *App) GetINIFormatOverride() ini.FormatOverride {
+	return a.settings().INIFormat
+}
 
-	// 寫回檔案（保留行尾與 BOM）
-	if err := writeINIWithFormat(targetPath, merged, eol, hasBOM); err != nil {
+// SetINIFormatOverride 保存 INI 格式覆寫設定到 config.json
+func (a *App) SetINIFormatOverride(o ini.FormatOverride) error {
+	o = o.Normalize()
+	if err := o.Validate(); err != nil {
 		return err
 	}
-	return nil
+	return a.updateSettings(func(s *config.Settings) error {
+		s.INIFormat = o
+		return nil
+	})
 }
 
-// SelectFile 開啟檔案選擇對話框
-func (a *App) SelectFile(title string) (string, error) {
+// writeINIDocument 依文件偵測到的格式（套用使用者覆寫設定後）寫入檔案
+func (a *App) writeINIDocument(filePath string, doc *ini.Document) error {
+	// 寫入本機儲存區的語系檔前先保留快照，寫入後替變動的鍵記錄英文原文指紋
+	localeName, inStore := a.locales.LocaleOf(filePath)
+	if !inStore {
+		return doc.WriteFile(filePath, a.GetINIFormatOverride().Apply(doc.Format))
+	}
+	if _, err := a.locales.Snapshot(localeName, "edit"); err != nil {
+		return fmt.Errorf("snapshot before write failed: %w", err)
+	}
+	before := a.locales.Values(localeName)
+	if err := doc.WriteFile(filePath, a.GetINIFormatOverride().Apply(doc.Format)); err != nil {
+		return err
+	}
+	a.trackFingerprints(localeName, before)
+	return nil
+}
+
+// ValidateTranslation 比對譯文檔與英文參考檔的佔位符與標記
+// referencePath 為空時只檢查譯文本身的格式錯誤與未成對標籤
+func (a *App) ValidateTranslation(translatedPath, referencePath string) (ini.ValidationReport, error) {
+	if strings.TrimSpace(translatedPath) == "" {
+		return ini.ValidationReport{}, fmt.Errorf("translated file path is required")
+	}
+	return ini.ValidateFile(translatedPath, strings.TrimSpace(referencePath))
+}
+
+// ValidateLocalLocale 驗證本機儲存區指定語系的佔位符，英文參考檔自動尋找（channel 為空時使用目前版本）
+func (a *App) ValidateLocalLocale(scPath, channel, localeName string) (ini.ValidationReport, error) {
+	iniPath, err := a.GetLocalLocaleINIPath(localeName)
+	if err != nil {
+		return ini.ValidationReport{}, err
+	}
+	if strings.TrimSpace(channel) == "" {
+		channel = a.GetGameChannel()
+	}
+	ch, err := gameinstall.NormalizeChannel(channel)
+	if err != nil {
+		return ini.ValidationReport{}, err
+	}
+	return ini.ValidateFile(iniPath, a.findEnglishReference(scPath, ch))
+}
+
+// findEnglishReference 尋找可用的英文 global.ini：本機儲存區優先，其次為遊戲資料夾中指定版本的英文語系
+func (a *App) findEnglishReference(scPath, channel string) string {
+	candidates := []string{a.locales.Path("english")}
+	if scPath != "" {
+		candidates = append(candidates, filepath.Join(gameinstall.LocalizationDir(scPath, channel, "english"), "global.ini"))
+	}
+	for _, p := range candidates {
+		if st, err := os.Stat(p); err == nil && !st.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+// SelectFile 開啟檔案選擇對話框
+func (a *App) SelectFile(title string) (string, error) {
 	options := wailsRuntime.OpenDialogOptions{
 		Title: title,
 		Filters: []wailsRuntime.FileFilter{
@@ -765,7 +763,7 @@ func (a *App) GetCurrentLocaleINIPath(scPath string) (string, error) {
 		return "", fmt.Errorf("no locale configured")
 	}
 	// 改為本機儲存路徑
-	iniPath := filepath.Join(getLocalLocalizationBase(), currentLocale, "global.ini")
+	iniPath := a.locales.Path(currentLocale)
 	if _, err := os.Stat(iniPath); err == nil {
 		return iniPath, nil
 	}
@@ -816,26 +814,7 @@ func (a *App) SaveTextFile(title string, defaultFilename string, content string)
 
 // ExportLocaleFile 將指定語系的 global.ini 匯出到目標路徑
 func (a *App) ExportLocaleFile(scPath string, localeName string, destFile string) error {
-	if strings.TrimSpace(localeName) == "" {
-		return fmt.Errorf("invalid locale name")
-	}
-	if strings.TrimSpace(destFile) == "" {
-		return fmt.Errorf("invalid destination path")
-	}
-
-	src := filepath.Join(getLocalLocalizationBase(), localeName, "global.ini")
-	if src == "" {
-		return fmt.Errorf("global.ini not found for locale: %s", localeName)
-	}
-
-	data, err := os.ReadFile(src)
-	if err != nil {
-		return fmt.Errorf("read source failed: %w", err)
-	}
-	if err := os.WriteFile(destFile, data, 0644); err != nil {
-		return fmt.Errorf("write destination failed: %w", err)
-	}
-	return nil
+	return a.locales.Export(localeName, destFile)
 }
 
 // ExportLocaleFileStripped 匯出指定語系的 global.ini，並針對含 vehicle_Name 的鍵移除值前方的 3 碼排序前綴（例如："001 ")
@@ -847,209 +826,47 @@ func (a *App) ExportLocaleFileStripped(scPath string, localeName string, destFil
 		return fmt.Errorf("invalid destination path")
 	}
 
-	// 尋找來源檔案（LIVE / PTU / EPTU）
-	src := filepath.Join(getLocalLocalizationBase(), localeName, "global.ini")
-	if src == "" {
-		return fmt.Errorf("global.ini not found for locale: %s", localeName)
-	}
-
 	// 讀取並解析來源 INI
-	items, err := a.ReadINIFile(src)
+	doc, err := ini.Load(a.locales.Path(localeName))
 	if err != nil {
 		return fmt.Errorf("read source failed: %w", err)
 	}
 
-	// 從 Sort/active.json 取得目前使用中的 baseKeys 清單
-	activeSet := map[string]struct{}{}
-	{
-		baseSort := a.GetSortBasePath(scPath)
-		if baseSort != "" {
-			activePath := filepath.Join(baseSort, "active.json")
-			if data, err := os.ReadFile(activePath); err == nil {
-				var vo VehicleOrder
-				if json.Unmarshal(data, &vo) == nil && vo.Type == "vehicle_order" && vo.Version > 0 && len(vo.BaseKeys) > 0 {
-					for _, k := range vo.BaseKeys {
-						activeSet[k] = struct{}{}
-					}
-				}
-			}
-		}
-	}
-
 	// 只有 baseKey 在 active.json 的 vehicle_Name 項目才移除排序前綴
-	cleaned := make([]INIKeyValue, 0, len(items))
-	for _, it := range items {
-		if strings.Contains(strings.ToLower(it.Key), "vehicle_name") {
-			// 計算 baseKey 與前端一致的規則：_short,p -> ",P"；_short -> 去除後綴；其他維持
-			keyLower := strings.ToLower(it.Key)
-			baseKey := it.Key
-			if strings.HasSuffix(keyLower, "_short,p") {
-				baseKey = it.Key[:len(it.Key)-len("_short,p")] + ",P"
-			} else if strings.HasSuffix(keyLower, "_short") {
-				baseKey = it.Key[:len(it.Key)-len("_short")]
-			}
-
-			if _, ok := activeSet[baseKey]; ok {
-				v := it.Value
-				if len(v) >= 4 {
-					if v[0] >= '0' && v[0] <= '9' && v[1] >= '0' && v[1] <= '9' && v[2] >= '0' && v[2] <= '9' && (v[3] == ' ' || v[3] == '\t') {
-						v = v[4:]
-					}
-				}
-				cleaned = append(cleaned, INIKeyValue{Key: it.Key, Value: v})
-				continue
-			}
-		}
-		cleaned = append(cleaned, it)
-	}
+	vehicleorder.Strip(doc, a.orders.ActiveSet())
 
-	// 使用既定格式（Windows CRLF + UTF-8 BOM）寫出到目的檔案
-	if err := writeINIWithFormat(destFile, cleaned, "\r\n", true); err != nil {
-		return err
-	}
-	return nil
+	// 沿用來源檔案的格式寫出到目的檔案
+	return a.writeINIDocument(destFile, doc)
 }
 
-// GetSortBasePath 回傳與 Localization 同層的 Sort 目錄路徑（優先回傳存在的版本目錄）
+// GetSortBasePath 回傳 Sort 目錄路徑（存放於使用者本機資料夾）
 func (a *App) GetSortBasePath(scPath string) string {
-	// 改存放於使用者本機資料夾，避免寫入 Program Files 需提權
-	base := getLocalDataBase()
-	return filepath.Join(base, "Sort")
+	return a.orders.Base
 }
 
 // EnsureSortDirs 確保 Sort 與 Sort/save 目錄存在
 func (a *App) EnsureSortDirs(scPath string) (string, string, error) {
-	base := a.GetSortBasePath(scPath)
-	if err := os.MkdirAll(base, 0755); err != nil {
-		return "", "", err
-	}
-	save := filepath.Join(base, "save")
-	if err := os.MkdirAll(save, 0755); err != nil {
-		return "", "", err
-	}
-	// 嘗試從舊位置遷移（僅第一次，若新位置為空）
-	_ = migrateSortDataIfNeeded(scPath, base)
-	return base, save, nil
-}
-
-type VehicleOrder struct {
-	Type     string   `json:"type"`
-	Version  int      `json:"version"`
-	BaseKeys []string `json:"baseKeys"`
+	return a.orders.EnsureDirs(scPath)
 }
 
 // SaveVehicleOrderActive 寫入 active.json（不建立時機由前端控制）
 func (a *App) SaveVehicleOrderActive(scPath string, baseKeys []string) (string, error) {
-	base, _, err := a.EnsureSortDirs(scPath)
-	if err != nil {
-		return "", err
-	}
-	vo := VehicleOrder{Type: "vehicle_order", Version: 1, BaseKeys: baseKeys}
-	data, err := json.MarshalIndent(vo, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	dest := filepath.Join(base, "active.json")
-	if err := os.WriteFile(dest, data, 0644); err != nil {
-		return "", err
-	}
-	return dest, nil
+	return a.orders.SaveActive(scPath, baseKeys)
 }
 
 // SaveVehicleOrderAs 另存新檔到 save 目錄，回傳完整路徑
 func (a *App) SaveVehicleOrderAs(scPath string, name string, baseKeys []string) (string, error) {
-	if strings.TrimSpace(name) == "" {
-		return "", fmt.Errorf("name is required")
-	}
-	// 簡單過濾檔名
-	safe := strings.TrimSpace(name)
-	safe = strings.ReplaceAll(safe, "\\", "_")
-	safe = strings.ReplaceAll(safe, "/", "_")
-	safe = strings.ReplaceAll(safe, ":", "_")
-	safe = strings.ReplaceAll(safe, "*", "_")
-	safe = strings.ReplaceAll(safe, "?", "_")
-	safe = strings.ReplaceAll(safe, "\"", "_")
-	safe = strings.ReplaceAll(safe, "<", "_")
-	safe = strings.ReplaceAll(safe, ">", "_")
-	safe = strings.ReplaceAll(safe, "|", "_")
-
-	_, saveDir, err := a.EnsureSortDirs(scPath)
-	if err != nil {
-		return "", err
-	}
-	dest := filepath.Join(saveDir, safe+".json")
-	vo := VehicleOrder{Type: "vehicle_order", Version: 1, BaseKeys: baseKeys}
-	data, err := json.MarshalIndent(vo, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	if err := os.WriteFile(dest, data, 0644); err != nil {
-		return "", err
-	}
-	return dest, nil
+	return a.orders.SaveAs(scPath, name, baseKeys)
 }
 
 // GetActiveVehicleOrder 讀取 Sort/active.json 並回傳 BaseKeys（若不存在則回傳空陣列）
 func (a *App) GetActiveVehicleOrder(scPath string) ([]string, error) {
-	base, _, err := a.EnsureSortDirs(scPath)
-	if err != nil {
-		return nil, err
-	}
-	active := filepath.Join(base, "active.json")
-	data, err := os.ReadFile(active)
-	if err != nil {
-		// 不存在或讀取失敗即回傳空
-		return []string{}, nil
-	}
-	var vo VehicleOrder
-	if err := json.Unmarshal(data, &vo); err != nil {
-		return []string{}, nil
-	}
-	if vo.Type != "vehicle_order" || vo.Version <= 0 {
-		return []string{}, nil
-	}
-	return vo.BaseKeys, nil
-}
-
-// getLocaleINIPath 找出特定語系名稱的 global.ini 路徑
-func (a *App) getLocaleINIPath(scPath, localeName string) (string, error) {
-	p := filepath.Join(getLocalLocalizationBase(), localeName, "global.ini")
-	if _, err := os.Stat(p); err == nil {
-		return p, nil
-	}
-	return "", fmt.Errorf("global.ini not found for locale: %s", localeName)
+	return a.orders.Active(scPath)
 }
 
 // GetLocalLocaleINIPath 回傳本機儲存區指定語系的 global.ini 路徑
 func (a *App) GetLocalLocaleINIPath(localeName string) (string, error) {
-	if strings.TrimSpace(localeName) == "" {
-		return "", fmt.Errorf("invalid locale name")
-	}
-	p := filepath.Join(getLocalLocalizationBase(), localeName, "global.ini")
-	if _, err := os.Stat(p); err == nil {
-		return p, nil
-	}
-	return "", fmt.Errorf("local locale ini not found: %s", localeName)
-}
-
-// makeBaseKey 與前端一致：_short,p -> ,P；_short -> 去除；其他維持
-func makeBaseKey(key string) string {
-	kl := strings.ToLower(key)
-	if strings.HasSuffix(kl, "_short,p") {
-		return key[:len(key)-len("_short,p")] + ",P"
-	}
-	if strings.HasSuffix(kl, "_short") {
-		return key[:len(key)-len("_short")]
-	}
-	return key
-}
-
-// stripPrefix 若值為 NNN␠ 開頭則去除
-func stripPrefix(val string) string {
-	if len(val) >= 4 && val[0] >= '0' && val[0] <= '9' && val[1] >= '0' && val[1] <= '9' && val[2] >= '0' && val[2] <= '9' && (val[3] == ' ' || val[3] == '\t') {
-		return val[4:]
-	}
-	return val
+	return a.locales.INIPath(localeName)
 }
 
 // ApplyActiveVehicleOrderToLocale 讀取 active.json，將排序套用到指定語系檔（存在於清單者加 NNN 前綴，其他移除）
@@ -1063,38 +880,16 @@ func (a *App) ApplyActiveVehicleOrderToLocale(scPath, localeName string) error {
 		// 無排序即不動
 		return nil
 	}
-	iniPath, err := a.getLocaleINIPath(scPath, localeName)
+	iniPath, err := a.GetLocalLocaleINIPath(localeName)
 	if err != nil {
 		return err
 	}
-	items, err := a.ReadINIFile(iniPath)
+	doc, err := ini.Load(iniPath)
 	if err != nil {
 		return err
 	}
-
-	orderMap := map[string]int{}
-	for i, k := range baseKeys {
-		orderMap[k] = i + 1
-	}
-
-	newItems := make([]INIKeyValue, 0, len(items))
-	for _, it := range items {
-		if strings.Contains(strings.ToLower(it.Key), "vehicle_name") {
-			base := makeBaseKey(it.Key)
-			clean := stripPrefix(it.Value)
-			if ord, ok := orderMap[base]; ok {
-				// 加前綴
-				v := fmt.Sprintf("%03d %s", ord, clean)
-				newItems = append(newItems, INIKeyValue{Key: it.Key, Value: v})
-				continue
-			}
-			// 其他移除前綴
-			newItems = append(newItems, INIKeyValue{Key: it.Key, Value: clean})
-		} else {
-			newItems = append(newItems, it)
-		}
-	}
-	return writeINIWithFormat(iniPath, newItems, "\r\n", true)
+	vehicleorder.Apply(doc, baseKeys)
+	return a.writeINIDocument(iniPath, doc)
 }
 
 // StripActiveVehicleOrderFromLocale 讀取 active.json，僅對其中 baseKeys 的載具移除前綴
@@ -1106,11 +901,11 @@ func (a *App) StripActiveVehicleOrderFromLocale(scPath, localeName string) error
 	if len(baseKeys) == 0 {
 		return nil
 	}
-	iniPath, err := a.getLocaleINIPath(scPath, localeName)
+	iniPath, err := a.GetLocalLocaleINIPath(localeName)
 	if err != nil {
 		return err
 	}
-	items, err := a.ReadINIFile(iniPath)
+	doc, err := ini.Load(iniPath)
 	if err != nil {
 		return err
 	}
@@ -1118,146 +913,36 @@ func (a *App) StripActiveVehicleOrderFromLocale(scPath, localeName string) error
 	for _, k := range baseKeys {
 		set[k] = struct{}{}
 	}
-
-	newItems := make([]INIKeyValue, 0, len(items))
-	for _, it := range items {
-		if strings.Contains(strings.ToLower(it.Key), "vehicle_name") {
-			base := makeBaseKey(it.Key)
-			if _, ok := set[base]; ok {
-				newItems = append(newItems, INIKeyValue{Key: it.Key, Value: stripPrefix(it.Value)})
-				continue
-			}
-		}
-		newItems = append(newItems, it)
-	}
-	return writeINIWithFormat(iniPath, newItems, "\r\n", true)
+	vehicleorder.Strip(doc, set)
+	return a.writeINIDocument(iniPath, doc)
 }
 
 // ListVehicleOrderSaves 列出 save 目錄下的檔名（不含副檔名）
 func (a *App) ListVehicleOrderSaves(scPath string) ([]string, error) {
-	_, saveDir, err := a.EnsureSortDirs(scPath)
-	if err != nil {
-		return nil, err
-	}
-	entries, err := os.ReadDir(saveDir)
-	if err != nil {
-		return []string{}, nil
-	}
-	var names []string
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
-		}
-		name := e.Name()
-		if strings.HasSuffix(strings.ToLower(name), ".json") {
-			names = append(names, strings.TrimSuffix(name, filepath.Ext(name)))
-		}
-	}
-	sort.Strings(names)
-	return names, nil
+	return a.orders.List(scPath)
 }
 
 // ExportVehicleOrderFile 將 save/<name>.json 匯出到指定路徑
 func (a *App) ExportVehicleOrderFile(scPath string, name string, destFile string) error {
-	if strings.TrimSpace(name) == "" || strings.TrimSpace(destFile) == "" {
-		return fmt.Errorf("invalid params")
-	}
-	_, saveDir, err := a.EnsureSortDirs(scPath)
-	if err != nil {
-		return err
-	}
-	src := filepath.Join(saveDir, name+".json")
-	data, err := os.ReadFile(src)
-	if err != nil {
-		return err
-	}
-	if err := os.WriteFile(destFile, data, 0644); err != nil {
-		return err
-	}
-	return nil
+	return a.orders.Export(scPath, name, destFile)
 }
 
 // ImportVehicleOrderFile 複製外部 JSON 到 save 目錄（使用原檔名）
 func (a *App) ImportVehicleOrderFile(scPath string, sourceFilePath string) (string, error) {
-	if strings.TrimSpace(sourceFilePath) == "" {
-		return "", fmt.Errorf("source path required")
-	}
-	data, err := os.ReadFile(sourceFilePath)
-	if err != nil {
-		return "", err
-	}
-	// 簡單驗證型別
-	var vo VehicleOrder
-	if err := json.Unmarshal(data, &vo); err != nil {
-		return "", fmt.Errorf("invalid json: %w", err)
-	}
-	if vo.Type != "vehicle_order" || vo.Version <= 0 {
-		return "", fmt.Errorf("unsupported vehicle_order json")
-	}
-	_, saveDir, err := a.EnsureSortDirs(scPath)
-	if err != nil {
-		return "", err
-	}
-	base := filepath.Base(sourceFilePath)
-	if strings.ToLower(filepath.Ext(base)) != ".json" {
-		base = base + ".json"
-	}
-	dest := filepath.Join(saveDir, base)
-	if err := os.WriteFile(dest, data, 0644); err != nil {
-		return "", err
-	}
-	return dest, nil
+	return a.orders.Import(scPath, sourceFilePath)
 }
 
 // SetActiveVehicleOrderByName 以 save/<name>.json 覆蓋 active.json，回傳 BaseKeys
 func (a *App) SetActiveVehicleOrderByName(scPath string, name string) ([]string, error) {
-	if strings.TrimSpace(name) == "" {
-		return nil, fmt.Errorf("name is required")
-	}
-	base, saveDir, err := a.EnsureSortDirs(scPath)
-	if err != nil {
-		return nil, err
-	}
-	src := filepath.Join(saveDir, name+".json")
-	data, err := os.ReadFile(src)
-	if err != nil {
-		return nil, err
-	}
-	var vo VehicleOrder
-	if err := json.Unmarshal(data, &vo); err != nil {
-		return nil, fmt.Errorf("invalid json: %w", err)
-	}
-	if vo.Type != "vehicle_order" || vo.Version <= 0 {
-		return nil, fmt.Errorf("unsupported vehicle_order json")
-	}
-	// 寫入 active.json
-	dest := filepath.Join(base, "active.json")
-	if err := os.WriteFile(dest, data, 0644); err != nil {
-		return nil, err
-	}
-	return vo.BaseKeys, nil
+	return a.orders.Activate(scPath, name)
 }
 
 // DeleteVehicleOrderSave 刪除 save/<name>.json
 func (a *App) DeleteVehicleOrderSave(scPath string, name string) error {
-	if strings.TrimSpace(name) == "" {
-		return fmt.Errorf("name is required")
-	}
-	_, saveDir, err := a.EnsureSortDirs(scPath)
-	if err != nil {
-		return err
-	}
-	target := filepath.Join(saveDir, name+".json")
-	if _, err := os.Stat(target); os.IsNotExist(err) {
-		return nil
-	}
-	if err := os.Remove(target); err != nil {
-		return err
-	}
-	return nil
+	return a.orders.Delete(scPath, name)
 }
 
-// DeleteLocalization 刪除指定語系資料夾（嘗試 LIVE/PTU/EPTU），不存在則跳過
+// DeleteLocalization 刪除本機儲存區的語系資料夾，不存在則跳過
 func (a *App) DeleteLocalization(scPath string, localeName string) error {
 	if strings.TrimSpace(localeName) == "" {
 		return fmt.Errorf("invalid locale name")
@@ -1266,65 +951,7 @@ func (a *App) DeleteLocalization(scPath string, localeName string) error {
 	if current := a.GetUserLanguage(scPath); current != "" && current == localeName {
 		return fmt.Errorf("cannot delete locale currently in use")
 	}
-	// 刪除本機儲存區的語系資料夾
-	dir := filepath.Join(getLocalLocalizationBase(), localeName)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return nil
-	}
-	return os.RemoveAll(dir)
-}
-
-// WriteINIFile 寫入 INI 檔案
-func (a *App) WriteINIFile(filePath string, items []INIKeyValue) error {
-	// 強制使用 Windows CRLF 與 UTF-8 BOM
-	eol, hasBOM := "\r\n", true
-	if err := writeINIWithFormat(filePath, items, eol, hasBOM); err != nil {
-		return err
-	}
-	return nil
-}
-
-// detectFileFormat 嘗試從既有檔案偵測行尾(EOL)與是否含 UTF-8 BOM
-func detectFileFormat(filePath string) (string, bool) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		// 檔案不存在或讀取失敗時，採用 Windows CRLF 與 UTF-8 BOM
-		return "\r\n", true
-	}
-	hasBOM := len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF
-	// 判斷行尾：若含 \r\n 則視為 CRLF，否則預設為 LF
-	content := string(data)
-	eol := "\n"
-	if strings.Contains(content, "\r\n") {
-		eol = "\r\n"
-	}
-	return eol, hasBOM
-}
-
-// writeINIWithFormat 依指定行尾與 BOM 寫入 INI 檔案
-func writeINIWithFormat(filePath string, items []INIKeyValue, eol string, hasBOM bool) error {
-	// 依 Key 文字順序排序（不分大小寫）
-	sorted := make([]INIKeyValue, len(items))
-	copy(sorted, items)
-	sort.Slice(sorted, func(i, j int) bool {
-		ai := strings.ToLower(sorted[i].Key)
-		aj := strings.ToLower(sorted[j].Key)
-		return ai < aj
-	})
-
-	var lines []string
-	for _, item := range sorted {
-		lines = append(lines, fmt.Sprintf("%s=%s", item.Key, item.Value))
-	}
-	contentStr := strings.Join(lines, eol) + eol
-	content := []byte(contentStr)
-	if hasBOM {
-		content = append([]byte{0xEF, 0xBB, 0xBF}, content...)
-	}
-	if err := os.WriteFile(filePath, content, 0644); err != nil {
-		return fmt.Errorf("write file failed: %w", err)
-	}
-	return nil
+	return a.locales.Delete(localeName)
 }
 
 // ImportLocaleFile 匯入語系檔案到指定的語系名稱資料夾
@@ -1340,121 +967,245 @@ func (a *App) ImportLocaleFile(scPath, localeName, sourceFilePath string) error
 	if _, err := os.Stat(sourceFilePath); os.IsNotExist(err) {
 		return fmt.Errorf("source file does not exist: %s", sourceFilePath)
 	}
-	// 寫入本機儲存區
-	base := getLocalLocalizationBase()
-	targetDir := filepath.Join(base, localeName)
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return err
-	}
-	data, err := os.ReadFile(sourceFilePath)
-	if err != nil {
+	// 偵測編碼（UTF-16 / Big5 / GBK 會轉為 UTF-8）後寫入本機儲存區
+	before := a.locales.Values(localeName)
+	if _, err := a.locales.Import(localeName, sourceFilePath); err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(targetDir, "global.ini"), data, 0644)
+	a.trackFingerprints(localeName, before)
+	// 以目前安裝的遊戲建置作為語系檔的對應版本（讀不到時略過）
+	_, _ = a.RecordLocaleGameBuild(scPath, localeName)
+	return nil
 }
 
 // SaveLocalLocaleFromFile 將來源 global.ini 複製到本機儲存區的指定語系資料夾
 func (a *App) SaveLocalLocaleFromFile(localeName string, sourceFilePath string) (string, error) {
-	if strings.TrimSpace(localeName) == "" {
-		return "", fmt.Errorf("locale name is required")
+	before := a.locales.Values(localeName)
+	dest, err := a.locales.Import(localeName, sourceFilePath)
+	if err != nil {
+		return "", err
 	}
-	if strings.TrimSpace(sourceFilePath) == "" {
-		return "", fmt.Errorf("source file path is required")
+	a.trackFingerprints(localeName, before)
+	// 以已保存路徑的遊戲建置作為語系檔的對應版本（讀不到時略過）
+	if scPath := a.GetSavedStarCitizenPath(); scPath != "" {
+		_, _ = a.RecordLocaleGameBuild(scPath, localeName)
 	}
-	if _, err := os.Stat(sourceFilePath); err != nil {
-		return "", err
+	return dest, nil
+}
+
+// ListLocaleLayers 列出語系的圖層（依套用順序，不含基底 global.ini）
+func (a *App) ListLocaleLayers(localeName string) (localestore.LayerStack, error) {
+	return a.locales.Layers(localeName)
+}
+
+// ImportLocaleLayer 匯入圖層檔案；kind 為 patch（團隊修正，預設）或 override（個人覆寫）
+func (a *App) ImportLocaleLayer(localeName, layerName, kind, sourceFilePath string) (localestore.Layer, error) {
+	if _, err := a.locales.INIPath(localeName); err != nil {
+		return localestore.Layer{}, err
 	}
-	base := getLocalLocalizationBase()
-	targetDir := filepath.Join(base, localeName)
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return "", err
+	before := a.locales.Values(localeName)
+	layer, err := a.locales.ImportLayer(localeName, layerName, kind, sourceFilePath)
+	if err != nil {
+		return layer, err
+	}
+	a.trackFingerprints(localeName, before)
+	return layer, nil
+}
+
+// SetLocaleLayerEnabled 啟用或停用圖層
+func (a *App) SetLocaleLayerEnabled(localeName, layerName string, enabled bool) error {
+	return a.locales.SetLayerEnabled(localeName, layerName, enabled)
+}
+
+// ReorderLocaleLayers 依 names 重新排列圖層（個人覆寫一律在團隊修正之後）
+func (a *App) ReorderLocaleLayers(localeName string, names []string) error {
+	return a.locales.ReorderLayers(localeName, names)
+}
+
+// DeleteLocaleLayer 刪除圖層
+func (a *App) DeleteLocaleLayer(localeName, layerName string) error {
+	return a.locales.DeleteLayer(localeName, layerName)
+}
+
+// GetLocaleOverrides 讀取個人覆寫圖層的項目
+func (a *App) GetLocaleOverrides(localeName string) ([]ini.KeyValue, error) {
+	return a.locales.Overrides(localeName)
+}
+
+// GetLocaleNameOfPath 回傳路徑所屬的本機語系名稱；不是本機儲存區中的語系檔時回傳空字串
+func (a *App) GetLocaleNameOfPath(filePath string) string {
+	localeName, _ := a.locales.LocaleOf(filePath)
+	return localeName
+}
+
+// ReadLocalLocaleComposed 讀取語系目前生效的內容（基底疊加啟用的圖層），供編輯器顯示
+func (a *App) ReadLocalLocaleComposed(localeName string) ([]ini.KeyValue, error) {
+	doc, err := a.locales.Compose(localeName)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Entries(), nil
+}
+
+// SaveLocaleOverrides 將編輯器的修改存入個人覆寫圖層，更新基底譯文時不會遺失；removeKeys 中的鍵回復為基底的值
+func (a *App) SaveLocaleOverrides(localeName string, items []ini.KeyValue, removeKeys []string) error {
+	before := a.locales.Values(localeName)
+	if err := a.locales.SetOverrides(localeName, items, removeKeys); err != nil {
+		return err
 	}
-	dest := filepath.Join(targetDir, "global.ini")
-	data, err := os.ReadFile(sourceFilePath)
+	a.trackFingerprints(localeName, before)
+	return nil
+}
+
+// ListLocaleSnapshots 列出語系檔的快照（新到舊）；匯入、編輯、修改圖層與還原前都會自動建立快照
+func (a *App) ListLocaleSnapshots(localeName string) ([]localestore.Snapshot, error) {
+	return a.locales.Snapshots(localeName)
+}
+
+// CreateLocaleSnapshot 手動替語系檔目前的內容建立快照；與最新快照相同時不建立並回傳 nil
+func (a *App) CreateLocaleSnapshot(localeName string) (*localestore.Snapshot, error) {
+	if _, err := a.locales.INIPath(localeName); err != nil {
+		return nil, err
+	}
+	return a.locales.Snapshot(localeName, "manual")
+}
+
+// DiffLocaleSnapshot 比對快照與目前的語系檔（含啟用的圖層）
+func (a *App) DiffLocaleSnapshot(localeName, snapshotID string) (localestore.SnapshotDiff, error) {
+	return a.locales.DiffSnapshot(localeName, snapshotID)
+}
+
+// RestoreLocaleSnapshot 以快照取代目前的語系檔與圖層（目前內容會先存成快照）
+func (a *App) RestoreLocaleSnapshot(localeName, snapshotID string) error {
+	return a.locales.RestoreSnapshot(localeName, snapshotID)
+}
+
+// ApplyLocalLocaleToGame 將本機儲存區的語系檔套用到遊戲資料夾的目前版本（需要提權）
+// 安裝前會檢查佔位符與標記，有錯誤時中止安裝；語系記錄的遊戲版本與目前不同時回傳的 Warning 有值
+func (a *App) ApplyLocalLocaleToGame(scPath, localeName string) (localestore.BuildCheck, error) {
+	return a.applyLocalLocaleToGame(scPath, a.GetGameChannel(), localeName, true, false)
+}
+
+// ApplyLocalLocaleToGameSkipValidation 同 ApplyLocalLocaleToGame，但略過佔位符檢查
+func (a *App) ApplyLocalLocaleToGameSkipValidation(scPath, localeName string) (localestore.BuildCheck, error) {
+	return a.applyLocalLocaleToGame(scPath, a.GetGameChannel(), localeName, false, false)
+}
+
+// ApplyLocalLocaleToGameForChannel 同 ApplyLocalLocaleToGame，安裝到指定版本
+func (a *App) ApplyLocalLocaleToGameForChannel(scPath, channel, localeName string, skipValidation bool) (localestore.BuildCheck, error) {
+	return a.applyLocalLocaleToGame(scPath, channel, localeName, !skipValidation, false)
+}
+
+// ApplyLocalLocaleAndSetLanguage 將本機語系檔套用到目前版本，並同時將 system.cfg / user.cfg 的語系設為該語系
+// 兩者在同一次提權中完成，只會跳出一次 UAC；回傳 system.cfg 的路徑
+func (a *App) ApplyLocalLocaleAndSetLanguage(scPath, localeName string) (string, error) {
+	return a.ApplyLocalLocaleAndSetLanguageForChannel(scPath, a.GetGameChannel(), localeName)
+}
+
+// ApplyLocalLocaleAndSetLanguageForChannel 同 ApplyLocalLocaleAndSetLanguage，安裝到指定版本
+func (a *App) ApplyLocalLocaleAndSetLanguageForChannel(scPath, channel, localeName string) (string, error) {
+	ch, err := gameinstall.NormalizeChannel(channel)
 	if err != nil {
 		return "", err
 	}
-	if err := os.WriteFile(dest, data, 0644); err != nil {
+	if _, err := a.applyLocalLocaleToGame(scPath, ch, localeName, true, true); err != nil {
 		return "", err
 	}
-	return dest, nil
+	return filepath.Join(gameinstall.DataDir(scPath, ch), "system.cfg"), nil
 }
 
-// ApplyLocalLocaleToGame 將本機儲存區的語系檔套用到遊戲資料夾（需要提權）
-func (a *App) ApplyLocalLocaleToGame(scPath, localeName string) error {
+// applyLocalLocaleToGame 安裝本機語系檔，回傳語系記錄的遊戲建置與目前版本的比對結果
+// 版本不一致時只提示（locale:build-warning），不中止安裝
+func (a *App) applyLocalLocaleToGame(scPath, channel, localeName string, validate, setLanguage bool) (localestore.BuildCheck, error) {
+	var check localestore.BuildCheck
 	if scPath == "" || !a.ValidateStarCitizenPath(scPath) {
-		return fmt.Errorf("invalid Star Citizen path")
+		return check, fmt.Errorf("invalid Star Citizen path")
 	}
-	src := filepath.Join(getLocalLocalizationBase(), localeName, "global.ini")
+	channel, err := gameinstall.NormalizeChannel(channel)
+	if err != nil {
+		return check, err
+	}
+	src := a.locales.Path(localeName)
 	if _, err := os.Stat(src); err != nil {
-		return fmt.Errorf("local locale not found: %s", src)
+		return check, fmt.Errorf("local locale not found: %s", src)
 	}
-	// 先依 active.json 生成已套用排序的暫存檔，再提權拷貝到遊戲資料夾
+	// 讀不到語系資訊時無從比對，照常安裝
+	check, _ = a.checkLocaleGameBuild(scPath, channel, localeName)
+	// 先疊加圖層並依 active.json 套用排序生成暫存檔，再提權拷貝到遊戲資料夾
+	// 沒有圖層也沒有排序時直接套用本機原檔；有圖層卻組合失敗時中止，避免安裝缺少個人修改的檔案
+	installSrc := src
 	orderedPath, err := a.BuildOrderedLocaleToTemp(scPath, localeName)
-	if err == nil && orderedPath != "" {
-		return a.InstallLocaleFromFileElevated(scPath, localeName, orderedPath)
+	switch {
+	case err == nil && orderedPath != "":
+		installSrc = orderedPath
+	case a.locales.HasLayers(localeName):
+		return check, fmt.Errorf("compose locale layers failed: %w", err)
+	}
+	if validate {
+		report, err := ini.ValidateFile(installSrc, a.findEnglishReference(scPath, channel))
+		if err != nil {
+			return check, err
+		}
+		if err := report.GateError(); err != nil {
+			return check, err
+		}
+	}
+	req := installtx.Request{Channel: channel, Locale: localeName, Source: installSrc}
+	if setLanguage {
+		req.Language = localeName
+	}
+	if err := a.reportCopier(installer.RunElevated(scPath, req)); err != nil {
+		return check, err
+	}
+	// 記錄安裝內容，供偵測遊戲更新是否覆蓋語系檔
+	a.updatePatchState(channel, func(st *patchwatch.State, ch string) error {
+		if setLanguage {
+			st.RecordLanguage(ch, localeName)
+		}
+		return st.RecordINI(ch, localeName, installSrc)
+	})
+	// 記錄為目前使用的語系；寫入設定失敗不影響已完成的安裝
+	_ = a.updateSettings(func(s *config.Settings) error {
+		s.ActiveLocale = localeName
+		return nil
+	})
+	if check.Warning != "" {
+		a.emit("locale:build-warning", check)
 	}
-	// 若無排序可套用或產生失敗，直接套用本機原檔
-	return a.InstallLocaleFromFileElevated(scPath, localeName, src)
+	return check, nil
 }
 
-// BuildOrderedLocaleToTemp 讀取本機語系檔，依 active.json 套用載具排序後輸出到本機暫存，回傳檔案路徑
+// BuildOrderedLocaleToTemp 讀取本機語系檔並依序疊加啟用的圖層（團隊修正、個人覆寫），
+// 再依 active.json 套用載具排序後輸出到本機暫存，回傳檔案路徑
 func (a *App) BuildOrderedLocaleToTemp(scPath, localeName string) (string, error) {
 	if strings.TrimSpace(localeName) == "" {
 		return "", fmt.Errorf("invalid locale name")
 	}
 	// 取得排序清單
 	baseKeys, _ := a.GetActiveVehicleOrder(scPath)
-	if len(baseKeys) == 0 {
+	if len(baseKeys) == 0 && !a.locales.HasLayers(localeName) {
 		return "", fmt.Errorf("no active order")
 	}
-	iniPath, err := a.getLocaleINIPath(scPath, localeName)
+	doc, err := a.locales.Compose(localeName)
 	if err != nil {
 		return "", err
 	}
-	items, err := a.ReadINIFile(iniPath)
-	if err != nil {
-		return "", err
-	}
-	orderMap := map[string]int{}
-	for i, k := range baseKeys {
-		orderMap[k] = i + 1
-	}
-	newItems := make([]INIKeyValue, 0, len(items))
-	for _, it := range items {
-		if strings.Contains(strings.ToLower(it.Key), "vehicle_name") {
-			base := makeBaseKey(it.Key)
-			clean := stripPrefix(it.Value)
-			if ord, ok := orderMap[base]; ok {
-				v := fmt.Sprintf("%03d %s", ord, clean)
-				newItems = append(newItems, INIKeyValue{Key: it.Key, Value: v})
-				continue
-			}
-			newItems = append(newItems, INIKeyValue{Key: it.Key, Value: clean})
-		} else {
-			newItems = append(newItems, it)
-		}
+	if len(baseKeys) > 0 {
+		vehicleorder.Apply(doc, baseKeys)
 	}
 	// 輸出到暫存
-	tmpDir := getLocalTmpDir()
+	tmpDir := config.TmpDir()
 	if err := os.MkdirAll(tmpDir, 0755); err != nil {
 		return "", err
 	}
 	out := filepath.Join(tmpDir, fmt.Sprintf("ordered-%s.ini", localeName))
-	if err := writeINIWithFormat(out, newItems, "\r\n", true); err != nil {
+	// 遊戲需要 UTF-8 BOM，安裝用的暫存檔一律使用遊戲格式
+	if err := doc.WriteFile(out, ini.GameFormat); err != nil {
 		return "", err
 	}
 	return out, nil
 }
 
-func getLocalTmpDir() string {
-	base := os.Getenv("LOCALAPPDATA")
-	if base == "" {
-		base = os.TempDir()
-	}
-	return filepath.Join(base, "zh-tool", "tmp")
-}
-
 // DownloadToTemp 下載檔案到使用者本機暫存資料夾，回傳完整路徑
 // 如果是 global.ini 檔案，會檢查檔案完整性（行數應至少 80000 行）
 func (a *App) DownloadToTemp(url string, filename string) (string, error) {
@@ -1465,246 +1216,271 @@ func (a *App) DownloadToTemp(url string, filename string) (string, error) {
 		filename = fmt.Sprintf("download-%d.ini", time.Now().Unix())
 	}
 	// 暫存路徑：%LOCALAPPDATA%/zh-tool/tmp
-	base := os.Getenv("LOCALAPPDATA")
-	if base == "" {
-		// fallback to OS temp
-		base = os.TempDir()
-	}
-	tmpDir := filepath.Join(base, "zh-tool", "tmp")
+	tmpDir := config.TmpDir()
 	if err := os.MkdirAll(tmpDir, 0755); err != nil {
 		return "", err
 	}
 	dest := filepath.Join(tmpDir, filename)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
+	if err := installer.Download(url, dest); err != nil {
 		return "", err
 	}
-	req.Header.Set("User-Agent", "zh-tool/1.0")
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("download failed: status %d", resp.StatusCode)
+	return dest, nil
+}
+
+// InstallLocaleFromFileElevated 以提權方式將來源 global.ini 安裝到目前版本的 data/Localization/<localeName>/global.ini
+// 實作方式：啟動同目錄下的 zh-tool-copier.exe，使用 UAC 提權，並等待拷貝程式結束後回傳其結果
+func (a *App) InstallLocaleFromFileElevated(scPath, localeName, sourceFilePath string) error {
+	return a.InstallLocaleFromFileElevatedForChannel(scPath, a.GetGameChannel(), localeName, sourceFilePath)
+}
+
+// InstallLocaleFromFileElevatedForChannel 同 InstallLocaleFromFileElevated，安裝到指定版本
+func (a *App) InstallLocaleFromFileElevatedForChannel(scPath, channel, localeName, sourceFilePath string) error {
+	if strings.TrimSpace(localeName) == "" {
+		return fmt.Errorf("invalid locale name")
 	}
-	f, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	return a.reportCopier(installer.RunElevated(scPath, installtx.Request{
+		Channel: channel,
+		Locale:  strings.TrimSpace(localeName),
+		Source:  sourceFilePath,
+	}))
+}
+
+// InstallFromManifest 依安裝清單（JSON）一次安裝多個檔案與 cfg 設定；全部成功或全部還原
+func (a *App) InstallFromManifest(scPath, manifestPath string) error {
+	m, err := installtx.LoadManifest(manifestPath)
 	if err != nil {
-		return "", err
+		return err
 	}
-	defer f.Close()
-	if _, err := io.Copy(f, resp.Body); err != nil {
-		return "", err
+	if strings.TrimSpace(m.Channel) == "" {
+		m.Channel = a.GetGameChannel()
 	}
+	return a.reportCopier(installer.ApplyManifestElevated(scPath, m))
+}
 
-	// 如果是 global.ini 檔案，檢查檔案完整性
-	if strings.HasSuffix(strings.ToLower(filename), "global.ini") || strings.Contains(strings.ToLower(url), "global.ini") {
-		lineCount, err := a.countFileLines(dest)
-		if err != nil {
-			// 如果無法讀取檔案，刪除下載的檔案並返回錯誤
-			os.Remove(dest)
-			return "", fmt.Errorf("無法驗證下載檔案完整性: %w", err)
-		}
-		if lineCount < 80000 {
-			// 檔案不完整，刪除下載的檔案並返回錯誤
-			os.Remove(dest)
-			return "", fmt.Errorf("下載的檔案不完整（僅有 %d 行，應至少 80000 行）。請檢查網路連線並重試", lineCount)
-		}
+// reportCopier 將提權拷貝程式的執行結果通知前端（copier:result），回傳原本的錯誤
+// 錯誤包含拷貝程式回報的訊息；使用者拒絕 UAC 或逾時時為 installer.ErrElevationCancelled / ErrElevationTimeout
+func (a *App) reportCopier(res *installtx.Result, err error) error {
+	if res == nil {
+		res = &installtx.Result{Changed: []string{}}
 	}
-
-	return dest, nil
+	if err != nil && res.Error == "" {
+		res.Error = err.Error()
+	}
+	a.emit("copier:result", res)
+	return err
 }
 
-// countFileLines 計算檔案的行數
-func (a *App) countFileLines(filePath string) (int, error) {
-	file, err := os.Open(filePath)
+// updatePatchState 更新遊戲更新偵測的預期狀態；失敗時略過，不影響安裝結果
+func (a *App) updatePatchState(channel string, fn func(st *patchwatch.State, channel string) error) {
+	ch, err := gameinstall.NormalizeChannel(channel)
 	if err != nil {
-		return 0, err
+		return
 	}
-	defer file.Close()
-
-	lineCount := 0
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		lineCount++
+	path := patchwatch.StatePath()
+	st := patchwatch.LoadState(path)
+	if err := fn(st, ch); err != nil {
+		return
 	}
+	_ = st.Save(path)
+}
 
-	if err := scanner.Err(); err != nil {
-		return 0, err
+// GetPatchWatchStatus 檢查各版本已套用的語系檔與 g_language 是否被遊戲更新還原
+func (a *App) GetPatchWatchStatus(scPath string) ([]patchwatch.Status, error) {
+	if scPath == "" || !a.ValidateStarCitizenPath(scPath) {
+		return nil, fmt.Errorf("invalid Star Citizen path")
 	}
-
-	return lineCount, nil
+	return patchwatch.CheckAll(scPath, patchwatch.LoadState(patchwatch.StatePath())), nil
 }
 
-// InstallLocaleFromFileElevated 以提權方式將來源 global.ini 安裝到 LIVE/data/Localization/<localeName>/global.ini
-// 實作方式：啟動同目錄下的 zh-tool-copier.exe，使用 UAC 提權（PowerShell Start-Process -Verb RunAs）
-func (a *App) InstallLocaleFromFileElevated(scPath, localeName, sourceFilePath string) error {
-	// 僅支援 Windows
-	if runtime.GOOS != "windows" {
-		return fmt.Errorf("elevated install only supported on Windows")
+// ReapplyLocalization 重新套用被遊戲更新還原的語系檔與 g_language；channel 為空時處理所有被還原的版本
+func (a *App) ReapplyLocalization(scPath, channel string) error {
+	statuses, err := a.GetPatchWatchStatus(scPath)
+	if err != nil {
+		return err
 	}
-	if scPath == "" || !a.ValidateStarCitizenPath(scPath) {
-		return fmt.Errorf("invalid Star Citizen path")
+	if strings.TrimSpace(channel) != "" {
+		if channel, err = gameinstall.NormalizeChannel(channel); err != nil {
+			return err
+		}
 	}
-	if strings.TrimSpace(localeName) == "" {
-		return fmt.Errorf("invalid locale name")
+	for _, s := range statuses {
+		if channel != "" && s.Expected.Channel != channel {
+			continue
+		}
+		if err := a.reapplyStatus(scPath, s); err != nil {
+			return err
+		}
 	}
-	if sourceFilePath == "" {
-		return fmt.Errorf("source file path is required")
+	return nil
+}
+
+// reapplyStatus 依檢查結果重新套用單一版本
+func (a *App) reapplyStatus(scPath string, s patchwatch.Status) error {
+	exp := s.Expected
+	// 語系設定與語系檔相同時在同一次提權中一併重新套用
+	languageDone := false
+	if s.INIReverted {
+		languageDone = s.LanguageReverted && exp.Language == exp.Locale
+		if _, err := a.applyLocalLocaleToGame(scPath, exp.Channel, exp.Locale, true, languageDone); err != nil {
+			return fmt.Errorf("reapply %s %s failed: %w", exp.Channel, exp.Locale, err)
+		}
 	}
-	if st, err := os.Stat(sourceFilePath); err != nil || st.IsDir() {
-		return fmt.Errorf("source file not found: %s", sourceFilePath)
+	if s.LanguageReverted && !languageDone {
+		if _, err := a.SetUserLanguageForChannel(scPath, exp.Channel, exp.Language); err != nil {
+			return fmt.Errorf("reapply %s g_language failed: %w", exp.Channel, err)
+		}
 	}
+	return nil
+}
 
-	exePath, err := os.Executable()
+// StartPatchWatcher 開始定期檢查遊戲更新是否還原了中文化（設定會保存，下次啟動自動恢復）
+// autoReapply 為 true 時直接重新套用，否則詢問使用者
+func (a *App) StartPatchWatcher(scPath string, autoReapply bool) error {
+	if scPath == "" || !a.ValidateStarCitizenPath(scPath) {
+		return fmt.Errorf("invalid Star Citizen path")
+	}
+	err := a.updateSettings(func(s *config.Settings) error {
+		s.Updates.WatchGamePatches = true
+		s.Updates.AutoReapply = autoReapply
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("cannot resolve executable path: %w", err)
-	}
-	helper := filepath.Join(filepath.Dir(exePath), "zh-tool-copier.exe")
-	if _, err := os.Stat(helper); err != nil {
-		return fmt.Errorf("helper not found: %s", helper)
-	}
-
-	args := []string{
-		"--game", scPath,
-		"--source", sourceFilePath,
-		"--locale", strings.TrimSpace(localeName),
-	}
-	param := windowsJoinArgs(args)
-	cwd := filepath.Dir(helper)
-
-	modShell32 := syscall.NewLazyDLL("shell32.dll")
-	procShellExecuteW := modShell32.NewProc("ShellExecuteW")
-	verb := syscall.StringToUTF16Ptr("runas")
-	file := syscall.StringToUTF16Ptr(helper)
-	parameters := syscall.StringToUTF16Ptr(param)
-	directory := syscall.StringToUTF16Ptr(cwd)
-	showCmd := uintptr(0) // SW_HIDE
-
-	r, _, e := procShellExecuteW.Call(0,
-		uintptr(unsafe.Pointer(verb)),
-		uintptr(unsafe.Pointer(file)),
-		uintptr(unsafe.Pointer(parameters)),
-		uintptr(unsafe.Pointer(directory)),
-		showCmd,
-	)
-	if r <= 32 {
-		return fmt.Errorf("ShellExecuteW failed: %v (ret=%d)", e, r)
+		return err
 	}
+	a.startPatchWatcher(scPath, autoReapply)
 	return nil
 }
 
-// getLocalDataBase 回傳使用者本機資料目錄：%LOCALAPPDATA%\Squadron978\zh-tool
-func getLocalDataBase() string {
-	base := os.Getenv("LOCALAPPDATA")
-	if base == "" {
-		if home, err := os.UserHomeDir(); err == nil {
-			base = filepath.Join(home, "AppData", "Local")
-		} else {
-			base = os.TempDir()
-		}
-	}
-	return filepath.Join(base, "Squadron978", "zh-tool")
+// StopPatchWatcher 停止檢查遊戲更新
+func (a *App) StopPatchWatcher() error {
+	a.stopPatchWatcher()
+	return a.updateSettings(func(s *config.Settings) error {
+		s.Updates.WatchGamePatches = false
+		s.Updates.AutoReapply = false
+		return nil
+	})
 }
 
-// getLocalLocalizationBase 回傳本機語系檔根目錄：%LOCALAPPDATA%\Squadron978\zh-tool\Localization
-func getLocalLocalizationBase() string {
-	return filepath.Join(getLocalDataBase(), "Localization")
+// IsPatchWatcherRunning 是否正在檢查遊戲更新
+func (a *App) IsPatchWatcherRunning() bool {
+	a.watchMu.Lock()
+	defer a.watchMu.Unlock()
+	return a.watchCancel != nil
 }
 
-// migrateSortDataIfNeeded 若新路徑下無 active.json/save，嘗試從舊的遊戲資料夾位置搬移
-func migrateSortDataIfNeeded(scPath string, newBase string) error {
-	// 新位置已有資料則不動
-	newActive := filepath.Join(newBase, "active.json")
-	newSave := filepath.Join(newBase, "save")
-	if _, err := os.Stat(newActive); err == nil {
-		return nil
+// patchWatchSettings 讀取 config.json 的遊戲更新檢查設定
+func (a *App) patchWatchSettings() (enabled, autoReapply bool) {
+	u := a.settings().Updates
+	return u.WatchGamePatches, u.AutoReapply
+}
+
+// patchWatchInterval 檢查間隔；遊戲更新通常持續數分鐘，每分鐘檢查一次即可
+const patchWatchInterval = time.Minute
+
+func (a *App) startPatchWatcher(scPath string, autoReapply bool) {
+	a.stopPatchWatcher()
+	parent := a.ctx
+	if parent == nil {
+		parent = context.Background()
 	}
-	if entries, err := os.ReadDir(newSave); err == nil && len(entries) > 0 {
-		return nil
+	ctx, cancel := context.WithCancel(parent)
+	a.watchMu.Lock()
+	a.watchCancel = cancel
+	a.watchMu.Unlock()
+
+	w := &patchwatch.Watcher{
+		Interval: patchWatchInterval,
+		Settle:   2,
+		Check: func() ([]patchwatch.Status, error) {
+			return a.GetPatchWatchStatus(scPath)
+		},
+		OnRevert: func(s patchwatch.Status) {
+			a.emit("patchwatch:reverted", s)
+			if !autoReapply && !a.confirmReapply(s) {
+				return
+			}
+			if err := a.reapplyStatus(scPath, s); err != nil {
+				a.emit("patchwatch:error", err.Error())
+				return
+			}
+			a.emit("patchwatch:reapplied", s.Expected)
+		},
+		OnError: func(err error) {
+			a.emit("patchwatch:error", err.Error())
+		},
 	}
+	go w.Run(ctx)
+}
 
-	// 舊位置候選：<scPath>/<LIVE|PTU|EPTU>/data/Sort
-	if scPath == "" {
-		return nil
+func (a *App) stopPatchWatcher() {
+	a.watchMu.Lock()
+	defer a.watchMu.Unlock()
+	if a.watchCancel != nil {
+		a.watchCancel()
+		a.watchCancel = nil
 	}
-	candidates := []string{"LIVE", "PTU", "EPTU"}
-	var oldBase string
-	for _, vf := range candidates {
-		b := filepath.Join(scPath, vf, "data", "Sort")
-		if _, err := os.Stat(b); err == nil {
-			oldBase = b
-			break
-		}
+}
+
+// confirmReapply 詢問使用者是否重新套用
+func (a *App) confirmReapply(s patchwatch.Status) bool {
+	if a.ctx == nil {
+		return false
 	}
-	if oldBase == "" {
-		return nil
+	var what []string
+	if s.INIReverted {
+		what = append(what, "語系檔 "+s.Expected.Locale)
+	}
+	if s.LanguageReverted {
+		what = append(what, "語系設定 g_language="+s.Expected.Language)
 	}
+	answer, err := wailsRuntime.MessageDialog(a.ctx, wailsRuntime.MessageDialogOptions{
+		Type:    wailsRuntime.QuestionDialog,
+		Title:   "偵測到遊戲更新",
+		Message: fmt.Sprintf("%s 的%s已被遊戲更新還原，是否重新套用？", s.Expected.Channel, strings.Join(what, "與")),
+	})
+	return err == nil && strings.EqualFold(answer, "Yes")
+}
+
+// driveScanOptions 依掃描設定建立掃描選項，進度以 drivescan:progress 事件送出
+func (a *App) driveScanOptions() gameinstall.ScanOptions {
+	s := a.GetDriveScanSettings()
+	return gameinstall.ScanOptions{
+		Depth:        s.Depth,
+		DriveTimeout: time.Duration(s.TimeoutSeconds) * time.Second,
+		Roots:        s.LibraryFolders,
+		Progress: func(p gameinstall.ScanProgress) {
+			a.emit("drivescan:progress", p)
+		},
+	}
+}
 
-	// 搬移 active.json 與 save/*.json
-	_ = os.MkdirAll(newSave, 0755)
-	if data, err := os.ReadFile(filepath.Join(oldBase, "active.json")); err == nil {
-		_ = os.WriteFile(newActive, data, 0644)
+// beginDriveScan 開始一次可由 CancelDriveScan 取消的磁碟機掃描；結束時呼叫 done
+func (a *App) beginDriveScan() (context.Context, func()) {
+	parent := a.ctx
+	if parent == nil {
+		parent = context.Background()
 	}
-	if entries, err := os.ReadDir(filepath.Join(oldBase, "save")); err == nil {
-		for _, e := range entries {
-			if e.IsDir() {
-				continue
-			}
-			name := e.Name()
-			if strings.HasSuffix(strings.ToLower(name), ".json") {
-				src := filepath.Join(oldBase, "save", name)
-				dst := filepath.Join(newSave, name)
-				if data, err := os.ReadFile(src); err == nil {
-					_ = os.WriteFile(dst, data, 0644)
-				}
-			}
-		}
+	ctx, cancel := context.WithCancel(parent)
+	a.scanMu.Lock()
+	if a.scans == nil {
+		a.scans = map[int]context.CancelFunc{}
+	}
+	a.scanSeq++
+	id := a.scanSeq
+	a.scans[id] = cancel
+	a.scanMu.Unlock()
+
+	return ctx, func() {
+		cancel()
+		a.scanMu.Lock()
+		delete(a.scans, id)
+		a.scanMu.Unlock()
 	}
-	return nil
 }
 
-// windowsJoinArgs 以 Windows 規則組合命令列參數，必要時加上雙引號並跳脫
-func windowsJoinArgs(args []string) string {
-	var b strings.Builder
-	for i, a := range args {
-		needQuote := false
-		for _, r := range a {
-			if r == ' ' || r == '"' || r == '\t' || r == '\n' || r == '\r' {
-				needQuote = true
-				break
-			}
-		}
-		if needQuote {
-			b.WriteByte('"')
-			// 依據 Windows 規則跳脫反斜線與雙引號
-			backslashes := 0
-			for _, ch := range a {
-				if ch == '\\' {
-					backslashes++
-					b.WriteRune(ch)
-					continue
-				}
-				if ch == '"' {
-					b.WriteString(strings.Repeat("\\", backslashes))
-					backslashes = 0
-					b.WriteString("\\\"")
-					continue
-				}
-				backslashes = 0
-				b.WriteRune(ch)
-			}
-			if backslashes > 0 {
-				b.WriteString(strings.Repeat("\\", backslashes))
-			}
-			b.WriteByte('"')
-		} else {
-			b.WriteString(a)
-		}
-		if i != len(args)-1 {
-			b.WriteByte(' ')
-		}
+// emit 送出事件給前端（命令列模式沒有前端時略過）
+func (a *App) emit(name string, data interface{}) {
+	if a.ctx != nil {
+		wailsRuntime.EventsEmit(a.ctx, name, data)
 	}
-	return b.String()
 }
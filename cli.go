@@ -0,0 +1,901 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"zh-tool/pkg/config"
+	"zh-tool/pkg/gameinstall"
+	"zh-tool/pkg/ini"
+	"zh-tool/pkg/localestore"
+	"zh-tool/pkg/patchwatch"
+)
+
+// 命令列模式的結束代碼
+const (
+	cliExitOK      = 0
+	cliExitFailed  = 1 // 執行失敗或驗證未通過
+	cliExitUsage   = 2 // 參數錯誤
+	cliUsageHeader = "用法: zh-tool <command> [options]\n"
+)
+
+// cliOutcome 子命令的執行結果：data 供 --json 輸出，text 供人工閱讀
+type cliOutcome struct {
+	data   interface{}
+	text   string
+	failed bool
+}
+
+// cliCommand 子命令定義
+type cliCommand struct {
+	summary string
+	run     func(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error)
+}
+
+// cliCommands 所有子命令
+var cliCommands = map[string]cliCommand{
+	"compare":         {"比對語系檔與參考檔（缺少的鍵或過時的譯文）", cliCompare},
+	"merge":           {"合併更新：以參考檔順序套用更新，或以新舊英文三方合併", cliMerge},
+	"validate":        {"檢查譯文的佔位符與標記", cliValidate},
+	"apply-order":     {"將 active.json 的載具排序套用到本機語系檔", cliApplyOrder},
+	"export":          {"匯出本機語系檔", cliExport},
+	"install":         {"將本機語系檔安裝到遊戲資料夾", cliInstall},
+	"apply-manifest":  {"依安裝清單一次安裝多個檔案與 cfg 設定（失敗時全部還原）", cliApplyManifest},
+	"set-language":    {"設定或重設遊戲語系（system.cfg / user.cfg）", cliSetLanguage},
+	"build-info":      {"顯示遊戲建置版本，並比對語系檔記錄的版本", cliBuildInfo},
+	"channels":        {"列出安裝目錄下存在的版本（LIVE / PTU / EPTU / TECH-PREVIEW）", cliChannels},
+	"installs":        {"列出所有找到的安裝，或以 --select 保存要使用的安裝", cliInstalls},
+	"scan":            {"平行掃描磁碟機與自訂遊戲庫資料夾尋找安裝（可設定深度與逾時）", cliScan},
+	"extract-english": {"自遊戲的 Data.p4k 取出英文 global.ini", cliExtractEnglish},
+	"check-patch":     {"檢查遊戲更新是否還原了已套用的語系檔與語系設定", cliCheckPatch},
+	"layers":          {"管理語系的圖層（團隊修正、個人覆寫）", cliLayers},
+	"history":         {"列出、比對或還原本機語系檔的快照", cliHistory},
+	"uninstall":       {"解除安裝：還原原始遊戲檔案並移除語系資料夾", cliUninstall},
+}
+
+// cliUsageError 參數錯誤
+type cliUsageError struct{ msg string }
+
+func (e cliUsageError) Error() string { return e.msg }
+
+// isCLIInvocation 判斷是否以命令列模式啟動（第一個參數為已知子命令或 help）
+func isCLIInvocation(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		return true
+	}
+	_, ok := cliCommands[args[0]]
+	return ok
+}
+
+// runCLI 執行子命令並回傳結束代碼
+// 指定 --json 時所有結果（包含參數錯誤）都以 JSON 輸出到 stdout
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || strings.HasPrefix(args[0], "-") {
+		printCLIUsage(stdout)
+		return cliExitOK
+	}
+	jsonOut := wantsJSON(args)
+	cmd, ok := cliCommands[args[0]]
+	if !ok {
+		msg := "unknown command: " + args[0]
+		if jsonOut {
+			writeCLIJSON(stdout, args[0], false, "error", msg)
+		} else {
+			fmt.Fprintln(stderr, msg)
+			printCLIUsage(stderr)
+		}
+		return cliExitUsage
+	}
+
+	fs := flag.NewFlagSet("zh-tool "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if jsonOut {
+		// 參數錯誤改以 JSON 回報，flag 套件本身的錯誤訊息不輸出
+		fs.SetOutput(io.Discard)
+	}
+	fs.Bool("json", false, "以 JSON 格式輸出結果")
+	out, err := cmd.run(NewApp(), fs, args[1:])
+
+	var usageErr cliUsageError
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		if jsonOut {
+			fs.SetOutput(stderr)
+			fs.Usage()
+		}
+		return cliExitOK
+	case errors.As(err, &usageErr):
+		if jsonOut {
+			writeCLIJSON(stdout, args[0], false, "error", err.Error())
+		} else {
+			fmt.Fprintln(stderr, err.Error())
+			fs.Usage()
+		}
+		return cliExitUsage
+	}
+
+	ok = err == nil && !out.failed
+	switch {
+	case jsonOut && err != nil:
+		writeCLIJSON(stdout, args[0], false, "error", err.Error())
+	case jsonOut:
+		writeCLIJSON(stdout, args[0], ok, "result", out.data)
+	case err != nil:
+		fmt.Fprintln(stderr, "ERROR:", err.Error())
+	case out.text != "":
+		fmt.Fprintln(stdout, strings.TrimRight(out.text, "\n"))
+	}
+
+	if !ok {
+		return cliExitFailed
+	}
+	return cliExitOK
+}
+
+// isJSONFlag 判斷參數是否為 --json（含 --json=true 等寫法）
+func isJSONFlag(arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if len(arg)-len(name) < 1 || len(arg)-len(name) > 2 {
+		return false
+	}
+	if i := strings.Index(name, "="); i >= 0 {
+		v, err := strconv.ParseBool(name[i+1:])
+		return name[:i] == "json" && err == nil && v
+	}
+	return name == "json"
+}
+
+// wantsJSON 在解析參數前判斷是否指定了 --json，讓參數錯誤也能以 JSON 回報
+func wantsJSON(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if isJSONFlag(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCLIJSON 輸出 --json 的結果：key 為 result 或 error
+func writeCLIJSON(w io.Writer, command string, ok bool, key string, value interface{}) {
+	payload := map[string]interface{}{"command": command, "ok": ok, key: value}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(payload)
+}
+
+func printCLIUsage(w io.Writer) {
+	fmt.Fprint(w, cliUsageHeader)
+	names := make([]string, 0, len(cliCommands))
+	for name := range cliCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-13s %s\n", name, cliCommands[name].summary)
+	}
+	fmt.Fprintln(w, "\n所有命令皆支援 --json 以輸出機器可讀的結果；使用 zh-tool <command> -h 查看選項")
+}
+
+// parseFlags 解析子命令的參數；無法解析的參數視為參數錯誤
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return cliUsageError{msg: err.Error()}
+}
+
+// requireFlags 檢查必要參數
+func requireFlags(values map[string]string) error {
+	var missing []string
+	for name, v := range values {
+		if strings.TrimSpace(v) == "" {
+			missing = append(missing, "--"+name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return cliUsageError{msg: "missing required arguments: " + strings.Join(missing, ", ")}
+	}
+	return nil
+}
+
+// resolveGamePath 未指定 --game 時使用已保存或自動偵測的安裝路徑（偵測結果不保存到設定）
+func resolveGamePath(a *App, game string) (string, error) {
+	if strings.TrimSpace(game) != "" {
+		return game, nil
+	}
+	if p := a.detectStarCitizenPath(); p != "" {
+		return p, nil
+	}
+	return "", cliUsageError{msg: "Star Citizen path not found, please specify --game"}
+}
+
+// resolveChannel 未指定 --channel 時使用已保存的版本
+func resolveChannel(a *App, channel string) string {
+	if strings.TrimSpace(channel) != "" {
+		return channel
+	}
+	return a.GetGameChannel()
+}
+
+func cliCompare(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	current := fs.String("current", "", "當前語系檔案")
+	reference := fs.String("reference", "", "參考檔案（stale 模式為英文 global.ini；預設取自遊戲 Data.p4k 的英文 global.ini）")
+	mode := fs.String("mode", "missing", "比對模式：missing（缺少的鍵）| stale（英文已變動的譯文，--current 須為本機儲存區中的語系檔）")
+	game := fs.String("game", "", "未指定 --reference 時讀取 Data.p4k 的安裝根目錄（預設使用已保存的路徑）")
+	channel := fs.String("channel", "", "未指定 --reference 時讀取的版本（預設使用已保存的版本）")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	if err := requireFlags(map[string]string{"current": *current}); err != nil {
+		return cliOutcome{}, err
+	}
+	if *reference == "" {
+		scPath, err := resolveGamePath(a, *game)
+		if err != nil {
+			return cliOutcome{}, err
+		}
+		if *reference, err = a.ExtractEnglishINIFromP4K(scPath, resolveChannel(a, *channel)); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+
+	var b strings.Builder
+	switch *mode {
+	case "missing":
+		res, err := a.CompareINIFilesDetailed(*current, *reference)
+		if err != nil {
+			return cliOutcome{}, err
+		}
+		fmt.Fprintf(&b, "missing %d keys (current %d, reference %d)\n", len(res.Missing), res.CurrentCount, res.ReferenceCount)
+		for _, it := range res.Missing {
+			fmt.Fprintf(&b, "%s=%s\n", it.Key, it.Value)
+		}
+		return cliOutcome{data: res, text: b.String()}, nil
+	case "stale":
+		res, err := a.CompareINIFilesStale(*current, *reference)
+		if err != nil {
+			return cliOutcome{}, err
+		}
+		fmt.Fprintf(&b, "stale %d, current %d, untracked %d\n", len(res.Stale), res.Current, len(res.Untracked))
+		for _, it := range res.Stale {
+			fmt.Fprintf(&b, "%s\t%s\n", it.Key, it.English)
+		}
+		return cliOutcome{data: res, text: b.String()}, nil
+	}
+	return cliOutcome{}, cliUsageError{msg: "invalid --mode: " + *mode}
+}
+
+func cliMerge(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	target := fs.String("target", "", "要更新的譯文檔")
+	reference := fs.String("reference", "", "參考檔案（決定新鍵的插入順序）")
+	updates := fs.String("updates", "", "含更新項目的 INI 檔案")
+	oldEnglish := fs.String("old-english", "", "三方合併：舊版英文 global.ini")
+	newEnglish := fs.String("new-english", "", "三方合併：新版英文 global.ini")
+	out := fs.String("out", "", "三方合併：輸出檔案（預設覆寫 --target）")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+
+	// 三方合併
+	if *oldEnglish != "" || *newEnglish != "" {
+		if err := requireFlags(map[string]string{"target": *target, "old-english": *oldEnglish, "new-english": *newEnglish}); err != nil {
+			return cliOutcome{}, err
+		}
+		report, err := a.MergeEnglishUpdate(*oldEnglish, *newEnglish, *target, *out)
+		if err != nil {
+			return cliOutcome{}, err
+		}
+		text := fmt.Sprintf("merged into %s: added %d, removed %d, changed %d, untranslated %d, unchanged %d",
+			report.OutputPath, len(report.Added), len(report.Removed), len(report.Changed), len(report.Untranslated), report.UnchangedCount)
+		return cliOutcome{data: report, text: text}, nil
+	}
+
+	// 依參考順序套用更新
+	if err := requireFlags(map[string]string{"target": *target, "reference": *reference, "updates": *updates}); err != nil {
+		return cliOutcome{}, err
+	}
+	items, err := a.ReadINIFile(*updates)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	if err := a.UpdateINIFile(*target, *reference, items); err != nil {
+		return cliOutcome{}, err
+	}
+	return cliOutcome{
+		data: map[string]interface{}{"target": *target, "updated": len(items)},
+		text: fmt.Sprintf("updated %d keys in %s", len(items), *target),
+	}, nil
+}
+
+func cliValidate(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	file := fs.String("file", "", "要檢查的譯文檔")
+	locale := fs.String("locale", "", "或指定本機語系名稱")
+	reference := fs.String("reference", "", "英文參考檔（可省略）")
+	game := fs.String("game", "", "Star Citizen 安裝根目錄（搭配 --locale 尋找英文參考檔）")
+	channel := fs.String("channel", "", "搭配 --game 時讀取此版本的英文參考檔（預設使用已保存的版本）")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+
+	var report ini.ValidationReport
+	var err error
+	switch {
+	case *file != "":
+		report, err = a.ValidateTranslation(*file, *reference)
+	case *locale != "":
+		if *reference != "" {
+			var iniPath string
+			if iniPath, err = a.GetLocalLocaleINIPath(*locale); err == nil {
+				report, err = a.ValidateTranslation(iniPath, *reference)
+			}
+		} else {
+			report, err = a.ValidateLocalLocale(*game, *channel, *locale)
+		}
+	default:
+		return cliOutcome{}, cliUsageError{msg: "missing required arguments: --file or --locale"}
+	}
+	if err != nil {
+		return cliOutcome{}, err
+	}
+
+	var b strings.Builder
+	for _, is := range report.Issues {
+		fmt.Fprintf(&b, "%d\t%s\t%s\t%s\t%s\n", is.Line, is.Severity, is.Kind, is.Key, is.Token)
+	}
+	fmt.Fprintf(&b, "checked %d keys: %d errors, %d warnings", report.Checked, report.Errors, report.Warnings)
+	return cliOutcome{data: report, text: b.String(), failed: report.Errors > 0}, nil
+}
+
+func cliApplyOrder(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	locale := fs.String("locale", "", "本機語系名稱")
+	strip := fs.Bool("strip", false, "移除排序前綴而非套用")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	if err := requireFlags(map[string]string{"locale": *locale}); err != nil {
+		return cliOutcome{}, err
+	}
+	// 排序資料存放於本機資料夾，不需要遊戲路徑
+	scPath := a.GetSavedStarCitizenPath()
+	var err error
+	if *strip {
+		err = a.StripActiveVehicleOrderFromLocale(scPath, *locale)
+	} else {
+		err = a.ApplyActiveVehicleOrderToLocale(scPath, *locale)
+	}
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	iniPath, _ := a.GetLocalLocaleINIPath(*locale)
+	return cliOutcome{data: map[string]interface{}{"locale": *locale, "path": iniPath, "stripped": *strip}, text: "updated " + iniPath}, nil
+}
+
+func cliExport(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	locale := fs.String("locale", "", "本機語系名稱")
+	out := fs.String("out", "", "輸出檔案")
+	strip := fs.Bool("strip-order", false, "移除載具排序前綴")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	if err := requireFlags(map[string]string{"locale": *locale, "out": *out}); err != nil {
+		return cliOutcome{}, err
+	}
+	scPath := a.GetSavedStarCitizenPath()
+	var err error
+	if *strip {
+		err = a.ExportLocaleFileStripped(scPath, *locale, *out)
+	} else {
+		err = a.ExportLocaleFile(scPath, *locale, *out)
+	}
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	return cliOutcome{data: map[string]interface{}{"locale": *locale, "path": *out}, text: "exported to " + *out}, nil
+}
+
+func cliInstall(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	game := fs.String("game", "", "Star Citizen 安裝根目錄（預設使用已保存的路徑）")
+	locale := fs.String("locale", "", "本機語系名稱")
+	source := fs.String("source", "", "先將此 global.ini 存入本機語系再安裝（可省略）")
+	skipValidation := fs.Bool("skip-validation", false, "略過安裝前的佔位符檢查")
+	setLanguage := fs.Bool("set-language", false, "同時將 system.cfg / user.cfg 的語系設為此語系（同一次提權）")
+	channel := fs.String("channel", "", "版本：LIVE / PTU / EPTU / TECH-PREVIEW（預設使用已保存的版本）")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	if err := requireFlags(map[string]string{"locale": *locale}); err != nil {
+		return cliOutcome{}, err
+	}
+	scPath, err := resolveGamePath(a, *game)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	ch := resolveChannel(a, *channel)
+	if *source != "" {
+		if _, err := a.SaveLocalLocaleFromFile(*locale, *source); err != nil {
+			return cliOutcome{}, err
+		}
+		_, _ = a.recordLocaleGameBuild(scPath, ch, *locale)
+	}
+	// 語系檔對應的遊戲版本與目前不同時只提示，不中止安裝
+	check, err := a.applyLocalLocaleToGame(scPath, ch, *locale, !*skipValidation, *setLanguage)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	text := "installed " + *locale + " to " + scPath + " (" + ch + ")"
+	if check.Warning != "" {
+		text += "\nWARNING: " + check.Warning
+	}
+	return cliOutcome{
+		data: map[string]interface{}{"game": scPath, "channel": ch, "locale": *locale, "buildCheck": check, "languageSet": *setLanguage},
+		text: text,
+	}, nil
+}
+
+func cliApplyManifest(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	game := fs.String("game", "", "Star Citizen 安裝根目錄（預設使用已保存的路徑）")
+	manifest := fs.String("manifest", "", "安裝清單（JSON）")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	if err := requireFlags(map[string]string{"manifest": *manifest}); err != nil {
+		return cliOutcome{}, err
+	}
+	scPath, err := resolveGamePath(a, *game)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	if err := a.InstallFromManifest(scPath, *manifest); err != nil {
+		return cliOutcome{}, err
+	}
+	return cliOutcome{
+		data: map[string]string{"game": scPath, "manifest": *manifest},
+		text: "applied " + *manifest + " to " + scPath,
+	}, nil
+}
+
+func cliSetLanguage(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	game := fs.String("game", "", "Star Citizen 安裝根目錄（預設使用已保存的路徑）")
+	locale := fs.String("locale", "", "語系名稱，例如 chinese_(traditional)")
+	reset := fs.Bool("reset", false, "重設為原版語系")
+	channel := fs.String("channel", "", "版本：LIVE / PTU / EPTU / TECH-PREVIEW（預設使用已保存的版本）")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	scPath, err := resolveGamePath(a, *game)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	ch := resolveChannel(a, *channel)
+	if *reset {
+		if err := a.ResetToDefaultLanguageForChannel(scPath, ch); err != nil {
+			return cliOutcome{}, err
+		}
+		return cliOutcome{data: map[string]interface{}{"game": scPath, "channel": ch, "reset": true}, text: "language reset to default (" + ch + ")"}, nil
+	}
+	if err := requireFlags(map[string]string{"locale": *locale}); err != nil {
+		return cliOutcome{}, err
+	}
+	cfg, err := a.SetUserLanguageForChannel(scPath, ch, *locale)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	return cliOutcome{data: map[string]interface{}{"game": scPath, "channel": ch, "locale": *locale, "config": cfg}, text: "language set to " + *locale + " (" + cfg + ")"}, nil
+}
+
+func cliChannels(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	game := fs.String("game", "", "Star Citizen 安裝根目錄（預設使用已保存的路徑）")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	scPath, err := resolveGamePath(a, *game)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	channels := a.ListGameChannels(scPath)
+	return cliOutcome{
+		data: map[string]interface{}{"game": scPath, "channels": channels, "selected": a.GetGameChannel()},
+		text: strings.Join(channels, "\n"),
+	}, nil
+}
+
+func cliInstalls(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	selectPath := fs.String("select", "", "保存此安裝根目錄為要使用的安裝")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	if *selectPath != "" {
+		if !a.ValidateStarCitizenPath(*selectPath) {
+			return cliOutcome{}, fmt.Errorf("not a valid Star Citizen path: %s", *selectPath)
+		}
+		if err := a.SaveStarCitizenPath(*selectPath); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+	installs := a.DiscoverStarCitizenInstalls()
+	var b strings.Builder
+	for _, inst := range installs {
+		mark := " "
+		if inst.Preferred {
+			mark = "*"
+		}
+		free := "?"
+		if inst.FreeBytes >= 0 {
+			free = fmt.Sprintf("%.1f GB", float64(inst.FreeBytes)/(1<<30))
+		}
+		fmt.Fprintf(&b, "%s %s (free %s; %s)\n", mark, inst.Path, free, strings.Join(inst.Sources, ", "))
+		for _, ch := range inst.Channels {
+			build := "unknown build"
+			if ch.Build != nil {
+				build = ch.Build.String()
+			}
+			note := ""
+			if ch.NeedsElevation {
+				note = " [needs elevation]"
+			}
+			fmt.Fprintf(&b, "    %-12s %s%s\n", ch.Channel, build, note)
+		}
+	}
+	if len(installs) == 0 {
+		b.WriteString("no Star Citizen installation found\n")
+	}
+	return cliOutcome{data: installs, text: strings.TrimRight(b.String(), "\n")}, nil
+}
+
+func cliScan(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	settings := a.GetDriveScanSettings()
+	depth := fs.Int("depth", settings.Depth, fmt.Sprintf("在遊戲目錄與遊戲庫下往下尋找的層數（0 為預設值 %d，最多 %d）", gameinstall.DefaultScanDepth, gameinstall.MaxScanDepth))
+	timeout := fs.Int("timeout", settings.TimeoutSeconds, fmt.Sprintf("每個磁碟機的時間上限（秒；0 為預設值 %d）", int(gameinstall.DefaultDriveTimeout/time.Second)))
+	library := fs.String("library", strings.Join(settings.LibraryFolders, ","), "額外掃描的遊戲庫資料夾（以逗號分隔；預設使用已保存的設定）")
+	save := fs.Bool("save", false, "將 --depth、--timeout、--library 保存為掃描設定")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	settings = config.DriveScanSettings{Depth: *depth, TimeoutSeconds: *timeout, LibraryFolders: []string{}}
+	for _, p := range strings.Split(*library, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			settings.LibraryFolders = append(settings.LibraryFolders, p)
+		}
+	}
+	if settings.Depth < 0 || settings.Depth > gameinstall.MaxScanDepth || settings.TimeoutSeconds < 0 {
+		return cliOutcome{}, cliUsageError{msg: fmt.Sprintf("--depth must be 0-%d and --timeout cannot be negative", gameinstall.MaxScanDepth)}
+	}
+	if *save {
+		if err := a.SetDriveScanSettings(settings); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+
+	// Ctrl+C 取消掃描，仍輸出已找到的安裝
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	progress := fs.Output()
+	paths, err := gameinstall.Scan(ctx, gameinstall.ScanOptions{
+		Depth:        settings.Depth,
+		DriveTimeout: time.Duration(settings.TimeoutSeconds) * time.Second,
+		Roots:        settings.LibraryFolders,
+		Progress: func(p gameinstall.ScanProgress) {
+			if p.State != gameinstall.ScanStarted {
+				fmt.Fprintf(progress, "[%d/%d] %s: %s\n", p.Done, p.Total, p.Target, p.State)
+			}
+		},
+	})
+	cancelled := errors.Is(err, gameinstall.ErrScanCancelled)
+	if err != nil && !cancelled {
+		return cliOutcome{}, err
+	}
+	text := strings.Join(paths, "\n")
+	if len(paths) == 0 {
+		text = "no Star Citizen installation found"
+	}
+	return cliOutcome{
+		data:   map[string]interface{}{"paths": paths, "cancelled": cancelled, "settings": settings},
+		text:   text,
+		failed: cancelled,
+	}, nil
+}
+
+func cliBuildInfo(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	game := fs.String("game", "", "Star Citizen 安裝根目錄（預設使用已保存的路徑）")
+	channel := fs.String("channel", "", "版本：LIVE / PTU / EPTU / TECH-PREVIEW（預設使用已保存的版本）")
+	locale := fs.String("locale", "", "比對此本機語系記錄的遊戲版本（可省略）")
+	record := fs.Bool("record", false, "將目前的遊戲版本記錄為 --locale 的對應版本")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	scPath, err := resolveGamePath(a, *game)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	ch := resolveChannel(a, *channel)
+	if *locale == "" {
+		if *record {
+			return cliOutcome{}, cliUsageError{msg: "--record requires --locale"}
+		}
+		build, err := a.GetGameBuildInfo(scPath, ch)
+		if err != nil {
+			return cliOutcome{}, err
+		}
+		return cliOutcome{data: build, text: ch + ": " + build.String()}, nil
+	}
+	if *record {
+		if _, err := a.recordLocaleGameBuild(scPath, ch, *locale); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+	check, err := a.checkLocaleGameBuild(scPath, ch, *locale)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	text := fmt.Sprintf("game %s: %s\nlocale %s: %s", ch, check.GameBuild.String(), *locale, check.LocaleBuild.String())
+	if check.Warning != "" {
+		text += "\nWARNING: " + check.Warning
+	}
+	return cliOutcome{data: check, text: text}, nil
+}
+
+func cliExtractEnglish(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	game := fs.String("game", "", "Star Citizen 安裝根目錄（預設使用已保存的路徑）")
+	channel := fs.String("channel", "", "版本：LIVE / PTU / EPTU / TECH-PREVIEW（預設使用已保存的版本）")
+	out := fs.String("out", "", "輸出檔案（預設寫入本機快取並顯示路徑）")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	scPath, err := resolveGamePath(a, *game)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	path, err := a.ExtractEnglishINIFromP4K(scPath, resolveChannel(a, *channel))
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	if *out != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cliOutcome{}, err
+		}
+		if err := os.WriteFile(*out, data, 0644); err != nil {
+			return cliOutcome{}, fmt.Errorf("write output failed: %w", err)
+		}
+		path = *out
+	}
+	return cliOutcome{data: map[string]string{"path": path}, text: path}, nil
+}
+
+func cliCheckPatch(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	game := fs.String("game", "", "Star Citizen 安裝根目錄（預設使用已保存的路徑）")
+	channel := fs.String("channel", "", "只檢查此版本（預設檢查所有已套用過的版本）")
+	reapply := fs.Bool("reapply", false, "被還原時重新套用")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	scPath, err := resolveGamePath(a, *game)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	ch := ""
+	if *channel != "" {
+		if ch, err = gameinstall.NormalizeChannel(*channel); err != nil {
+			return cliOutcome{}, cliUsageError{msg: err.Error()}
+		}
+	}
+	if *reapply {
+		if err := a.ReapplyLocalization(scPath, ch); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+	statuses, err := a.GetPatchWatchStatus(scPath)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+
+	var out cliOutcome
+	var b strings.Builder
+	checked := []patchwatch.Status{}
+	for _, s := range statuses {
+		if ch != "" && s.Expected.Channel != ch {
+			continue
+		}
+		checked = append(checked, s)
+		state := "ok"
+		if s.Reverted {
+			out.failed = true
+			var what []string
+			if s.INIReverted {
+				what = append(what, "global.ini ("+s.Expected.Locale+")")
+			}
+			if s.LanguageReverted {
+				what = append(what, fmt.Sprintf("g_language=%q (expected %s)", s.Language, s.Expected.Language))
+			}
+			state = "REVERTED: " + strings.Join(what, ", ")
+		}
+		fmt.Fprintf(&b, "%s: %s\n", s.Expected.Channel, state)
+	}
+	if len(checked) == 0 {
+		b.WriteString("no localization has been applied yet\n")
+	}
+	out.data = checked
+	out.text = b.String()
+	return out, nil
+}
+
+func cliLayers(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	locale := fs.String("locale", "", "本機語系名稱")
+	importName := fs.String("import", "", "匯入圖層（名稱），需搭配 --source")
+	source := fs.String("source", "", "匯入的圖層檔案")
+	kind := fs.String("kind", "patch", "匯入圖層的種類：patch（團隊修正）| override（個人覆寫）")
+	enable := fs.String("enable", "", "啟用圖層")
+	disable := fs.String("disable", "", "停用圖層")
+	remove := fs.String("delete", "", "刪除圖層")
+	order := fs.String("order", "", "以逗號分隔的圖層套用順序")
+	compose := fs.String("compose", "", "將基底與啟用的圖層組合後輸出到此檔案")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	if err := requireFlags(map[string]string{"locale": *locale}); err != nil {
+		return cliOutcome{}, err
+	}
+	if *importName != "" {
+		if err := requireFlags(map[string]string{"source": *source}); err != nil {
+			return cliOutcome{}, err
+		}
+		if _, err := a.ImportLocaleLayer(*locale, *importName, *kind, *source); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+	if *enable != "" {
+		if err := a.SetLocaleLayerEnabled(*locale, *enable, true); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+	if *disable != "" {
+		if err := a.SetLocaleLayerEnabled(*locale, *disable, false); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+	if *remove != "" {
+		if err := a.DeleteLocaleLayer(*locale, *remove); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+	if *order != "" {
+		var names []string
+		for _, n := range strings.Split(*order, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				names = append(names, n)
+			}
+		}
+		if err := a.ReorderLocaleLayers(*locale, names); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+	if *compose != "" {
+		doc, err := a.locales.Compose(*locale)
+		if err != nil {
+			return cliOutcome{}, err
+		}
+		if err := doc.WriteFile(*compose, ini.GameFormat); err != nil {
+			return cliOutcome{}, err
+		}
+	}
+
+	stack, err := a.ListLocaleLayers(*locale)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "0. %s (base)\n", localestore.INIFileName)
+	for i, l := range stack.Layers {
+		state := "enabled"
+		if !l.Enabled {
+			state = "disabled"
+		}
+		fmt.Fprintf(&b, "%d. %s [%s, %s]\n", i+1, l.Name, l.Kind, state)
+	}
+	if *compose != "" {
+		b.WriteString("composed to " + *compose + "\n")
+	}
+	return cliOutcome{data: stack, text: b.String()}, nil
+}
+
+func cliHistory(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	locale := fs.String("locale", "", "本機語系名稱")
+	diff := fs.String("diff", "", "比對此快照與目前的語系檔")
+	restore := fs.String("restore", "", "以此快照取代目前的語系檔與圖層")
+	snapshot := fs.Bool("snapshot", false, "替目前的語系檔建立快照")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	if err := requireFlags(map[string]string{"locale": *locale}); err != nil {
+		return cliOutcome{}, err
+	}
+
+	var b strings.Builder
+	switch {
+	case *diff != "":
+		d, err := a.DiffLocaleSnapshot(*locale, *diff)
+		if err != nil {
+			return cliOutcome{}, err
+		}
+		fmt.Fprintf(&b, "snapshot %s -> current: added %d, removed %d, changed %d\n", d.Snapshot.ID, len(d.Added), len(d.Removed), len(d.Changed))
+		for _, it := range d.Added {
+			fmt.Fprintf(&b, "+ %s=%s\n", it.Key, it.Value)
+		}
+		for _, it := range d.Removed {
+			fmt.Fprintf(&b, "- %s=%s\n", it.Key, it.Value)
+		}
+		for _, it := range d.Changed {
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", it.Key, it.Snapshot, it.Current)
+		}
+		return cliOutcome{data: d, text: b.String()}, nil
+	case *restore != "":
+		if err := a.RestoreLocaleSnapshot(*locale, *restore); err != nil {
+			return cliOutcome{}, err
+		}
+		return cliOutcome{data: map[string]string{"locale": *locale, "restored": *restore}, text: "restored " + *restore}, nil
+	case *snapshot:
+		sn, err := a.CreateLocaleSnapshot(*locale)
+		if err != nil {
+			return cliOutcome{}, err
+		}
+		if sn == nil {
+			return cliOutcome{data: nil, text: "unchanged since the latest snapshot"}, nil
+		}
+		return cliOutcome{data: sn, text: "created snapshot " + sn.ID}, nil
+	}
+
+	snaps, err := a.ListLocaleSnapshots(*locale)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	for _, sn := range snaps {
+		fmt.Fprintf(&b, "%s\t%s\t%d bytes\t%s\n", sn.ID, sn.Reason, sn.Size, sn.CreatedAt)
+	}
+	if len(snaps) == 0 {
+		b.WriteString("no snapshots\n")
+	}
+	return cliOutcome{data: snaps, text: b.String()}, nil
+}
+
+func cliUninstall(a *App, fs *flag.FlagSet, args []string) (cliOutcome, error) {
+	game := fs.String("game", "", "Star Citizen 安裝根目錄（預設使用已保存的路徑）")
+	locale := fs.String("locale", "", "要移除的遊戲語系資料夾名稱")
+	channel := fs.String("channel", "", "版本：LIVE / PTU / EPTU / TECH-PREVIEW（預設使用已保存的版本）")
+	if err := parseFlags(fs, args); err != nil {
+		return cliOutcome{}, err
+	}
+	if err := requireFlags(map[string]string{"locale": *locale}); err != nil {
+		return cliOutcome{}, err
+	}
+	scPath, err := resolveGamePath(a, *game)
+	if err != nil {
+		return cliOutcome{}, err
+	}
+	ch := resolveChannel(a, *channel)
+	if err := a.UninstallLocalizationForChannel(scPath, ch, *locale); err != nil {
+		return cliOutcome{}, err
+	}
+	return cliOutcome{
+		data: map[string]string{"game": scPath, "channel": ch, "locale": *locale},
+		text: fmt.Sprintf("uninstalled %s from %s", *locale, ch),
+	}, nil
+}
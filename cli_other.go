@@ -0,0 +1,6 @@
+//go:build !windows
+
+package main
+
+// attachParentConsole 非 Windows 平台的命令列程式本來就有主控台
+func attachParentConsole() {}
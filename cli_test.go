@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeCLIFile 在 dir 下建立測試用的檔案
+func writeCLIFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunCLI(t *testing.T) {
+	// 設定與本機儲存區寫到暫存目錄
+	t.Setenv("LOCALAPPDATA", t.TempDir())
+	dir := t.TempDir()
+	good := writeCLIFile(t, dir, "good.ini", "ui_Ok=確定\r\nui_Hello=你好 %s\r\n")
+	broken := writeCLIFile(t, dir, "broken.ini", "ui_Ok=<EM4>確定\r\n")
+	reference := writeCLIFile(t, dir, "reference.ini", "ui_Ok=OK\r\nui_Hello=Hello %s\r\nui_New=New\r\n")
+	missing := filepath.Join(dir, "missing.ini")
+
+	cases := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string // stdout 應包含的字串
+		stderr string // stderr 應包含的字串
+		json   bool   // stdout 應為 JSON，且 stderr 為空
+		ok     bool   // JSON 的 ok 欄位
+	}{
+		{name: "no arguments", args: nil, code: cliExitOK, stdout: "用法"},
+		{name: "help", args: []string{"help"}, code: cliExitOK, stdout: "validate"},
+		{name: "leading flag", args: []string{"--help"}, code: cliExitOK, stdout: "用法"},
+		{name: "unknown command", args: []string{"frobnicate"}, code: cliExitUsage, stderr: "unknown command: frobnicate"},
+		{name: "unknown command json", args: []string{"frobnicate", "--json"}, code: cliExitUsage, stdout: "unknown command", json: true},
+		{name: "command help", args: []string{"validate", "-h"}, code: cliExitOK, stderr: "-file"},
+		{name: "command help json", args: []string{"validate", "-h", "--json"}, code: cliExitOK, stderr: "-file"},
+		{name: "undefined flag", args: []string{"validate", "--bogus"}, code: cliExitUsage, stderr: "flag provided but not defined"},
+		{name: "undefined flag json", args: []string{"validate", "--bogus", "--json"}, code: cliExitUsage, stdout: "flag provided but not defined", json: true},
+		{name: "json before bad flag value", args: []string{"scan", "--json", "--depth", "deep"}, code: cliExitUsage, stdout: "invalid value", json: true},
+		{name: "missing required", args: []string{"validate"}, code: cliExitUsage, stderr: "missing required arguments"},
+		{name: "missing required json", args: []string{"validate", "--json"}, code: cliExitUsage, stdout: "missing required arguments", json: true},
+		{name: "json=false is text", args: []string{"validate", "--json=false"}, code: cliExitUsage, stderr: "missing required arguments"},
+		{name: "invalid mode", args: []string{"compare", "--current", good, "--reference", reference, "--mode", "fuzzy"}, code: cliExitUsage, stderr: "invalid --mode"},
+		{name: "validate passes", args: []string{"validate", "--file", good, "--reference", reference}, code: cliExitOK, stdout: "0 errors"},
+		{name: "validate finds errors", args: []string{"validate", "--file", broken}, code: cliExitFailed, stdout: "1 errors"},
+		{name: "validate finds errors json", args: []string{"validate", "--json", "--file", broken}, code: cliExitFailed, stdout: `"result"`, json: true},
+		{name: "run error", args: []string{"validate", "--file", missing}, code: cliExitFailed, stderr: "ERROR:"},
+		{name: "run error json", args: []string{"validate", "--file", missing, "--json"}, code: cliExitFailed, stdout: `"error"`, json: true},
+		{name: "compare json", args: []string{"compare", "--current", good, "--reference", reference, "--json"}, code: cliExitOK, stdout: "ui_New", json: true, ok: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := runCLI(c.args, &stdout, &stderr)
+			if code != c.code {
+				t.Errorf("exit code = %d, want %d\nstdout: %s\nstderr: %s", code, c.code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), c.stdout) {
+				t.Errorf("stdout missing %q:\n%s", c.stdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), c.stderr) {
+				t.Errorf("stderr missing %q:\n%s", c.stderr, stderr.String())
+			}
+			if !c.json {
+				return
+			}
+			if stderr.Len() != 0 {
+				t.Errorf("stderr not empty with --json:\n%s", stderr.String())
+			}
+			var payload struct {
+				Command string `json:"command"`
+				OK      bool   `json:"ok"`
+			}
+			if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+				t.Fatalf("stdout is not JSON: %v\n%s", err, stdout.String())
+			}
+			if payload.Command != c.args[0] || payload.OK != c.ok {
+				t.Errorf("payload = %+v", payload)
+			}
+		})
+	}
+}
+
+func TestWantsJSON(t *testing.T) {
+	cases := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"validate", "--json"}, true},
+		{[]string{"validate", "-json"}, true},
+		{[]string{"validate", "--json=true"}, true},
+		{[]string{"validate", "--json=false"}, false},
+		{[]string{"validate", "---json"}, false},
+		{[]string{"validate", "--jsonl"}, false},
+		{[]string{"validate", "--", "--json"}, false},
+		{[]string{"validate"}, false},
+	}
+	for _, c := range cases {
+		if got := wantsJSON(c.args); got != c.want {
+			t.Errorf("wantsJSON(%q) = %v, want %v", c.args, got, c.want)
+		}
+	}
+}
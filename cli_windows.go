@@ -0,0 +1,25 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// attachParentConsole GUI 程式預設沒有主控台；以命令列模式啟動時附加到父行程的主控台以輸出結果
+func attachParentConsole() {
+	if _, err := os.Stdout.Stat(); err == nil {
+		// 已重新導向到檔案或管線
+		return
+	}
+	const attachParentProcess = ^uint32(0) // ATTACH_PARENT_PROCESS (DWORD)-1
+	proc := syscall.NewLazyDLL("kernel32.dll").NewProc("AttachConsole")
+	if r, _, _ := proc.Call(uintptr(attachParentProcess)); r == 0 {
+		return
+	}
+	if f, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+		os.Stdout = f
+		os.Stderr = f
+	}
+}
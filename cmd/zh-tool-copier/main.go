@@ -4,72 +4,175 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"zh-tool/pkg/gameinstall"
+	"zh-tool/pkg/installtx"
 )
 
 func main() {
 	gamePath := flag.String("game", "", "Star Citizen 安裝根目錄 (e.g. C:\\Program Files\\Roberts Space Industries\\StarCitizen)")
-	srcFile := flag.String("source", "", "要套用的 global.ini 來源檔案")
-	locale := flag.String("locale", "chinese_(traditional)", "語系資料夾名稱")
+	srcFile := flag.String("source", "", "要套用的 global.ini 來源檔案（可省略，只修改語系設定）")
+	locale := flag.String("locale", "", "語系資料夾名稱（安裝語系檔與解除安裝時必填）")
+	channel := flag.String("channel", "LIVE", "版本資料夾：LIVE / PTU / EPTU / TECH-PREVIEW")
+	setLanguage := flag.String("set-language", "", "同時在 system.cfg / user.cfg 設定 sys_languages 與 g_language")
+	resetLanguage := flag.Bool("reset-language", false, "回復原版語系：刪除 system.cfg 並移除 user.cfg 的語系設定")
+	uninstall := flag.Bool("uninstall", false, "解除安裝：還原原始 global.ini 與 system.cfg / user.cfg，移除語系資料夾")
+	manifest := flag.String("manifest", "", "安裝清單（JSON）：一次安裝多個檔案與 cfg 設定")
+	resultPath := flag.String("result", "", "將執行結果（JSON）寫入此檔案，供主程式讀取")
 	flag.Parse()
 
-	if err := run(*gamePath, *srcFile, *locale); err != nil {
+	var res *installtx.Result
+	var err error
+	switch {
+	case *uninstall:
+		res, err = runUninstall(*gamePath, *channel, *locale)
+	case *manifest != "":
+		res, err = runManifest(*gamePath, *manifest)
+	default:
+		res, err = run(*gamePath, installtx.Request{
+			Channel:       *channel,
+			Locale:        *locale,
+			Source:        *srcFile,
+			Language:      *setLanguage,
+			ResetLanguage: *resetLanguage,
+		})
+	}
+	if *resultPath != "" {
+		res.OK = err == nil
+		if err != nil {
+			res.Error = err.Error()
+		}
+		if werr := installtx.WriteResult(*resultPath, res); werr != nil {
+			_ = writeLog(fmt.Sprintf("write result failed: %v", werr))
+		}
+	}
+	if err != nil {
 		// 盡量寫入本機使用者可寫日誌，便於回報
 		_ = writeLog(fmt.Sprintf("ERROR: %v", err))
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
+	if *uninstall {
+		_ = writeLog("SUCCESS: localization uninstalled")
+		return
+	}
 	_ = writeLog("SUCCESS: localization applied")
 }
 
-func run(gameRoot, source, locale string) error {
-	if strings.TrimSpace(gameRoot) == "" || strings.TrimSpace(source) == "" || strings.TrimSpace(locale) == "" {
-		return errors.New("missing required arguments: --game, --source, --locale")
+// run 在一次執行中安裝語系檔並設定或重設語系（以交易方式，全部成功或全部還原）
+func run(gameRoot string, req installtx.Request) (*installtx.Result, error) {
+	res := &installtx.Result{Channel: req.Channel, Changed: []string{}}
+	if strings.TrimSpace(gameRoot) == "" {
+		return res, errors.New("missing required arguments: --game")
 	}
-	if !validateGamePath(gameRoot) {
-		return fmt.Errorf("invalid game path: %s", gameRoot)
+	if strings.TrimSpace(req.Source) == "" && req.Language == "" && !req.ResetLanguage {
+		return res, errors.New("missing required arguments: --source, --set-language or --reset-language")
+	}
+	if strings.TrimSpace(req.Source) != "" && strings.TrimSpace(req.Locale) == "" {
+		return res, errors.New("missing required arguments: --locale")
+	}
+	channel, err := gameinstall.NormalizeChannel(req.Channel)
+	if err != nil {
+		return res, err
+	}
+	req.Channel = channel
+	res.Channel = channel
+	if req.Source != "" {
+		if st, err := os.Stat(req.Source); err != nil || st.IsDir() {
+			return res, fmt.Errorf("invalid source file: %s", req.Source)
+		}
+	}
+	channelDir, err := openChannel(gameRoot, channel)
+	if err != nil {
+		return res, err
+	}
+	res, err = installtx.Run(channelDir, req)
+	logRecovered(res, channelDir)
+	if err != nil {
+		return res, err
+	}
+	_ = writeLog(fmt.Sprintf("install %s: %s", channel, strings.Join(res.Changed, ", ")))
+	return res, nil
+}
+
+// runManifest 依安裝清單安裝
+func runManifest(gameRoot, manifestPath string) (*installtx.Result, error) {
+	res := &installtx.Result{Changed: []string{}}
+	if strings.TrimSpace(gameRoot) == "" {
+		return res, errors.New("missing required arguments: --game")
+	}
+	m, err := installtx.LoadManifest(manifestPath)
+	if err != nil {
+		return res, err
+	}
+	channel, err := gameinstall.NormalizeChannel(m.Channel)
+	if err != nil {
+		return res, err
+	}
+	res.Channel = channel
+	m.Channel = channel
+	channelDir, err := openChannel(gameRoot, channel)
+	if err != nil {
+		return res, err
 	}
-	if st, err := os.Stat(source); err != nil || st.IsDir() {
-		return fmt.Errorf("invalid source file: %s", source)
+	res, err = installtx.RunManifest(channelDir, m)
+	logRecovered(res, channelDir)
+	if err != nil {
+		return res, err
 	}
+	_ = writeLog(fmt.Sprintf("install %s: %s", channel, strings.Join(res.Changed, ", ")))
+	return res, nil
+}
 
-	// 僅允許 LIVE 版本，降低風險
-	targetDir := filepath.Join(gameRoot, "LIVE", "data", "Localization", locale)
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return fmt.Errorf("mkdir failed: %w", err)
+// openChannel 驗證遊戲路徑與版本資料夾
+// 只寫入已存在的版本資料夾，不替使用者建立新的版本；上次中斷的安裝由 installtx 在執行開始時還原
+func openChannel(gameRoot, channel string) (string, error) {
+	if !validateGamePath(gameRoot) {
+		return "", fmt.Errorf("invalid game path: %s", gameRoot)
+	}
+	channelDir := filepath.Join(gameRoot, channel)
+	if st, err := os.Stat(channelDir); err != nil || !st.IsDir() {
+		return "", fmt.Errorf("channel directory not found: %s", channelDir)
 	}
-	target := filepath.Join(targetDir, "global.ini")
+	return channelDir, nil
+}
 
-	// 備份舊檔（若存在且尚無 .bak）
-	if _, err := os.Stat(target); err == nil {
-		bak := target + ".bak"
-		if _, err := os.Stat(bak); os.IsNotExist(err) {
-			if err := copyFile(target, bak); err != nil {
-				return fmt.Errorf("backup old file failed: %w", err)
-			}
-		}
+// logRecovered 執行前還原了上次中斷的安裝，或將無法讀取的日誌移到一旁時寫入日誌
+func logRecovered(res *installtx.Result, channelDir string) {
+	if res.Recovered {
+		_ = writeLog("rolled back an interrupted install in " + channelDir)
+	}
+	if res.SetAside != "" {
+		_ = writeLog("unreadable install journal moved aside: " + filepath.Join(channelDir, res.SetAside))
 	}
+}
 
-	// 原子替換：寫到 .tmp 再替換
-	tmp := target + ".tmp"
-	if err := copyFile(source, tmp); err != nil {
-		return fmt.Errorf("write temp failed: %w", err)
-	}
-	if err := os.Rename(tmp, target); err != nil {
-		// 若跨磁碟區導致 Rename 失敗，退回以 Copy + Remove 實現
-		if err2 := os.Remove(target); err2 == nil {
-			if err3 := copyFile(source, target); err3 != nil {
-				return fmt.Errorf("replace failed: %v", err3)
-			}
-		} else {
-			return fmt.Errorf("replace failed: %v", err)
-		}
+// runUninstall 還原原始檔案並移除語系資料夾
+func runUninstall(gameRoot, channel, locale string) (*installtx.Result, error) {
+	res := &installtx.Result{Channel: channel, Changed: []string{}, Reset: true}
+	if strings.TrimSpace(gameRoot) == "" || strings.TrimSpace(locale) == "" {
+		return res, errors.New("missing required arguments: --game, --locale")
 	}
-	return nil
+	channel, err := gameinstall.NormalizeChannel(channel)
+	if err != nil {
+		return res, err
+	}
+	res.Channel = channel
+	channelDir, err := openChannel(gameRoot, channel)
+	if err != nil {
+		return res, err
+	}
+	res, err = installtx.Uninstall(channelDir, channel, locale)
+	logRecovered(res, channelDir)
+	if err != nil {
+		return res, err
+	}
+	_ = writeLog(fmt.Sprintf("uninstall %s %s: %s", channel, locale, strings.Join(res.Changed, ", ")))
+	return res, nil
 }
 
 func validateGamePath(p string) bool {
@@ -77,7 +180,9 @@ func validateGamePath(p string) bool {
 		filepath.Join(p, "Bin64"),
 		filepath.Join(p, "Data"),
 		filepath.Join(p, "data.p4k"),
-		filepath.Join(p, "LIVE"),
+	}
+	for _, c := range gameinstall.Channels {
+		indicators = append(indicators, filepath.Join(p, c))
 	}
 	for _, x := range indicators {
 		if _, err := os.Stat(x); err == nil {
@@ -87,24 +192,6 @@ func validateGamePath(p string) bool {
 	return false
 }
 
-func copyFile(src, dst string) error {
-	s, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer s.Close()
-	// 0644 避免過寬權限
-	d, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	defer d.Close()
-	if _, err := io.Copy(d, s); err != nil {
-		return err
-	}
-	return nil
-}
-
 func writeLog(line string) error {
 	base := os.Getenv("LOCALAPPDATA")
 	if base == "" {
@@ -1,12 +1,12 @@
-import { useEffect } from 'react';
+import { useEffect, useState } from 'react';
 import './App.css';
 // import { IntroSection } from './components/IntroSection';
 import { useAppStore } from './store/appStore';
 import { GettingStarted } from './components/GettingStarted';
 import { ShipSorting } from './components/ShipSorting';
-import { GetSystemInfo } from '../wailsjs/go/main/App';
+import { GetSystemInfo, GetConfigWarning } from '../wailsjs/go/main/App';
 import { Footer } from './components/Footer';
-import { BrowserOpenURL } from '../wailsjs/runtime/runtime';
+import { BrowserOpenURL, EventsOn } from '../wailsjs/runtime/runtime';
 
 function App() {
     const { setSystemInfo, systemInfo, currentPage, setCurrentPage } = useAppStore();
@@ -18,9 +18,35 @@ function App() {
         });
     }, [setSystemInfo]);
 
+    // 設定檔損毀（已備份並改用預設設定）等警告
+    const [configWarning, setConfigWarning] = useState('');
+    useEffect(() => {
+        GetConfigWarning().then((w) => setConfigWarning(w || ''));
+        return EventsOn('config:error', (e: { error: string }) => setConfigWarning(e?.error || ''));
+    }, []);
+    // 套用的語系檔對應的遊戲版本與目前不同（只提示，安裝已完成）
+    const [buildWarning, setBuildWarning] = useState('');
+    useEffect(() => {
+        return EventsOn('locale:build-warning', (c: { warning: string }) => setBuildWarning(c?.warning || ''));
+    }, []);
+    const configBanner = configWarning ? (
+        <div className="max-w-6xl xl:max-w-7xl min-w-[960px] mx-auto mb-4 flex items-start justify-between gap-3 text-sm text-yellow-300 bg-yellow-950/30 border border-yellow-900/50 rounded-lg px-4 py-3">
+            <span className="break-all">設定檔讀取失敗，已改用預設設定：{configWarning}</span>
+            <button onClick={() => setConfigWarning('')} className="text-yellow-500 hover:text-yellow-300">✕</button>
+        </div>
+    ) : null;
+    const buildBanner = buildWarning ? (
+        <div className="max-w-6xl xl:max-w-7xl min-w-[960px] mx-auto mb-4 flex items-start justify-between gap-3 text-sm text-yellow-300 bg-yellow-950/30 border border-yellow-900/50 rounded-lg px-4 py-3">
+            <span className="break-all">語系檔與目前遊戲版本不同，部分文字可能未翻譯或顯示錯誤：{buildWarning}</span>
+            <button onClick={() => setBuildWarning('')} className="text-yellow-500 hover:text-yellow-300">✕</button>
+        </div>
+    ) : null;
+
     if (currentPage === 'localization') {
         return (
             <div className="min-h-screen bg-black p-6 flex flex-col">
+                {configBanner}
+                {buildBanner}
 				<div className="max-w-6xl xl:max-w-7xl min-w-[960px] mx-auto flex-1">
                     <GettingStarted />
                 </div>
@@ -31,6 +57,8 @@ function App() {
     if (currentPage === 'shipSorting') {
         return (
             <div className="min-h-screen bg-black p-6 flex flex-col">
+                {configBanner}
+                {buildBanner}
                 <div className="max-w-6xl xl:max-w-7xl min-w-[960px] mx-auto flex-1">
                     <ShipSorting />
                 </div>
@@ -41,6 +69,8 @@ function App() {
 
     return (
         <div className="min-h-screen bg-black p-6 flex flex-col">
+            {configBanner}
+            {buildBanner}
             <div className="max-w-5xl mx-auto flex-1">
                 {/* Header - 橘紅色主題 */}
                 <div className="text-center mb-6">
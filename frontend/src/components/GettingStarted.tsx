@@ -4,7 +4,7 @@ import { useAppStore } from '../store/appStore';
 import { PathSelector } from './PathSelector';
 import { LocaleCompare } from './LocaleCompare';
 import { LocaleEditor } from './LocaleEditor';
-import { ListInstalledLocalizations, SetUserLanguage, DetectStarCitizenPath, ValidateStarCitizenPath, CheckLocalizationExists } from '../../wailsjs/go/main/App';
+import { ListInstalledLocalizations, DetectStarCitizenPath, ValidateStarCitizenPath, CheckLocalizationExists } from '../../wailsjs/go/main/App';
 
 export const GettingStarted = () => {
   const { setCurrentPage, scPath, isPathValid, bumpLocalesVersion, setScPath, setIsPathValid, setIsPathDetecting, setLocalizationExists, editorTargetLocale, setEditorTargetLocale } = useAppStore();
@@ -80,20 +80,18 @@ export const GettingStarted = () => {
       log('開始處理...');
       log(`偵測安裝目錄：${scPath}`);
       log(hasChineseLocale ? '偵測到已存在中文語系，將執行自動更新' : '未偵測到中文語系，將執行自動安裝');
-      const url = 'https://squadron978.net/api/localization/latest/global.ini';
-      log('下載中文化檔案中...');
       const app: any = await import('../../wailsjs/go/main/App');
+      const url = await app.GetDownloadSource();
+      log('下載中文化檔案中...');
       const tmpPath = await app.DownloadToTemp(url, 'global.ini');
       log(`下載完成：${tmpPath}`);
       log('驗證檔案完整性...');
       const savedLocal = await app.SaveLocalLocaleFromFile('chinese_(traditional)', tmpPath);
       log(`已存到本機：${savedLocal}`);
-      log('套用至遊戲資料夾（將彈出系統授權）...');
-      await app.ApplyLocalLocaleToGame(scPath, 'chinese_(traditional)');
+      log('套用至遊戲資料夾並設定語系（將彈出系統授權）...');
+      const cfgPath = await app.ApplyLocalLocaleAndSetLanguage(scPath, 'chinese_(traditional)');
       log('已寫入 LIVE/data/Localization/chinese_(traditional)/global.ini');
-      log('設定 system.cfg 語系為 chinese_(traditional)...');
-      await SetUserLanguage(scPath, 'chinese_(traditional)');
-      log('system.cfg 設定完成');
+      log(`system.cfg 設定完成：${cfgPath}`);
       // 若存在 active.json，安裝後自動套用排序到 chinese_(traditional)
       try {
         await app.ApplyActiveVehicleOrderToLocale(scPath, 'chinese_(traditional)');
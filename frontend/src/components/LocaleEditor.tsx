@@ -57,6 +57,10 @@ TableRow.displayName = 'TableRow';
 export const LocaleEditor = () => {
   const { scPath, isPathValid, editorTargetLocale, setEditorTargetLocale } = useAppStore();
   const [currentFilePath, setCurrentFilePath] = useState('');
+  // 本機語系名稱；有值時修改存入個人覆寫圖層，不直接改寫基底 global.ini
+  const [localeName, setLocaleName] = useState('');
+  // 載入或儲存時的值，用於找出修改與刪除的項目
+  const savedValues = useRef<{ [key: string]: string }>({});
   const [allItems, setAllItems] = useState<INIKeyValue[]>([]);
   const [editedValues, setEditedValues] = useState<{ [key: string]: string }>({});
   const [isLoading, setIsLoading] = useState(false);
@@ -112,6 +116,20 @@ export const LocaleEditor = () => {
     return () => window.removeEventListener('resize', calculateHeight);
   }, []);
 
+  // 讀取編輯內容：本機語系讀取疊加圖層後的結果（含個人覆寫），其他檔案直接讀取
+  const readItems = async (path: string): Promise<INIKeyValue[]> => {
+    const app = await import('../../wailsjs/go/main/App');
+    const name = await app.GetLocaleNameOfPath(path);
+    const items = (name ? await app.ReadLocalLocaleComposed(name) : await app.ReadINIFile(path)) || [];
+    const values: { [key: string]: string } = {};
+    items.forEach((it) => {
+      if (!(it.key in values)) values[it.key] = it.value;
+    });
+    setLocaleName(name);
+    savedValues.current = values;
+    return items;
+  };
+
   // 檢查當前語系檔案路徑（不自動載入內容）
   useEffect(() => {
     const checkCurrentLocale = async () => {
@@ -171,8 +189,8 @@ export const LocaleEditor = () => {
         setSortOrder('asc');
         setShowReplacePanel(false);
         // 自動載入內容
-        const items = await app.ReadINIFile(p);
-        setAllItems(items || []);
+        const items = await readItems(p);
+        setAllItems(items);
         const initialValues: { [key: string]: string } = {};
         (items || []).forEach((it: any) => { initialValues[it.key] = it.value; });
         setEditedValues(initialValues);
@@ -352,17 +370,38 @@ export const LocaleEditor = () => {
         value: editedValues[item.key] || item.value
       }));
 
-      // 直接寫入檔案
-      const content = updates.map((item) => `${item.key}=${item.value}`).join('\n') + '\n';
-      
-      // 使用原生方法寫入
-      const { WriteINIFile } = await import('../../wailsjs/go/main/App');
-      await WriteINIFile(currentFilePath, updates);
-      
-      setMessage({ type: 'success', text: '儲存成功！' });
-      
-      // 更新 allItems 為新的值
-      setAllItems(updates);
+      const app = await import('../../wailsjs/go/main/App');
+      if (localeName) {
+        // 本機語系：修改存入個人覆寫圖層，基底 global.ini 不變，下次匯入新版譯文時不會遺失
+        const changed = updates.filter((item) => savedValues.current[item.key] !== item.value);
+        const kept = new Set(updates.map((item) => item.key));
+        const removed = Object.keys(savedValues.current).filter((key) => !kept.has(key));
+        await app.SaveLocaleOverrides(localeName, changed, removed);
+
+        // 重新讀取組合結果；基底中的鍵無法刪除，移除覆寫後會還原為基底的值
+        const items = await readItems(currentFilePath);
+        const values: { [key: string]: string } = {};
+        items.forEach((it) => { values[it.key] = it.value; });
+        setAllItems(items);
+        setEditedValues(values);
+        const restored = removed.filter((key) => key in values).length;
+        setMessage({
+          type: 'success',
+          text: restored > 0
+            ? `儲存成功！（已存入個人覆寫；${restored} 個項目存在於基底譯文，已還原為基底的值）`
+            : '儲存成功！（已存入個人覆寫）'
+        });
+      } else {
+        // 不在本機儲存區的檔案直接寫入
+        await app.WriteINIFile(currentFilePath, updates);
+        const values: { [key: string]: string } = {};
+        updates.forEach((it) => { values[it.key] = it.value; });
+        savedValues.current = values;
+        setMessage({ type: 'success', text: '儲存成功！' });
+
+        // 更新 allItems 為新的值
+        setAllItems(updates);
+      }
     } catch (e: any) {
       setMessage({ type: 'error', text: `儲存失敗：${e?.message || e}` });
     } finally {
@@ -381,13 +420,12 @@ export const LocaleEditor = () => {
     setMessage(null);
     
     try {
-      const { ReadINIFile } = await import('../../wailsjs/go/main/App');
-      const items = await ReadINIFile(currentFilePath);
-      setAllItems(items || []);
+      const items = await readItems(currentFilePath);
+      setAllItems(items);
       
       // 初始化編輯值
       const initialValues: { [key: string]: string } = {};
-      (items || []).forEach((item) => {
+      items.forEach((item) => {
         initialValues[item.key] = item.value;
       });
       setEditedValues(initialValues);
@@ -12,7 +12,14 @@ import {
   SetUserLanguage,
   ResetToDefaultLanguage,
   SaveStarCitizenPath,
+  DiscoverStarCitizenInstalls,
+  CancelDriveScan,
 } from '../../wailsjs/go/main/App';
+import { gameinstall } from '../../wailsjs/go/models';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+
+// drivescan:progress 事件內容（對應 gameinstall.ScanProgress）
+type ScanProgress = { target: string; state: string; found: string[]; done: number; total: number };
 
 export const PathSelector = ({ showPathSection = true, showLocaleSection = true }: { showPathSection?: boolean; showLocaleSection?: boolean }) => {
   const {
@@ -42,11 +49,20 @@ export const PathSelector = ({ showPathSection = true, showLocaleSection = true
   const [importFilePath, setImportFilePath] = useState('');
   const [isImporting, setIsImporting] = useState(false);
   const [confirmDeleteLocale, setConfirmDeleteLocale] = useState<string | null>(null);
+  const [installs, setInstalls] = useState<gameinstall.Install[]>([]);
+  const [scanProgress, setScanProgress] = useState<ScanProgress | null>(null);
+
+  // 磁碟機掃描進度（自動偵測與列出安裝時）
+  useEffect(() => {
+    return EventsOn('drivescan:progress', (p: ScanProgress) => setScanProgress(p));
+  }, []);
 
   // 掛載時自動偵測一次（若尚未有路徑且未在偵測中）
   useEffect(() => {
     if (!scPath && !isPathDetecting) {
       void handleAutoDetect();
+    } else if (scPath) {
+      void refreshInstalls();
     }
   // eslint-disable-next-line react-hooks/exhaustive-deps
   }, []);
@@ -112,6 +128,60 @@ export const PathSelector = ({ showPathSection = true, showLocaleSection = true
     }
   }, [installedLocales]);
 
+  // 讀取有效路徑的語系狀態（必要時建立 Localization 資料夾）
+  const loadPathState = async (path: string) => {
+    const hasHasBaseApi = (window as any)?.go?.main?.App?.HasLocalizationBase;
+    const hasCreateDirApi = (window as any)?.go?.main?.App?.CreateLocalizationDir;
+    const hasListApi = (window as any)?.go?.main?.App?.ListInstalledLocalizations;
+
+    const baseExists = hasHasBaseApi ? await HasLocalizationBase(path) : true;
+    if (!baseExists) {
+      if (hasCreateDirApi) {
+        await CreateLocalizationDir(path);
+      }
+      setDidInitLocalization(true);
+    } else {
+      setDidInitLocalization(false);
+    }
+    const exists = await CheckLocalizationExists(path);
+    setLocalizationExists(exists);
+    const locPath = await GetLocalizationPath(path);
+    setLocalizationPath(locPath);
+    if (hasListApi) {
+      const locales = await ListInstalledLocalizations(path);
+      setInstalledLocales(Array.isArray(locales) ? locales : []);
+    } else {
+      setInstalledLocales([]);
+    }
+  };
+
+  // 列出所有找到的安裝（多於一個時讓使用者選擇）
+  const refreshInstalls = async () => {
+    try {
+      const list = await DiscoverStarCitizenInstalls();
+      setInstalls(Array.isArray(list) ? list : []);
+    } catch (e) {
+      console.error('列出安裝失敗:', e);
+      setInstalls([]);
+    }
+  };
+
+  // 選擇其中一個安裝並保存
+  const handleSelectInstall = async (path: string) => {
+    try {
+      await SaveStarCitizenPath(path);
+      setScPath(path);
+      const valid = await ValidateStarCitizenPath(path);
+      setIsPathValid(valid);
+      if (valid) {
+        await loadPathState(path);
+      }
+      await refreshInstalls();
+    } catch (e) {
+      console.error('選擇安裝失敗:', e);
+    }
+  };
+
   // 自動偵測路徑
   const handleAutoDetect = async () => {
     setIsPathDetecting(true);
@@ -123,39 +193,19 @@ export const PathSelector = ({ showPathSection = true, showLocaleSection = true
         setIsPathValid(valid);
         
         if (valid) {
-          const hasHasBaseApi = (window as any)?.go?.main?.App?.HasLocalizationBase;
-          const hasCreateDirApi = (window as any)?.go?.main?.App?.CreateLocalizationDir;
-          const hasListApi = (window as any)?.go?.main?.App?.ListInstalledLocalizations;
-
-          const baseExists = hasHasBaseApi ? await HasLocalizationBase(detectedPath) : true;
-          if (!baseExists) {
-            if (hasCreateDirApi) {
-              await CreateLocalizationDir(detectedPath);
-            }
-            setDidInitLocalization(true);
-          } else {
-            setDidInitLocalization(false);
-          }
-          const exists = await CheckLocalizationExists(detectedPath);
-          setLocalizationExists(exists);
-          const locPath = await GetLocalizationPath(detectedPath);
-          setLocalizationPath(locPath);
-          if (hasListApi) {
-            const locales = await ListInstalledLocalizations(detectedPath);
-            setInstalledLocales(Array.isArray(locales) ? locales : []);
-          } else {
-            setInstalledLocales([]);
-          }
+          await loadPathState(detectedPath);
         }
       } else {
         // 未找到路徑
         setScPath('');
         setIsPathValid(false);
       }
+      await refreshInstalls();
     } catch (error) {
       console.error('自動偵測失敗:', error);
     } finally {
       setIsPathDetecting(false);
+      setScanProgress(null);
     }
   };
 
@@ -176,29 +226,7 @@ export const PathSelector = ({ showPathSection = true, showLocaleSection = true
             console.warn('保存路徑失敗:', err);
           }
 
-          const hasHasBaseApi = (window as any)?.go?.main?.App?.HasLocalizationBase;
-          const hasCreateDirApi = (window as any)?.go?.main?.App?.CreateLocalizationDir;
-          const hasListApi = (window as any)?.go?.main?.App?.ListInstalledLocalizations;
-
-          const baseExists = hasHasBaseApi ? await HasLocalizationBase(selectedPath) : true;
-          if (!baseExists) {
-            if (hasCreateDirApi) {
-              await CreateLocalizationDir(selectedPath);
-            }
-            setDidInitLocalization(true);
-          } else {
-            setDidInitLocalization(false);
-          }
-          const exists = await CheckLocalizationExists(selectedPath);
-          setLocalizationExists(exists);
-          const locPath = await GetLocalizationPath(selectedPath);
-          setLocalizationPath(locPath);
-          if (hasListApi) {
-            const locales = await ListInstalledLocalizations(selectedPath);
-            setInstalledLocales(Array.isArray(locales) ? locales : []);
-          } else {
-            setInstalledLocales([]);
-          }
+          await loadPathState(selectedPath);
         }
       }
     } catch (error) {
@@ -301,6 +329,56 @@ export const PathSelector = ({ showPathSection = true, showLocaleSection = true
           </div>
         )}
 
+        {/* 掃描磁碟機的進度，可取消 */}
+        {isPathDetecting && scanProgress && scanProgress.total > 0 && (
+          <div className="mt-3 flex items-center justify-between text-xs text-gray-400 bg-black/40 border border-gray-700 rounded-lg px-3 py-2">
+            <span>
+              掃描中 {scanProgress.target}（{scanProgress.done}/{scanProgress.total}）
+              {scanProgress.state === 'timeout' ? '：逾時略過' : ''}
+            </span>
+            <button
+              onClick={() => void CancelDriveScan()}
+              className="ml-3 px-2 py-1 rounded border border-orange-900/50 text-orange-400 hover:bg-gray-800"
+            >
+              取消掃描
+            </button>
+          </div>
+        )}
+
+        {/* 找到多個安裝時列出供選擇 */}
+        {installs.length > 1 && (
+          <div className="mt-3 space-y-2">
+            <div className="text-xs text-gray-400">找到 {installs.length} 個安裝，請選擇要使用的安裝：</div>
+            {installs.map((inst) => {
+              const selected = inst.path === scPath;
+              return (
+                <button
+                  key={inst.path}
+                  onClick={() => void handleSelectInstall(inst.path)}
+                  className={`w-full text-left px-3 py-2 rounded-lg border text-xs transition-colors ${
+                    selected
+                      ? 'bg-orange-950/40 border-orange-600 text-orange-200'
+                      : 'bg-black/40 border-gray-700 text-gray-300 hover:border-orange-900/60'
+                  }`}
+                >
+                  <div className="font-mono break-all">{selected ? '✓ ' : ''}{inst.path}</div>
+                  <div className="mt-1 text-gray-400">
+                    {(inst.channels || []).map((ch) => (
+                      <span key={ch.channel} className="mr-3">
+                        {ch.channel} {ch.build?.version || '未知版本'}
+                        {ch.needsElevation ? '（需要管理員權限）' : ''}
+                      </span>
+                    ))}
+                    {inst.freeBytes >= 0 && (
+                      <span>可用空間 {(inst.freeBytes / 1024 ** 3).toFixed(1)} GB</span>
+                    )}
+                  </div>
+                </button>
+              );
+            })}
+          </div>
+        )}
+
         {/* 操作按鈕（移到輸入框下方） */}
         <div className="mt-3 flex gap-3">
           <button
@@ -1,29 +1,59 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
-import {main} from '../models';
+import {localestore} from '../models';
+import {ini} from '../models';
+import {gameinstall} from '../models';
+import {config} from '../models';
+import {patchwatch} from '../models';
 
 export function ApplyActiveVehicleOrderToLocale(arg1:string,arg2:string):Promise<void>;
 
-export function ApplyLocalLocaleToGame(arg1:string,arg2:string):Promise<void>;
+export function ApplyLocalLocaleAndSetLanguage(arg1:string,arg2:string):Promise<string>;
+
+export function ApplyLocalLocaleAndSetLanguageForChannel(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function ApplyLocalLocaleToGame(arg1:string,arg2:string):Promise<localestore.BuildCheck>;
+
+export function ApplyLocalLocaleToGameForChannel(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<localestore.BuildCheck>;
+
+export function ApplyLocalLocaleToGameSkipValidation(arg1:string,arg2:string):Promise<localestore.BuildCheck>;
 
 export function BuildOrderedLocaleToTemp(arg1:string,arg2:string):Promise<string>;
 
+export function CancelDriveScan():Promise<void>;
+
+export function CheckLocaleGameBuild(arg1:string,arg2:string):Promise<localestore.BuildCheck>;
+
 export function CheckLocalizationExists(arg1:string):Promise<boolean>;
 
-export function CompareINIFiles(arg1:string,arg2:string):Promise<Array<main.INIKeyValue>>;
+export function CompareINIFiles(arg1:string,arg2:string):Promise<Array<ini.KeyValue>>;
+
+export function CompareINIFilesDetailed(arg1:string,arg2:string):Promise<ini.CompareResult>;
 
-export function CompareINIFilesDetailed(arg1:string,arg2:string):Promise<main.CompareResult>;
+export function CompareINIFilesStale(arg1:string,arg2:string):Promise<ini.StaleCompareResult>;
+
+export function CreateLocaleSnapshot(arg1:string):Promise<localestore.Snapshot>;
 
 export function CreateLocalizationDir(arg1:string):Promise<void>;
 
+export function DeleteLocaleLayer(arg1:string,arg2:string):Promise<void>;
+
 export function DeleteLocalization(arg1:string,arg2:string):Promise<void>;
 
 export function DeleteVehicleOrderSave(arg1:string,arg2:string):Promise<void>;
 
+export function DetectFileEncoding(arg1:string):Promise<string>;
+
 export function DetectStarCitizenPath():Promise<string>;
 
+export function DiffLocaleSnapshot(arg1:string,arg2:string):Promise<localestore.SnapshotDiff>;
+
+export function DiscoverStarCitizenInstalls():Promise<Array<gameinstall.Install>>;
+
 export function DownloadAndInstallLocalization(arg1:string,arg2:string):Promise<string>;
 
+export function DownloadAndInstallLocalizationForChannel(arg1:string,arg2:string,arg3:string):Promise<string>;
+
 export function DownloadToTemp(arg1:string,arg2:string):Promise<string>;
 
 export function EnsureSortDirs(arg1:string):Promise<string>;
@@ -34,14 +64,40 @@ export function ExportLocaleFileStripped(arg1:string,arg2:string,arg3:string):Pr
 
 export function ExportVehicleOrderFile(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function ExtractEnglishINIFromP4K(arg1:string,arg2:string):Promise<string>;
+
 export function GetActiveVehicleOrder(arg1:string):Promise<Array<string>>;
 
+export function GetAppSettings():Promise<config.Settings>;
+
+export function GetConfigWarning():Promise<string>;
+
 export function GetCurrentLocaleINIPath(arg1:string):Promise<string>;
 
+export function GetDownloadSource():Promise<string>;
+
+export function GetDriveScanSettings():Promise<config.DriveScanSettings>;
+
+export function GetGameBuildInfo(arg1:string,arg2:string):Promise<gameinstall.BuildInfo>;
+
+export function GetGameChannel():Promise<string>;
+
+export function GetINIDiagnostics(arg1:string):Promise<Array<ini.Warning>>;
+
+export function GetINIFormatOverride():Promise<ini.FormatOverride>;
+
 export function GetLocalLocaleINIPath(arg1:string):Promise<string>;
 
+export function GetLocaleGameBuild(arg1:string):Promise<localestore.Meta>;
+
+export function GetLocaleNameOfPath(arg1:string):Promise<string>;
+
+export function GetLocaleOverrides(arg1:string):Promise<Array<ini.KeyValue>>;
+
 export function GetLocalizationPath(arg1:string):Promise<string>;
 
+export function GetPatchWatchStatus(arg1:string):Promise<Array<patchwatch.Status>>;
+
 export function GetSavedStarCitizenPath():Promise<string>;
 
 export function GetSortBasePath(arg1:string):Promise<string>;
@@ -50,26 +106,62 @@ export function GetSystemInfo():Promise<Record<string, string>>;
 
 export function GetUserLanguage(arg1:string):Promise<string>;
 
+export function GetUserLanguageForChannel(arg1:string,arg2:string):Promise<string>;
+
 export function HasLocalizationBase(arg1:string):Promise<boolean>;
 
 export function ImportLocaleFile(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function ImportLocaleLayer(arg1:string,arg2:string,arg3:string,arg4:string):Promise<localestore.Layer>;
+
 export function ImportVehicleOrderFile(arg1:string,arg2:string):Promise<string>;
 
+export function InstallFromManifest(arg1:string,arg2:string):Promise<void>;
+
 export function InstallLocaleFromFileElevated(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function InstallLocaleFromFileElevatedForChannel(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function IsPatchWatcherRunning():Promise<boolean>;
+
+export function ListGameChannels(arg1:string):Promise<Array<string>>;
+
 export function ListInstalledLocalizations(arg1:string):Promise<Array<string>>;
 
+export function ListLocaleLayers(arg1:string):Promise<localestore.LayerStack>;
+
+export function ListLocaleSnapshots(arg1:string):Promise<Array<localestore.Snapshot>>;
+
 export function ListVehicleOrderSaves(arg1:string):Promise<Array<string>>;
 
-export function ReadINIFile(arg1:string):Promise<Array<main.INIKeyValue>>;
+export function MergeEnglishUpdate(arg1:string,arg2:string,arg3:string,arg4:string):Promise<ini.MergeReport>;
+
+export function ReadINIFile(arg1:string):Promise<Array<ini.KeyValue>>;
+
+export function ReadLocalLocaleComposed(arg1:string):Promise<Array<ini.KeyValue>>;
+
+export function ReapplyLocalization(arg1:string,arg2:string):Promise<void>;
+
+export function RecordLocaleGameBuild(arg1:string,arg2:string):Promise<localestore.Meta>;
+
+export function RecordSourceFingerprints(arg1:string,arg2:string,arg3:Array<string>):Promise<number>;
+
+export function ReorderLocaleLayers(arg1:string,arg2:Array<string>):Promise<void>;
 
 export function ResetToDefaultLanguage(arg1:string):Promise<void>;
 
+export function ResetToDefaultLanguageForChannel(arg1:string,arg2:string):Promise<void>;
+
+export function RestoreLocaleSnapshot(arg1:string,arg2:string):Promise<void>;
+
+export function SaveAppSettings(arg1:config.Settings):Promise<void>;
+
 export function SaveFile(arg1:string,arg2:string):Promise<string>;
 
 export function SaveLocalLocaleFromFile(arg1:string,arg2:string):Promise<string>;
 
+export function SaveLocaleOverrides(arg1:string,arg2:Array<ini.KeyValue>,arg3:Array<string>):Promise<void>;
+
 export function SaveStarCitizenPath(arg1:string):Promise<void>;
 
 export function SaveTextFile(arg1:string,arg2:string,arg3:string):Promise<string>;
@@ -78,6 +170,8 @@ export function SaveVehicleOrderActive(arg1:string,arg2:Array<string>):Promise<s
 
 export function SaveVehicleOrderAs(arg1:string,arg2:string,arg3:Array<string>):Promise<string>;
 
+export function ScanDrivesForStarCitizen():Promise<Array<string>>;
+
 export function SelectDirectory():Promise<string>;
 
 export function SelectFile(arg1:string):Promise<string>;
@@ -86,12 +180,34 @@ export function SelectJSONFile(arg1:string):Promise<string>;
 
 export function SetActiveVehicleOrderByName(arg1:string,arg2:string):Promise<Array<string>>;
 
+export function SetDriveScanSettings(arg1:config.DriveScanSettings):Promise<void>;
+
+export function SetGameChannel(arg1:string):Promise<void>;
+
+export function SetINIFormatOverride(arg1:ini.FormatOverride):Promise<void>;
+
+export function SetLocaleLayerEnabled(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+
 export function SetUserLanguage(arg1:string,arg2:string):Promise<string>;
 
+export function SetUserLanguageForChannel(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function StartPatchWatcher(arg1:string,arg2:boolean):Promise<void>;
+
+export function StopPatchWatcher():Promise<void>;
+
 export function StripActiveVehicleOrderFromLocale(arg1:string,arg2:string):Promise<void>;
 
-export function UpdateINIFile(arg1:string,arg2:string,arg3:Array<main.INIKeyValue>):Promise<void>;
+export function UninstallLocalization(arg1:string,arg2:string):Promise<void>;
+
+export function UninstallLocalizationForChannel(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function UpdateINIFile(arg1:string,arg2:string,arg3:Array<ini.KeyValue>):Promise<void>;
+
+export function ValidateLocalLocale(arg1:string,arg2:string,arg3:string):Promise<ini.ValidationReport>;
 
 export function ValidateStarCitizenPath(arg1:string):Promise<boolean>;
 
-export function WriteINIFile(arg1:string,arg2:Array<main.INIKeyValue>):Promise<void>;
+export function ValidateTranslation(arg1:string,arg2:string):Promise<ini.ValidationReport>;
+
+export function WriteINIFile(arg1:string,arg2:Array<ini.KeyValue>):Promise<void>;
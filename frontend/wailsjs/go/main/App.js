@@ -6,14 +6,38 @@ export function ApplyActiveVehicleOrderToLocale(arg1, arg2) {
   return window['go']['main']['App']['ApplyActiveVehicleOrderToLocale'](arg1, arg2);
 }
 
+export function ApplyLocalLocaleAndSetLanguage(arg1, arg2) {
+  return window['go']['main']['App']['ApplyLocalLocaleAndSetLanguage'](arg1, arg2);
+}
+
+export function ApplyLocalLocaleAndSetLanguageForChannel(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ApplyLocalLocaleAndSetLanguageForChannel'](arg1, arg2, arg3);
+}
+
 export function ApplyLocalLocaleToGame(arg1, arg2) {
   return window['go']['main']['App']['ApplyLocalLocaleToGame'](arg1, arg2);
 }
 
+export function ApplyLocalLocaleToGameForChannel(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ApplyLocalLocaleToGameForChannel'](arg1, arg2, arg3, arg4);
+}
+
+export function ApplyLocalLocaleToGameSkipValidation(arg1, arg2) {
+  return window['go']['main']['App']['ApplyLocalLocaleToGameSkipValidation'](arg1, arg2);
+}
+
 export function BuildOrderedLocaleToTemp(arg1, arg2) {
   return window['go']['main']['App']['BuildOrderedLocaleToTemp'](arg1, arg2);
 }
 
+export function CancelDriveScan() {
+  return window['go']['main']['App']['CancelDriveScan']();
+}
+
+export function CheckLocaleGameBuild(arg1, arg2) {
+  return window['go']['main']['App']['CheckLocaleGameBuild'](arg1, arg2);
+}
+
 export function CheckLocalizationExists(arg1) {
   return window['go']['main']['App']['CheckLocalizationExists'](arg1);
 }
@@ -26,10 +50,22 @@ export function CompareINIFilesDetailed(arg1, arg2) {
   return window['go']['main']['App']['CompareINIFilesDetailed'](arg1, arg2);
 }
 
+export function CompareINIFilesStale(arg1, arg2) {
+  return window['go']['main']['App']['CompareINIFilesStale'](arg1, arg2);
+}
+
+export function CreateLocaleSnapshot(arg1) {
+  return window['go']['main']['App']['CreateLocaleSnapshot'](arg1);
+}
+
 export function CreateLocalizationDir(arg1) {
   return window['go']['main']['App']['CreateLocalizationDir'](arg1);
 }
 
+export function DeleteLocaleLayer(arg1, arg2) {
+  return window['go']['main']['App']['DeleteLocaleLayer'](arg1, arg2);
+}
+
 export function DeleteLocalization(arg1, arg2) {
   return window['go']['main']['App']['DeleteLocalization'](arg1, arg2);
 }
@@ -38,14 +74,30 @@ export function DeleteVehicleOrderSave(arg1, arg2) {
   return window['go']['main']['App']['DeleteVehicleOrderSave'](arg1, arg2);
 }
 
+export function DetectFileEncoding(arg1) {
+  return window['go']['main']['App']['DetectFileEncoding'](arg1);
+}
+
 export function DetectStarCitizenPath() {
   return window['go']['main']['App']['DetectStarCitizenPath']();
 }
 
+export function DiffLocaleSnapshot(arg1, arg2) {
+  return window['go']['main']['App']['DiffLocaleSnapshot'](arg1, arg2);
+}
+
+export function DiscoverStarCitizenInstalls() {
+  return window['go']['main']['App']['DiscoverStarCitizenInstalls']();
+}
+
 export function DownloadAndInstallLocalization(arg1, arg2) {
   return window['go']['main']['App']['DownloadAndInstallLocalization'](arg1, arg2);
 }
 
+export function DownloadAndInstallLocalizationForChannel(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DownloadAndInstallLocalizationForChannel'](arg1, arg2, arg3);
+}
+
 export function DownloadToTemp(arg1, arg2) {
   return window['go']['main']['App']['DownloadToTemp'](arg1, arg2);
 }
@@ -66,22 +118,74 @@ export function ExportVehicleOrderFile(arg1, arg2, arg3) {
   return window['go']['main']['App']['ExportVehicleOrderFile'](arg1, arg2, arg3);
 }
 
+export function ExtractEnglishINIFromP4K(arg1, arg2) {
+  return window['go']['main']['App']['ExtractEnglishINIFromP4K'](arg1, arg2);
+}
+
 export function GetActiveVehicleOrder(arg1) {
   return window['go']['main']['App']['GetActiveVehicleOrder'](arg1);
 }
 
+export function GetAppSettings() {
+  return window['go']['main']['App']['GetAppSettings']();
+}
+
+export function GetConfigWarning() {
+  return window['go']['main']['App']['GetConfigWarning']();
+}
+
 export function GetCurrentLocaleINIPath(arg1) {
   return window['go']['main']['App']['GetCurrentLocaleINIPath'](arg1);
 }
 
+export function GetDownloadSource() {
+  return window['go']['main']['App']['GetDownloadSource']();
+}
+
+export function GetDriveScanSettings() {
+  return window['go']['main']['App']['GetDriveScanSettings']();
+}
+
+export function GetGameBuildInfo(arg1, arg2) {
+  return window['go']['main']['App']['GetGameBuildInfo'](arg1, arg2);
+}
+
+export function GetGameChannel() {
+  return window['go']['main']['App']['GetGameChannel']();
+}
+
+export function GetINIDiagnostics(arg1) {
+  return window['go']['main']['App']['GetINIDiagnostics'](arg1);
+}
+
+export function GetINIFormatOverride() {
+  return window['go']['main']['App']['GetINIFormatOverride']();
+}
+
 export function GetLocalLocaleINIPath(arg1) {
   return window['go']['main']['App']['GetLocalLocaleINIPath'](arg1);
 }
 
+export function GetLocaleGameBuild(arg1) {
+  return window['go']['main']['App']['GetLocaleGameBuild'](arg1);
+}
+
+export function GetLocaleNameOfPath(arg1) {
+  return window['go']['main']['App']['GetLocaleNameOfPath'](arg1);
+}
+
+export function GetLocaleOverrides(arg1) {
+  return window['go']['main']['App']['GetLocaleOverrides'](arg1);
+}
+
 export function GetLocalizationPath(arg1) {
   return window['go']['main']['App']['GetLocalizationPath'](arg1);
 }
 
+export function GetPatchWatchStatus(arg1) {
+  return window['go']['main']['App']['GetPatchWatchStatus'](arg1);
+}
+
 export function GetSavedStarCitizenPath() {
   return window['go']['main']['App']['GetSavedStarCitizenPath']();
 }
@@ -98,6 +202,10 @@ export function GetUserLanguage(arg1) {
   return window['go']['main']['App']['GetUserLanguage'](arg1);
 }
 
+export function GetUserLanguageForChannel(arg1, arg2) {
+  return window['go']['main']['App']['GetUserLanguageForChannel'](arg1, arg2);
+}
+
 export function HasLocalizationBase(arg1) {
   return window['go']['main']['App']['HasLocalizationBase'](arg1);
 }
@@ -106,30 +214,94 @@ export function ImportLocaleFile(arg1, arg2, arg3) {
   return window['go']['main']['App']['ImportLocaleFile'](arg1, arg2, arg3);
 }
 
+export function ImportLocaleLayer(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ImportLocaleLayer'](arg1, arg2, arg3, arg4);
+}
+
 export function ImportVehicleOrderFile(arg1, arg2) {
   return window['go']['main']['App']['ImportVehicleOrderFile'](arg1, arg2);
 }
 
+export function InstallFromManifest(arg1, arg2) {
+  return window['go']['main']['App']['InstallFromManifest'](arg1, arg2);
+}
+
 export function InstallLocaleFromFileElevated(arg1, arg2, arg3) {
   return window['go']['main']['App']['InstallLocaleFromFileElevated'](arg1, arg2, arg3);
 }
 
+export function InstallLocaleFromFileElevatedForChannel(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['InstallLocaleFromFileElevatedForChannel'](arg1, arg2, arg3, arg4);
+}
+
+export function IsPatchWatcherRunning() {
+  return window['go']['main']['App']['IsPatchWatcherRunning']();
+}
+
+export function ListGameChannels(arg1) {
+  return window['go']['main']['App']['ListGameChannels'](arg1);
+}
+
 export function ListInstalledLocalizations(arg1) {
   return window['go']['main']['App']['ListInstalledLocalizations'](arg1);
 }
 
+export function ListLocaleLayers(arg1) {
+  return window['go']['main']['App']['ListLocaleLayers'](arg1);
+}
+
+export function ListLocaleSnapshots(arg1) {
+  return window['go']['main']['App']['ListLocaleSnapshots'](arg1);
+}
+
 export function ListVehicleOrderSaves(arg1) {
   return window['go']['main']['App']['ListVehicleOrderSaves'](arg1);
 }
 
+export function MergeEnglishUpdate(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['MergeEnglishUpdate'](arg1, arg2, arg3, arg4);
+}
+
 export function ReadINIFile(arg1) {
   return window['go']['main']['App']['ReadINIFile'](arg1);
 }
 
+export function ReadLocalLocaleComposed(arg1) {
+  return window['go']['main']['App']['ReadLocalLocaleComposed'](arg1);
+}
+
+export function ReapplyLocalization(arg1, arg2) {
+  return window['go']['main']['App']['ReapplyLocalization'](arg1, arg2);
+}
+
+export function RecordLocaleGameBuild(arg1, arg2) {
+  return window['go']['main']['App']['RecordLocaleGameBuild'](arg1, arg2);
+}
+
+export function RecordSourceFingerprints(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RecordSourceFingerprints'](arg1, arg2, arg3);
+}
+
+export function ReorderLocaleLayers(arg1, arg2) {
+  return window['go']['main']['App']['ReorderLocaleLayers'](arg1, arg2);
+}
+
 export function ResetToDefaultLanguage(arg1) {
   return window['go']['main']['App']['ResetToDefaultLanguage'](arg1);
 }
 
+export function ResetToDefaultLanguageForChannel(arg1, arg2) {
+  return window['go']['main']['App']['ResetToDefaultLanguageForChannel'](arg1, arg2);
+}
+
+export function RestoreLocaleSnapshot(arg1, arg2) {
+  return window['go']['main']['App']['RestoreLocaleSnapshot'](arg1, arg2);
+}
+
+export function SaveAppSettings(arg1) {
+  return window['go']['main']['App']['SaveAppSettings'](arg1);
+}
+
 export function SaveFile(arg1, arg2) {
   return window['go']['main']['App']['SaveFile'](arg1, arg2);
 }
@@ -138,6 +310,10 @@ export function SaveLocalLocaleFromFile(arg1, arg2) {
   return window['go']['main']['App']['SaveLocalLocaleFromFile'](arg1, arg2);
 }
 
+export function SaveLocaleOverrides(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveLocaleOverrides'](arg1, arg2, arg3);
+}
+
 export function SaveStarCitizenPath(arg1) {
   return window['go']['main']['App']['SaveStarCitizenPath'](arg1);
 }
@@ -154,6 +330,10 @@ export function SaveVehicleOrderAs(arg1, arg2, arg3) {
   return window['go']['main']['App']['SaveVehicleOrderAs'](arg1, arg2, arg3);
 }
 
+export function ScanDrivesForStarCitizen() {
+  return window['go']['main']['App']['ScanDrivesForStarCitizen']();
+}
+
 export function SelectDirectory() {
   return window['go']['main']['App']['SelectDirectory']();
 }
@@ -170,22 +350,66 @@ export function SetActiveVehicleOrderByName(arg1, arg2) {
   return window['go']['main']['App']['SetActiveVehicleOrderByName'](arg1, arg2);
 }
 
+export function SetDriveScanSettings(arg1) {
+  return window['go']['main']['App']['SetDriveScanSettings'](arg1);
+}
+
+export function SetGameChannel(arg1) {
+  return window['go']['main']['App']['SetGameChannel'](arg1);
+}
+
+export function SetINIFormatOverride(arg1) {
+  return window['go']['main']['App']['SetINIFormatOverride'](arg1);
+}
+
+export function SetLocaleLayerEnabled(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetLocaleLayerEnabled'](arg1, arg2, arg3);
+}
+
 export function SetUserLanguage(arg1, arg2) {
   return window['go']['main']['App']['SetUserLanguage'](arg1, arg2);
 }
 
+export function SetUserLanguageForChannel(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetUserLanguageForChannel'](arg1, arg2, arg3);
+}
+
+export function StartPatchWatcher(arg1, arg2) {
+  return window['go']['main']['App']['StartPatchWatcher'](arg1, arg2);
+}
+
+export function StopPatchWatcher() {
+  return window['go']['main']['App']['StopPatchWatcher']();
+}
+
 export function StripActiveVehicleOrderFromLocale(arg1, arg2) {
   return window['go']['main']['App']['StripActiveVehicleOrderFromLocale'](arg1, arg2);
 }
 
+export function UninstallLocalization(arg1, arg2) {
+  return window['go']['main']['App']['UninstallLocalization'](arg1, arg2);
+}
+
+export function UninstallLocalizationForChannel(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UninstallLocalizationForChannel'](arg1, arg2, arg3);
+}
+
 export function UpdateINIFile(arg1, arg2, arg3) {
   return window['go']['main']['App']['UpdateINIFile'](arg1, arg2, arg3);
 }
 
+export function ValidateLocalLocale(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ValidateLocalLocale'](arg1, arg2, arg3);
+}
+
 export function ValidateStarCitizenPath(arg1) {
   return window['go']['main']['App']['ValidateStarCitizenPath'](arg1);
 }
 
+export function ValidateTranslation(arg1, arg2) {
+  return window['go']['main']['App']['ValidateTranslation'](arg1, arg2);
+}
+
 export function WriteINIFile(arg1, arg2) {
   return window['go']['main']['App']['WriteINIFile'](arg1, arg2);
 }
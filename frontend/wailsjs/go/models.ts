@@ -1,11 +1,216 @@
-export namespace main {
+export namespace config {
 	
-	export class INIKeyValue {
+	export class DriveScanSettings {
+	    depth: number;
+	    timeoutSeconds: number;
+	    libraryFolders: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DriveScanSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.depth = source["depth"];
+	        this.timeoutSeconds = source["timeoutSeconds"];
+	        this.libraryFolders = source["libraryFolders"];
+	    }
+	}
+	export class UISettings {
+	    lastPage: string;
+	    showLog: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new UISettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lastPage = source["lastPage"];
+	        this.showLog = source["showLog"];
+	    }
+	}
+	export class UpdateSettings {
+	    checkOnStartup: boolean;
+	    autoInstall: boolean;
+	    watchGamePatches: boolean;
+	    autoReapply: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.checkOnStartup = source["checkOnStartup"];
+	        this.autoInstall = source["autoInstall"];
+	        this.watchGamePatches = source["watchGamePatches"];
+	        this.autoReapply = source["autoReapply"];
+	    }
+	}
+	export class Settings {
+	    version: number;
+	    starCitizenPath: string;
+	    gameChannel: string;
+	    downloadSource: string;
+	    activeLocale: string;
+	    updates: UpdateSettings;
+	    ui: UISettings;
+	    driveScan: DriveScanSettings;
+	    iniFormat: ini.FormatOverride;
+	    localeBuilds: Record<string, gameinstall.BuildInfo>;
+	    lastUpdated: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.starCitizenPath = source["starCitizenPath"];
+	        this.gameChannel = source["gameChannel"];
+	        this.downloadSource = source["downloadSource"];
+	        this.activeLocale = source["activeLocale"];
+	        this.updates = this.convertValues(source["updates"], UpdateSettings);
+	        this.ui = this.convertValues(source["ui"], UISettings);
+	        this.driveScan = this.convertValues(source["driveScan"], DriveScanSettings);
+	        this.iniFormat = this.convertValues(source["iniFormat"], ini.FormatOverride);
+	        this.localeBuilds = this.convertValues(source["localeBuilds"], gameinstall.BuildInfo, true);
+	        this.lastUpdated = source["lastUpdated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+
+}
+
+export namespace gameinstall {
+	
+	export class BuildInfo {
+	    channel: string;
+	    branch: string;
+	    version: string;
+	    changeNum: string;
+	    buildDate: string;
+	    tag: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BuildInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channel = source["channel"];
+	        this.branch = source["branch"];
+	        this.version = source["version"];
+	        this.changeNum = source["changeNum"];
+	        this.buildDate = source["buildDate"];
+	        this.tag = source["tag"];
+	    }
+	}
+	export class ChannelStatus {
+	    channel: string;
+	    build?: BuildInfo;
+	    writable: boolean;
+	    needsElevation: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChannelStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channel = source["channel"];
+	        this.build = this.convertValues(source["build"], BuildInfo);
+	        this.writable = source["writable"];
+	        this.needsElevation = source["needsElevation"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Install {
+	    path: string;
+	    sources: string[];
+	    channels: ChannelStatus[];
+	    freeBytes: number;
+	    preferred: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Install(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.sources = source["sources"];
+	        this.channels = this.convertValues(source["channels"], ChannelStatus);
+	        this.freeBytes = source["freeBytes"];
+	        this.preferred = source["preferred"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace ini {
+	
+	export class KeyValue {
 	    key: string;
 	    value: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new INIKeyValue(source);
+	        return new KeyValue(source);
 	    }
 	
 	    constructor(source: any = {}) {
@@ -15,7 +220,7 @@ export namespace main {
 	    }
 	}
 	export class CompareResult {
-	    missing: INIKeyValue[];
+	    missing: KeyValue[];
 	    currentCount: number;
 	    referenceCount: number;
 	
@@ -25,7 +230,7 @@ export namespace main {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.missing = this.convertValues(source["missing"], INIKeyValue);
+	        this.missing = this.convertValues(source["missing"], KeyValue);
 	        this.currentCount = source["currentCount"];
 	        this.referenceCount = source["referenceCount"];
 	    }
@@ -48,6 +253,522 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class FormatOverride {
+	    eol: string;
+	    bom: string;
+	    trailingNewline: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FormatOverride(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.eol = source["eol"];
+	        this.bom = source["bom"];
+	        this.trailingNewline = source["trailingNewline"];
+	    }
+	}
+	
+	export class MergeEntry {
+	    key: string;
+	    oldEnglish: string;
+	    newEnglish: string;
+	    translation: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.oldEnglish = source["oldEnglish"];
+	        this.newEnglish = source["newEnglish"];
+	        this.translation = source["translation"];
+	    }
+	}
+	export class MergeReport {
+	    outputPath: string;
+	    added: MergeEntry[];
+	    removed: MergeEntry[];
+	    changed: MergeEntry[];
+	    untranslated: MergeEntry[];
+	    unchangedCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.outputPath = source["outputPath"];
+	        this.added = this.convertValues(source["added"], MergeEntry);
+	        this.removed = this.convertValues(source["removed"], MergeEntry);
+	        this.changed = this.convertValues(source["changed"], MergeEntry);
+	        this.untranslated = this.convertValues(source["untranslated"], MergeEntry);
+	        this.unchangedCount = source["unchangedCount"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PlaceholderIssue {
+	    key: string;
+	    line: number;
+	    kind: string;
+	    severity: string;
+	    token: string;
+	    reference: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlaceholderIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.line = source["line"];
+	        this.kind = source["kind"];
+	        this.severity = source["severity"];
+	        this.token = source["token"];
+	        this.reference = source["reference"];
+	        this.value = source["value"];
+	    }
+	}
+	export class StaleEntry {
+	    key: string;
+	    translation: string;
+	    english: string;
+	    recordedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StaleEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.translation = source["translation"];
+	        this.english = source["english"];
+	        this.recordedAt = source["recordedAt"];
+	    }
+	}
+	export class StaleCompareResult {
+	    stale: StaleEntry[];
+	    untracked: string[];
+	    current: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StaleCompareResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stale = this.convertValues(source["stale"], StaleEntry);
+	        this.untracked = source["untracked"];
+	        this.current = source["current"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ValidationReport {
+	    referencePath: string;
+	    checked: number;
+	    errors: number;
+	    warnings: number;
+	    issues: PlaceholderIssue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidationReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.referencePath = source["referencePath"];
+	        this.checked = source["checked"];
+	        this.errors = source["errors"];
+	        this.warnings = source["warnings"];
+	        this.issues = this.convertValues(source["issues"], PlaceholderIssue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Warning {
+	    line: number;
+	    kind: string;
+	    message: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Warning(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.kind = source["kind"];
+	        this.message = source["message"];
+	        this.text = source["text"];
+	    }
+	}
+
+}
+
+export namespace localestore {
+	
+	export class BuildCheck {
+	    locale: string;
+	    localeBuild: gameinstall.BuildInfo;
+	    gameBuild: gameinstall.BuildInfo;
+	    recorded: boolean;
+	    match: boolean;
+	    warning: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BuildCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.locale = source["locale"];
+	        this.localeBuild = this.convertValues(source["localeBuild"], gameinstall.BuildInfo);
+	        this.gameBuild = this.convertValues(source["gameBuild"], gameinstall.BuildInfo);
+	        this.recorded = source["recorded"];
+	        this.match = source["match"];
+	        this.warning = source["warning"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChangedEntry {
+	    key: string;
+	    snapshot: string;
+	    current: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChangedEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.snapshot = source["snapshot"];
+	        this.current = source["current"];
+	    }
+	}
+	export class Layer {
+	    name: string;
+	    kind: string;
+	    enabled: boolean;
+	    source: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Layer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.enabled = source["enabled"];
+	        this.source = source["source"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class LayerStack {
+	    version: number;
+	    layers: Layer[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LayerStack(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.layers = this.convertValues(source["layers"], Layer);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Meta {
+	    gameBuild: gameinstall.BuildInfo;
+	    recordedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Meta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.gameBuild = this.convertValues(source["gameBuild"], gameinstall.BuildInfo);
+	        this.recordedAt = source["recordedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SnapshotLayer {
+	    name: string;
+	    hash: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SnapshotLayer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.hash = source["hash"];
+	    }
+	}
+	export class Snapshot {
+	    id: string;
+	    hash: string;
+	    createdAt: string;
+	    reason: string;
+	    size: number;
+	    layers?: SnapshotLayer[];
+	    layerList?: string;
+	    withLayers: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Snapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.hash = source["hash"];
+	        this.createdAt = source["createdAt"];
+	        this.reason = source["reason"];
+	        this.size = source["size"];
+	        this.layers = this.convertValues(source["layers"], SnapshotLayer);
+	        this.layerList = source["layerList"];
+	        this.withLayers = source["withLayers"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SnapshotDiff {
+	    snapshot: Snapshot;
+	    added: ini.KeyValue[];
+	    removed: ini.KeyValue[];
+	    changed: ChangedEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SnapshotDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.snapshot = this.convertValues(source["snapshot"], Snapshot);
+	        this.added = this.convertValues(source["added"], ini.KeyValue);
+	        this.removed = this.convertValues(source["removed"], ini.KeyValue);
+	        this.changed = this.convertValues(source["changed"], ChangedEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace patchwatch {
+	
+	export class Expected {
+	    channel: string;
+	    locale: string;
+	    iniHash: string;
+	    language: string;
+	    appliedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Expected(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channel = source["channel"];
+	        this.locale = source["locale"];
+	        this.iniHash = source["iniHash"];
+	        this.language = source["language"];
+	        this.appliedAt = source["appliedAt"];
+	    }
+	}
+	export class Status {
+	    expected: Expected;
+	    iniHash: string;
+	    iniMissing: boolean;
+	    language: string;
+	    iniReverted: boolean;
+	    languageReverted: boolean;
+	    reverted: boolean;
+	    checkedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Status(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.expected = this.convertValues(source["expected"], Expected);
+	        this.iniHash = source["iniHash"];
+	        this.iniMissing = source["iniMissing"];
+	        this.language = source["language"];
+	        this.iniReverted = source["iniReverted"];
+	        this.languageReverted = source["languageReverted"];
+	        this.reverted = source["reverted"];
+	        this.checkedAt = source["checkedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -2,7 +2,11 @@ module zh-tool
 
 go 1.23
 
-require github.com/wailsapp/wails/v2 v2.10.2
+require (
+	github.com/klauspost/compress v1.18.4
+	github.com/wailsapp/wails/v2 v2.10.2
+	golang.org/x/text v0.22.0
+)
 
 require (
 	github.com/bep/debounce v1.2.1 // indirect
@@ -31,5 +35,4 @@ require (
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
-	golang.org/x/text v0.22.0 // indirect
 )
@@ -2,6 +2,7 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -12,6 +13,12 @@ import (
 var assets embed.FS
 
 func main() {
+	// 帶有子命令時以命令列模式執行，不開啟視窗
+	if isCLIInvocation(os.Args[1:]) {
+		attachParentConsole()
+		os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
 
@@ -0,0 +1,203 @@
+// Package config 管理 zh-tool 的本機資料位置與 config.json
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DataDir 回傳使用者本機資料目錄：%LOCALAPPDATA%\Squadron978\zh-tool
+func DataDir() string {
+	base := os.Getenv("LOCALAPPDATA")
+	if base == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			base = filepath.Join(home, "AppData", "Local")
+		} else {
+			base = os.TempDir()
+		}
+	}
+	return filepath.Join(base, "Squadron978", "zh-tool")
+}
+
+// TmpDir 回傳本機暫存目錄：%LOCALAPPDATA%\zh-tool\tmp
+func TmpDir() string {
+	base := os.Getenv("LOCALAPPDATA")
+	if base == "" {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "zh-tool", "tmp")
+}
+
+// Path 回傳配置文件路徑
+func Path() string {
+	return filepath.Join(DataDir(), "config.json")
+}
+
+// CorruptError 配置文件無法解析或轉換；原檔已改名備份，改用預設設定
+type CorruptError struct {
+	Path   string
+	Backup string // 備份檔路徑；備份失敗時為空
+	Err    error
+}
+
+func (e *CorruptError) Error() string {
+	if e.Backup == "" {
+		return fmt.Sprintf("config %s is corrupted (%v) and could not be backed up", e.Path, e.Err)
+	}
+	return fmt.Sprintf("config %s is corrupted (%v), moved to %s", e.Path, e.Err, e.Backup)
+}
+
+func (e *CorruptError) Unwrap() error { return e.Err }
+
+// Load 讀取配置文件並轉換到目前的版本；不存在時回傳預設設定
+// 無法解析時將原檔改名為 config.json.corrupt-<時間> 保留，回傳預設設定與 *CorruptError；
+// 其他讀取錯誤回傳預設設定與錯誤（不備份，以免覆蓋仍然完好的檔案）
+func Load(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Default(), fmt.Errorf("read config failed: %w", err)
+	}
+	s, err := decode(data)
+	if err != nil {
+		ce := &CorruptError{Path: path, Err: err}
+		backup := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405"))
+		if rerr := os.Rename(path, backup); rerr == nil {
+			ce.Backup = backup
+		}
+		return Default(), ce
+	}
+	return s, nil
+}
+
+// Save 寫入配置文件（同時更新 version 與 lastUpdated）
+// 先寫入同資料夾的暫存檔並同步到磁碟，再替換原檔，中途中斷也不會留下寫到一半的設定
+func Save(path string, s *Settings) error {
+	if s.Version < SchemaVersion {
+		s.Version = SchemaVersion
+	}
+	s.LastUpdated = time.Now().Format(time.RFC3339)
+	data, err := s.encode()
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	// 確保配置目錄存在
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, ".config-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// updateMu 避免同時修改配置文件時互相覆蓋
+var updateMu sync.Mutex
+
+// Update 讀取配置文件、以 fn 修改後寫回；fn 回傳錯誤時不寫入
+// 配置文件損毀時（已由 Load 備份）以預設設定繼續；其他讀取錯誤直接回傳，避免以預設值覆蓋原檔
+// 成功時回傳寫入的設定，供呼叫端更新快取
+func Update(path string, fn func(s *Settings) error) (*Settings, error) {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+	s, err := Load(path)
+	var ce *CorruptError
+	if err != nil && !errors.As(err, &ce) {
+		return nil, err
+	}
+	if err := fn(s); err != nil {
+		return nil, err
+	}
+	if err := Save(path, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// decode 解析配置文件並依序套用版本轉換
+func decode(data []byte) (*Settings, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("config is not a JSON object")
+	}
+	version, err := schemaVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+	for v := version; v < SchemaVersion; v++ {
+		if err := migrations[v-1](raw); err != nil {
+			return nil, fmt.Errorf("migrate config from version %d failed: %w", v, err)
+		}
+		raw["version"] = v + 1
+	}
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	s := Default()
+	if err := json.Unmarshal(migrated, s); err != nil {
+		return nil, err
+	}
+	s.normalize()
+	// 保留較新版本寫入、本版本不認得的欄位
+	known, err := s.fields()
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range raw {
+		if _, ok := known[k]; ok {
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if s.extra == nil {
+			s.extra = map[string]json.RawMessage{}
+		}
+		s.extra[k] = b
+	}
+	return s, nil
+}
+
+// schemaVersion 讀取配置文件的版本；沒有 version 的舊檔案為第 1 版
+func schemaVersion(raw map[string]interface{}) (int, error) {
+	v, ok := raw["version"]
+	if !ok {
+		return 1, nil
+	}
+	f, ok := v.(float64)
+	if !ok || f < 1 || f != float64(int(f)) {
+		return 0, fmt.Errorf("invalid config version: %v", v)
+	}
+	return int(f), nil
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readRaw(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestLoadMigratesV1(t *testing.T) {
+	path := writeConfig(t, `{
+  "starCitizenPath": "C:\\Program Files\\Roberts Space Industries\\StarCitizen",
+  "patchWatch": {"enabled": true, "autoReapply": true},
+  "futureOption": {"nested": [1, 2]}
+}`)
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != SchemaVersion {
+		t.Errorf("version = %d, want %d", s.Version, SchemaVersion)
+	}
+	if s.StarCitizenPath != `C:\Program Files\Roberts Space Industries\StarCitizen` {
+		t.Errorf("starCitizenPath = %q", s.StarCitizenPath)
+	}
+	want := UpdateSettings{CheckOnStartup: true, WatchGamePatches: true, AutoReapply: true}
+	if s.Updates != want {
+		t.Errorf("updates = %+v, want %+v", s.Updates, want)
+	}
+	// 第 1 版沒有的欄位使用預設值
+	if s.DownloadSource != DefaultDownloadSource || s.LocaleBuilds == nil || s.DriveScan.LibraryFolders == nil {
+		t.Errorf("defaults missing: %+v", s)
+	}
+
+	// 寫回後為第 2 版，舊欄位移除，不認得的欄位原樣保留
+	if err := Save(path, s); err != nil {
+		t.Fatal(err)
+	}
+	raw := readRaw(t, path)
+	if raw["version"] != float64(SchemaVersion) {
+		t.Errorf("saved version = %v", raw["version"])
+	}
+	if _, ok := raw["patchWatch"]; ok {
+		t.Error("patchWatch still present after migration")
+	}
+	if !reflect.DeepEqual(raw["futureOption"], map[string]interface{}{"nested": []interface{}{1.0, 2.0}}) {
+		t.Errorf("futureOption = %v", raw["futureOption"])
+	}
+
+	again, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Updates != want || again.StarCitizenPath != s.StarCitizenPath {
+		t.Errorf("reload = %+v", again)
+	}
+}
+
+func TestLoadMissingReturnsDefault(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, Default()) {
+		t.Errorf("got %+v, want defaults", s)
+	}
+}
+
+func TestLoadCorruptBacksUpOriginal(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+	}{
+		{"invalid json", `{"starCitizenPath": "C:\Games`},
+		{"not an object", `["C:\\Games"]`},
+		{"bad version", `{"version": "two"}`},
+		{"bad patchWatch", `{"patchWatch": "yes"}`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := writeConfig(t, c.content)
+			s, err := Load(path)
+			var ce *CorruptError
+			if !errors.As(err, &ce) {
+				t.Fatalf("err = %v, want *CorruptError", err)
+			}
+			if !reflect.DeepEqual(s, Default()) {
+				t.Errorf("got %+v, want defaults", s)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("corrupt config still at %s", path)
+			}
+			backup, err := os.ReadFile(ce.Backup)
+			if err != nil {
+				t.Fatalf("backup: %v", err)
+			}
+			if string(backup) != c.content {
+				t.Errorf("backup = %q, want the original content", backup)
+			}
+
+			// 之後的寫入建立新檔，不覆蓋備份
+			if _, err := Update(path, func(s *Settings) error {
+				s.GameChannel = "PTU"
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if s, err := Load(path); err != nil || s.GameChannel != "PTU" {
+				t.Errorf("after update: %+v, %v", s, err)
+			}
+			if data, _ := os.ReadFile(ce.Backup); string(data) != c.content {
+				t.Error("backup changed by a later update")
+			}
+		})
+	}
+}
+
+func TestUpdateReturnsSavedSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	saved, err := Update(path, func(s *Settings) error {
+		s.ActiveLocale = "chinese_(traditional)"
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ActiveLocale != "chinese_(traditional)" || saved.LastUpdated == "" {
+		t.Errorf("saved = %+v", saved)
+	}
+
+	// fn 回傳錯誤時不寫入
+	stop := errors.New("stop")
+	if _, err := Update(path, func(s *Settings) error {
+		s.ActiveLocale = "other"
+		return stop
+	}); !errors.Is(err, stop) {
+		t.Fatalf("err = %v, want stop", err)
+	}
+	if s, _ := Load(path); s.ActiveLocale != "chinese_(traditional)" {
+		t.Errorf("ActiveLocale = %q after a failed update", s.ActiveLocale)
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	s := Default()
+	s.DriveScan.LibraryFolders = append(s.DriveScan.LibraryFolders, `D:\Games`)
+	c := s.Clone()
+	c.DriveScan.LibraryFolders[0] = `E:\Games`
+	c.LocaleBuilds["chinese"] = c.LocaleBuilds["chinese"]
+	if s.DriveScan.LibraryFolders[0] != `D:\Games` || len(s.LocaleBuilds) != 0 {
+		t.Errorf("clone shares data with the original: %+v", s)
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import "fmt"
+
+// migrations[i] 將第 i+1 版的配置文件（解析後的 JSON 物件）轉為第 i+2 版；只可新增，不可修改已發布的轉換
+var migrations = []func(raw map[string]interface{}) error{
+	migrateV1,
+}
+
+// migrateV1 第 1 版（沒有 version 的自由格式）轉為第 2 版：
+// patchWatch {enabled, autoReapply} 移到 updates {watchGamePatches, autoReapply}
+func migrateV1(raw map[string]interface{}) error {
+	updates, _ := raw["updates"].(map[string]interface{})
+	if updates == nil {
+		updates = map[string]interface{}{"checkOnStartup": true}
+	}
+	if v, ok := raw["patchWatch"]; ok {
+		pw, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid patchWatch: %v", v)
+		}
+		if enabled, ok := pw["enabled"].(bool); ok {
+			updates["watchGamePatches"] = enabled
+		}
+		if auto, ok := pw["autoReapply"].(bool); ok {
+			updates["autoReapply"] = auto
+		}
+		delete(raw, "patchWatch")
+	}
+	raw["updates"] = updates
+	return nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"encoding/json"
+
+	"zh-tool/pkg/gameinstall"
+	"zh-tool/pkg/ini"
+)
+
+// SchemaVersion 目前的配置文件版本；變更欄位時遞增，並在 migrations 加入轉換
+const SchemaVersion = 2
+
+// DefaultDownloadSource 預設的中文化檔案下載位置
+const DefaultDownloadSource = "https://squadron978.net/api/localization/latest/global.ini"
+
+// Settings config.json 的內容
+type Settings struct {
+	Version         int                              `json:"version"`
+	StarCitizenPath string                           `json:"starCitizenPath"` // 使用者選擇的安裝根目錄
+	GameChannel     string                           `json:"gameChannel"`     // 目前選擇的版本（空字串為 LIVE）
+	DownloadSource  string                           `json:"downloadSource"`  // 中文化檔案的下載位置
+	ActiveLocale    string                           `json:"activeLocale"`    // 最近安裝到遊戲的本機語系
+	Updates         UpdateSettings                   `json:"updates"`
+	UI              UISettings                       `json:"ui"`
+	DriveScan       DriveScanSettings                `json:"driveScan"`
+	INIFormat       ini.FormatOverride               `json:"iniFormat"`
+	LocaleBuilds    map[string]gameinstall.BuildInfo `json:"localeBuilds"` // 各語系檔記錄的遊戲建置
+	LastUpdated     string                           `json:"lastUpdated"`
+
+	extra map[string]json.RawMessage // 較新版本寫入、本版本不認得的欄位，寫入時原樣保留
+}
+
+// UpdateSettings 中文化與遊戲更新的偏好
+type UpdateSettings struct {
+	CheckOnStartup   bool `json:"checkOnStartup"`   // 啟動時檢查中文化是否有新版
+	AutoInstall      bool `json:"autoInstall"`      // 有新版時自動安裝
+	WatchGamePatches bool `json:"watchGamePatches"` // 檢查遊戲更新是否還原了中文化
+	AutoReapply      bool `json:"autoReapply"`      // 遊戲更新還原後自動重新套用（否則詢問）
+}
+
+// UISettings 介面選項
+type UISettings struct {
+	LastPage string `json:"lastPage"` // 上次開啟的頁面（home / localization / shipSorting）
+	ShowLog  bool   `json:"showLog"`  // 顯示執行流程日誌
+}
+
+// DriveScanSettings 磁碟機掃描設定
+type DriveScanSettings struct {
+	Depth          int      `json:"depth"`          // 在遊戲目錄與自訂遊戲庫下往下尋找的層數（0 為預設值）
+	TimeoutSeconds int      `json:"timeoutSeconds"` // 每個磁碟機的時間上限（0 為預設值）
+	LibraryFolders []string `json:"libraryFolders"` // 額外掃描的自訂遊戲庫資料夾
+}
+
+// Default 回傳預設設定
+func Default() *Settings {
+	return &Settings{
+		Version:        SchemaVersion,
+		DownloadSource: DefaultDownloadSource,
+		Updates:        UpdateSettings{CheckOnStartup: true},
+		UI:             UISettings{LastPage: "home", ShowLog: true},
+		DriveScan:      DriveScanSettings{LibraryFolders: []string{}},
+		LocaleBuilds:   map[string]gameinstall.BuildInfo{},
+	}
+}
+
+// Set 以 o 取代所有欄位（例如前端送回的整份設定），保留版本與不認得的欄位
+func (s *Settings) Set(o Settings) {
+	version, extra := s.Version, s.extra
+	*s = o
+	s.Version, s.extra = version, extra
+	s.normalize()
+}
+
+// Clone 回傳深層複本，修改複本的清單與對照表不影響原設定
+func (s *Settings) Clone() *Settings {
+	c := *s
+	c.DriveScan.LibraryFolders = append([]string{}, s.DriveScan.LibraryFolders...)
+	c.LocaleBuilds = make(map[string]gameinstall.BuildInfo, len(s.LocaleBuilds))
+	for k, v := range s.LocaleBuilds {
+		c.LocaleBuilds[k] = v
+	}
+	if s.extra != nil {
+		c.extra = make(map[string]json.RawMessage, len(s.extra))
+		for k, v := range s.extra {
+			c.extra[k] = v
+		}
+	}
+	return &c
+}
+
+// normalize 將 null 的清單與對照表換成空值，前端不必另外判斷
+func (s *Settings) normalize() {
+	if s.LocaleBuilds == nil {
+		s.LocaleBuilds = map[string]gameinstall.BuildInfo{}
+	}
+	if s.DriveScan.LibraryFolders == nil {
+		s.DriveScan.LibraryFolders = []string{}
+	}
+}
+
+// fields 將設定轉為 JSON 物件
+func (s *Settings) fields() (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// encode 輸出配置文件內容（含不認得的欄位）
+func (s *Settings) encode() ([]byte, error) {
+	m, err := s.fields()
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range s.extra {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+	return json.MarshalIndent(m, "", "  ")
+}
@@ -0,0 +1,241 @@
+// Package gamebackup 在第一次修改遊戲檔案前保留原始內容，供解除安裝時還原
+//
+// 備份記錄於版本資料夾的 zh-tool-backup.json：每個被修改過的檔案記錄修改前是否存在，
+// 存在時原檔複製為同目錄的 <檔名>.bak；安裝時建立的資料夾也一併記錄，解除安裝後若已清空即刪除。解除安裝由 installtx 依記錄以交易方式還原（見 OriginalOf）。
+// 提權拷貝程式與主程式共用此套件。
+package gamebackup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ManifestName 版本資料夾中記錄備份的檔案
+const ManifestName = "zh-tool-backup.json"
+
+// BackupSuffix 原檔備份的副檔名（與舊版 zh-tool-copier 的 global.ini.bak 相同）
+const BackupSuffix = ".bak"
+
+// Entry 單一檔案的備份記錄
+type Entry struct {
+	Path      string `json:"path"`    // 相對於版本資料夾，以 / 分隔
+	Existed   bool   `json:"existed"` // 第一次修改前檔案是否存在
+	CreatedAt string `json:"createdAt"`
+}
+
+// Manifest 版本資料夾的備份記錄
+type Manifest struct {
+	Version int      `json:"version"`
+	Entries []Entry  `json:"entries"`
+	Dirs    []string `json:"dirs,omitempty"` // 本工具建立的資料夾（相對於版本資料夾，由淺到深）
+}
+
+func manifestPath(channelDir string) string {
+	return filepath.Join(channelDir, ManifestName)
+}
+
+// Load 讀取備份記錄；不存在時回傳空記錄
+func Load(channelDir string) (*Manifest, error) {
+	m := &Manifest{Version: 1, Entries: []Entry{}}
+	data, err := os.ReadFile(manifestPath(channelDir))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	return m, nil
+}
+
+func (m *Manifest) save(channelDir string) error {
+	p := manifestPath(channelDir)
+	if len(m.Entries) == 0 && len(m.Dirs) == 0 {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write backup manifest failed: %w", err)
+	}
+	return os.Rename(tmp, p)
+}
+
+func (m *Manifest) find(rel string) int {
+	for i, e := range m.Entries {
+		if strings.EqualFold(e.Path, rel) {
+			return i
+		}
+	}
+	return -1
+}
+
+// relPath 將版本資料夾下的路徑轉為記錄用的相對路徑
+func relPath(channelDir, file string) (string, error) {
+	rel, err := filepath.Rel(channelDir, file)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("path outside channel directory: %s", file)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// Protect 在第一次修改 file 之前記錄其原始狀態（存在時複製為 file.bak）；已記錄過則不做任何事
+// 舊版留下的 .bak 視為原檔備份
+func Protect(channelDir, file string) error {
+	rel, err := relPath(channelDir, file)
+	if err != nil {
+		return err
+	}
+	m, err := Load(channelDir)
+	if err != nil {
+		return err
+	}
+	if m.find(rel) >= 0 {
+		return nil
+	}
+	entry := Entry{Path: rel, CreatedAt: time.Now().Format(time.RFC3339)}
+	bak := file + BackupSuffix
+	if _, err := os.Stat(bak); err == nil {
+		entry.Existed = true
+	} else if _, err := os.Stat(file); err == nil {
+		if err := copyFile(file, bak); err != nil {
+			return fmt.Errorf("backup %s failed: %w", filepath.Base(file), err)
+		}
+		entry.Existed = true
+	}
+	m.Entries = append(m.Entries, entry)
+	return m.save(channelDir)
+}
+
+// Original 檔案第一次修改前的狀態
+type Original struct {
+	File   string // 目標檔案
+	Backup string // 原檔備份（.bak）；空字串表示第一次修改前不存在
+}
+
+// OriginalOf 回傳 file 第一次修改前的狀態；沒有記錄也沒有舊版 zh-tool-copier 留下的 .bak 時回傳 false
+func (m *Manifest) OriginalOf(channelDir, file string) (Original, bool, error) {
+	rel, err := relPath(channelDir, file)
+	if err != nil {
+		return Original{}, false, err
+	}
+	bak := file + BackupSuffix
+	i := m.find(rel)
+	if i >= 0 && !m.Entries[i].Existed {
+		return Original{File: file}, true, nil
+	}
+	if _, err := os.Stat(bak); err != nil {
+		if i >= 0 {
+			return Original{}, false, fmt.Errorf("backup of %s is missing: %s", rel, bak)
+		}
+		return Original{}, false, nil
+	}
+	return Original{File: file, Backup: bak}, true, nil
+}
+
+// Forget 還原完成後刪除檔案的備份（.bak）與記錄
+func Forget(channelDir string, files []string) error {
+	m, err := Load(channelDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		rel, err := relPath(channelDir, file)
+		if err != nil {
+			return err
+		}
+		if i := m.find(rel); i >= 0 {
+			m.Entries = append(m.Entries[:i], m.Entries[i+1:]...)
+		}
+		if err := os.Remove(file + BackupSuffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return m.save(channelDir)
+}
+
+// RecordDirs 記錄即將由本工具建立的資料夾（相對於版本資料夾，以 / 分隔，由淺到深）；已記錄過的略過
+func RecordDirs(channelDir string, dirs []string) error {
+	if len(dirs) == 0 {
+		return nil
+	}
+	m, err := Load(channelDir)
+	if err != nil {
+		return err
+	}
+	added := false
+	for _, d := range dirs {
+		rel, err := relPath(channelDir, filepath.Join(channelDir, filepath.FromSlash(d)))
+		if err != nil {
+			return err
+		}
+		if !containsFold(m.Dirs, rel) {
+			m.Dirs = append(m.Dirs, rel)
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+	return m.save(channelDir)
+}
+
+// RemoveEmptyDirs 由深到淺刪除本工具建立且已清空的資料夾，並移除其記錄；仍有其他檔案的資料夾保留記錄
+func RemoveEmptyDirs(channelDir string) error {
+	m, err := Load(channelDir)
+	if err != nil || len(m.Dirs) == 0 {
+		return err
+	}
+	var kept []string
+	for i := len(m.Dirs) - 1; i >= 0; i-- {
+		rel := m.Dirs[i]
+		dir := filepath.Join(channelDir, filepath.FromSlash(rel))
+		if _, err := relPath(channelDir, dir); err != nil {
+			continue
+		}
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+			kept = append([]string{rel}, kept...)
+		}
+	}
+	m.Dirs = kept
+	return m.save(channelDir)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, x := range list {
+		if strings.EqualFold(x, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyFile(src, dst string) error {
+	s, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	d, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(d, s); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
@@ -0,0 +1,200 @@
+package gamebackup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestProtectRecordsFirstState(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "user.cfg")
+	ini := filepath.Join(dir, "data", "Localization", "chinese", "global.ini")
+	writeFile(t, cfg, "r_width=1920\n")
+
+	for _, f := range []string{cfg, ini} {
+		if err := Protect(dir, f); err != nil {
+			t.Fatalf("Protect(%s): %v", f, err)
+		}
+	}
+	// 修改後再次 Protect 不覆蓋第一次的備份
+	writeFile(t, cfg, "g_language=chinese\n")
+	if err := Protect(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, cfg+BackupSuffix); got != "r_width=1920\n" {
+		t.Errorf("backup = %q, want the original content", got)
+	}
+
+	m, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Entries) != 2 {
+		t.Fatalf("entries = %+v, want 2", m.Entries)
+	}
+	orig, ok, err := m.OriginalOf(dir, cfg)
+	if err != nil || !ok || orig.Backup != cfg+BackupSuffix {
+		t.Errorf("OriginalOf(user.cfg) = %+v, %v, %v", orig, ok, err)
+	}
+	// 原本不存在的檔案沒有備份
+	orig, ok, err = m.OriginalOf(dir, ini)
+	if err != nil || !ok || orig.Backup != "" {
+		t.Errorf("OriginalOf(global.ini) = %+v, %v, %v", orig, ok, err)
+	}
+	if _, err := os.Stat(ini + BackupSuffix); !os.IsNotExist(err) {
+		t.Error("backup created for a file that did not exist")
+	}
+}
+
+func TestProtectKeepsLegacyBackup(t *testing.T) {
+	dir := t.TempDir()
+	ini := filepath.Join(dir, "data", "Localization", "chinese", "global.ini")
+	// 舊版 zh-tool-copier 留下的 .bak 是真正的原檔，不可被目前（已修改）的內容覆蓋
+	writeFile(t, ini+BackupSuffix, "original")
+	writeFile(t, ini, "modified")
+	if err := Protect(dir, ini); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, ini+BackupSuffix); got != "original" {
+		t.Errorf("legacy backup overwritten: %q", got)
+	}
+
+	// 沒有記錄時也以舊版的 .bak 為原檔
+	m := &Manifest{}
+	orig, ok, err := m.OriginalOf(dir, ini)
+	if err != nil || !ok || orig.Backup != ini+BackupSuffix {
+		t.Errorf("OriginalOf without record = %+v, %v, %v", orig, ok, err)
+	}
+}
+
+func TestOriginalOfMissingBackup(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "user.cfg")
+	writeFile(t, cfg, "r_width=1920\n")
+	if err := Protect(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(cfg + BackupSuffix); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.OriginalOf(dir, cfg); err == nil {
+		t.Error("expected an error when the recorded backup is missing")
+	}
+	// 從未修改過的檔案沒有原檔記錄
+	if _, ok, err := m.OriginalOf(dir, filepath.Join(dir, "data", "system.cfg")); ok || err != nil {
+		t.Errorf("OriginalOf(untouched) = %v, %v", ok, err)
+	}
+}
+
+func TestForgetRemovesBackupAndRecord(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "user.cfg")
+	sys := filepath.Join(dir, "data", "system.cfg")
+	writeFile(t, cfg, "r_width=1920\n")
+	writeFile(t, sys, "sys_spec=4\n")
+	for _, f := range []string{cfg, sys} {
+		if err := Protect(dir, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Forget(dir, []string{cfg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cfg + BackupSuffix); !os.IsNotExist(err) {
+		t.Error("backup of user.cfg not removed")
+	}
+	m, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Entries) != 1 || m.Entries[0].Path != "data/system.cfg" {
+		t.Errorf("entries = %+v", m.Entries)
+	}
+
+	// 最後一筆記錄移除後刪除記錄檔
+	if err := Forget(dir, []string{sys}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ManifestName)); !os.IsNotExist(err) {
+		t.Error("empty backup manifest not removed")
+	}
+}
+
+func TestProtectRejectsOutsidePath(t *testing.T) {
+	dir := t.TempDir()
+	if err := Protect(dir, filepath.Join(filepath.Dir(dir), "other.cfg")); err == nil {
+		t.Error("Protect accepted a path outside the channel directory")
+	}
+	if err := Protect(dir, dir); err == nil {
+		t.Error("Protect accepted the channel directory itself")
+	}
+}
+
+func TestRemoveEmptyDirs(t *testing.T) {
+	dir := t.TempDir()
+	if err := RecordDirs(dir, []string{"data/Localization", "data/Localization/chinese"}); err != nil {
+		t.Fatal(err)
+	}
+	// 重複記錄不會增加項目；版本資料夾之外的路徑拒絕
+	if err := RecordDirs(dir, []string{"data/localization"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordDirs(dir, []string{"../outside"}); err == nil {
+		t.Error("RecordDirs accepted a path outside the channel directory")
+	}
+	writeFile(t, filepath.Join(dir, "data", "Localization", "chinese", "global.ini"), "a=甲\n")
+
+	// 仍有檔案：不刪除，保留記錄
+	if err := RemoveEmptyDirs(dir); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Dirs) != 2 {
+		t.Fatalf("dirs = %v", m.Dirs)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "data", "Localization", "chinese", "global.ini")); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveEmptyDirs(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data", "Localization")); !os.IsNotExist(err) {
+		t.Error("empty created folders not removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data")); err != nil {
+		t.Errorf("unrecorded parent removed: %v", err)
+	}
+	// 沒有其他記錄時刪除記錄檔
+	if _, err := os.Stat(filepath.Join(dir, ManifestName)); !os.IsNotExist(err) {
+		t.Error("backup manifest not removed")
+	}
+}
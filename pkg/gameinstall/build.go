@@ -0,0 +1,111 @@
+package gameinstall
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// BuildManifestName 版本資料夾中記錄遊戲建置資訊的檔案
+const BuildManifestName = "build_manifest.id"
+
+// BuildInfo 遊戲建置資訊（取自 build_manifest.id）
+type BuildInfo struct {
+	Channel   string `json:"channel"`
+	Branch    string `json:"branch"`    // 例如 sc-alpha-3.24.1
+	Version   string `json:"version"`   // 例如 3.24.1
+	ChangeNum string `json:"changeNum"` // P4 變更編號，同版本號的不同建置以此區分
+	BuildDate string `json:"buildDate"`
+	Tag       string `json:"tag"`
+}
+
+// String 回傳易讀的版本字串，例如 3.24.1 (9227512)
+func (b BuildInfo) String() string {
+	v := b.Version
+	if v == "" {
+		v = b.Branch
+	}
+	if b.ChangeNum != "" {
+		v += " (" + b.ChangeNum + ")"
+	}
+	return v
+}
+
+// Empty 判斷是否沒有任何版本資訊
+func (b BuildInfo) Empty() bool {
+	return b.Version == "" && b.Branch == "" && b.ChangeNum == ""
+}
+
+// SameBuild 判斷兩個建置是否相同：版本號不同即不同，兩者都有變更編號時也需相同
+func (b BuildInfo) SameBuild(o BuildInfo) bool {
+	if b.Version != o.Version {
+		return false
+	}
+	if b.ChangeNum != "" && o.ChangeNum != "" {
+		return b.ChangeNum == o.ChangeNum
+	}
+	return true
+}
+
+// versionPattern 從分支名稱取出版本號
+var versionPattern = regexp.MustCompile(`\d+\.\d+(?:\.\d+)*`)
+
+// ReadBuildInfo 讀取 <scPath>/<channel>/build_manifest.id
+func ReadBuildInfo(scPath, channel string) (BuildInfo, error) {
+	channel, err := NormalizeChannel(channel)
+	if err != nil {
+		return BuildInfo{}, err
+	}
+	p := filepath.Join(ChannelDir(scPath, channel), BuildManifestName)
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return BuildInfo{}, fmt.Errorf("read build manifest failed: %w", err)
+	}
+	info, err := parseBuildManifest(data)
+	if err != nil {
+		return BuildInfo{}, fmt.Errorf("invalid build manifest %s: %w", p, err)
+	}
+	info.Channel = channel
+	return info, nil
+}
+
+// parseBuildManifest 解析 build_manifest.id 的 JSON 內容（{"Data": {"Branch": ..., "RequestedP4ChangeNum": ...}}）
+func parseBuildManifest(data []byte) (BuildInfo, error) {
+	var m struct {
+		Data map[string]interface{} `json:"Data"`
+	}
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+	if err := json.Unmarshal(data, &m); err != nil {
+		return BuildInfo{}, err
+	}
+	if m.Data == nil {
+		return BuildInfo{}, fmt.Errorf("missing Data section")
+	}
+	field := func(name string) string {
+		switch v := m.Data[name].(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case float64:
+			return fmt.Sprintf("%.0f", v)
+		}
+		return ""
+	}
+	info := BuildInfo{
+		Branch:    field("Branch"),
+		ChangeNum: field("RequestedP4ChangeNum"),
+		BuildDate: field("BuildDateStamp"),
+		Tag:       field("Tag"),
+	}
+	info.Version = versionPattern.FindString(field("Version"))
+	if info.Version == "" {
+		info.Version = versionPattern.FindString(info.Branch)
+	}
+	if info.Empty() {
+		return info, fmt.Errorf("no version information")
+	}
+	return info, nil
+}
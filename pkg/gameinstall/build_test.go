@@ -0,0 +1,100 @@
+package gameinstall
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBuildManifest(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		want    BuildInfo
+		wantErr bool
+	}{
+		{
+			name: "live manifest",
+			input: `{"Data": {"Branch": "sc-alpha-3.24.1", "BuildDateStamp": "Sep 23 2024", "RequestedP4ChangeNum": "9227512",
+				"Tag": "public", "Version": ""}}`,
+			want: BuildInfo{Branch: "sc-alpha-3.24.1", Version: "3.24.1", ChangeNum: "9227512", BuildDate: "Sep 23 2024", Tag: "public"},
+		},
+		{
+			// 變更編號為數字、版本欄位優先於分支名稱
+			name:  "numeric change and version field",
+			input: "\uFEFF" + `{"Data": {"Branch": "sc-alpha-4.0", "Version": "4.0.1-ptu", "RequestedP4ChangeNum": 9400123}}`,
+			want:  BuildInfo{Branch: "sc-alpha-4.0", Version: "4.0.1", ChangeNum: "9400123"},
+		},
+		{
+			name:  "change number only",
+			input: `{"Data": {"Branch": "  ", "RequestedP4ChangeNum": "123"}}`,
+			want:  BuildInfo{ChangeNum: "123"},
+		},
+		{name: "missing data", input: `{"Other": {}}`, wantErr: true},
+		{name: "no version information", input: `{"Data": {"Tag": "public"}}`, wantErr: true},
+		{name: "not json", input: "Branch=sc-alpha-3.24.1", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := parseBuildManifest([]byte(c.input))
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got  %+v\nwant %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSameBuild(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b BuildInfo
+		want bool
+	}{
+		{"identical", BuildInfo{Version: "3.24.1", ChangeNum: "1"}, BuildInfo{Version: "3.24.1", ChangeNum: "1"}, true},
+		{"different change", BuildInfo{Version: "3.24.1", ChangeNum: "1"}, BuildInfo{Version: "3.24.1", ChangeNum: "2"}, false},
+		{"different version", BuildInfo{Version: "3.24.1"}, BuildInfo{Version: "3.24.2"}, false},
+		{"one side without change", BuildInfo{Version: "3.24.1", ChangeNum: "1"}, BuildInfo{Version: "3.24.1"}, true},
+		// 分支與日期不影響比對
+		{"branch ignored", BuildInfo{Version: "3.24.1", Branch: "a"}, BuildInfo{Version: "3.24.1", Branch: "b"}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.a.SameBuild(c.b); got != c.want {
+				t.Errorf("SameBuild = %v, want %v", got, c.want)
+			}
+			if got := c.b.SameBuild(c.a); got != c.want {
+				t.Errorf("reversed SameBuild = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestReadBuildInfo(t *testing.T) {
+	scPath := t.TempDir()
+	p := filepath.Join(scPath, "PTU", BuildManifestName)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(`{"Data": {"Branch": "sc-alpha-4.0.1", "RequestedP4ChangeNum": "9400123"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := ReadBuildInfo(scPath, "ptu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Channel != "PTU" || info.String() != "4.0.1 (9400123)" {
+		t.Errorf("info = %+v (%s)", info, info)
+	}
+	if _, err := ReadBuildInfo(scPath, "LIVE"); err == nil {
+		t.Error("ReadBuildInfo succeeded without a manifest")
+	}
+}
@@ -0,0 +1,135 @@
+package gameinstall
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// 安裝路徑的來源；Wine / Proton 前綴為 "prefix:" 加上前綴來源（例如 prefix:lutris）
+const (
+	SourceSaved    = "saved"
+	SourceLauncher = "launcher"
+	SourceCommon   = "common"
+	SourceScan     = "scan"
+	SourcePrefix   = "prefix:"
+)
+
+// ChannelStatus 安裝中一個版本資料夾的狀態
+type ChannelStatus struct {
+	Channel        string     `json:"channel"`
+	Build          *BuildInfo `json:"build,omitempty"` // 沒有 build_manifest.id 時為空
+	Writable       bool       `json:"writable"`        // 版本資料夾與 data 皆可直接寫入
+	NeedsElevation bool       `json:"needsElevation"`  // 寫入時需透過提權拷貝程式（由 installer 判斷）
+}
+
+// Install 找到的一個安裝及其資訊
+type Install struct {
+	Path      string          `json:"path"`
+	Sources   []string        `json:"sources"`   // 找到此安裝的來源（Source*）
+	Channels  []ChannelStatus `json:"channels"`  // 存在的版本（依 Channels 排序）
+	FreeBytes int64           `json:"freeBytes"` // 所在磁碟的可用空間；無法取得時為 -1
+	Preferred bool            `json:"preferred"` // 為已保存的路徑
+}
+
+// Writable 以建立暫存檔的方式檢查資料夾是否可寫入（權限位元無法反映 ACL 與唯讀掛載）
+func Writable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".zh-tool-write-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	f.Close()
+	os.Remove(name)
+	return true
+}
+
+// ChannelWritable 判斷版本資料夾與其 data 目錄是否皆可直接寫入
+func ChannelWritable(scPath, channel string) bool {
+	dir := ChannelDir(scPath, channel)
+	return Writable(dir) && Writable(filepath.Join(dir, "data"))
+}
+
+// pathKey 比對路徑用的鍵：Windows 不分大小寫，並解析符號連結（同一前綴可能經由不同連結出現）
+func pathKey(p string) string {
+	key := filepath.Clean(p)
+	if real, err := filepath.EvalSymlinks(key); err == nil {
+		key = real
+	}
+	if runtime.GOOS == "windows" {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// Describe 讀取安裝的版本、建置版本、可寫入狀態與磁碟可用空間
+func Describe(path string) Install {
+	inst := Install{Path: path, Sources: []string{}, Channels: []ChannelStatus{}, FreeBytes: -1}
+	for _, ch := range ListChannels(path) {
+		st := ChannelStatus{Channel: ch, Writable: ChannelWritable(path, ch)}
+		if build, err := ReadBuildInfo(path, ch); err == nil {
+			st.Build = &build
+		}
+		inst.Channels = append(inst.Channels, st)
+	}
+	if free, err := FreeSpace(path); err == nil {
+		inst.FreeBytes = int64(free)
+	}
+	return inst
+}
+
+// Discover 找出所有有效的安裝：已保存的路徑、RSI Launcher 記錄的安裝、Wine / Proton 前綴中的安裝（Windows 以外）、
+// 常見路徑、磁碟機（僅 Windows）與自訂遊戲庫的掃描（見 Scan）；同一安裝出現在多個來源時合併，依第一次出現的順序排列
+// ctx 取消時略過尚未完成的掃描，回傳已找到的安裝
+func Discover(ctx context.Context, saved string, scan ScanOptions) []Install {
+	var order []string
+	sources := map[string][]string{}
+	paths := map[string]string{}
+	add := func(p, source string) {
+		if p == "" || !Validate(p) {
+			return
+		}
+		key := pathKey(p)
+		if _, ok := paths[key]; !ok {
+			paths[key] = p
+			order = append(order, key)
+		}
+		if !containsFold(sources[key], source) {
+			sources[key] = append(sources[key], source)
+		}
+	}
+
+	add(saved, SourceSaved)
+	for _, p := range DetectFromLauncher() {
+		add(p, SourceLauncher)
+	}
+	if runtime.GOOS != "windows" {
+		for _, prefix := range WinePrefixes() {
+			for _, p := range PrefixCandidates(prefix.Path) {
+				add(p, SourcePrefix+prefix.Source)
+			}
+		}
+	}
+	for _, p := range CommonPaths() {
+		add(p, SourceCommon)
+	}
+	scanned, _ := Scan(ctx, scan)
+	for _, p := range scanned {
+		add(p, SourceScan)
+	}
+
+	savedKey := ""
+	if saved != "" {
+		savedKey = pathKey(saved)
+	}
+	result := make([]Install, 0, len(order))
+	for _, key := range order {
+		inst := Describe(paths[key])
+		inst.Sources = sources[key]
+		inst.Preferred = key == savedKey
+		result = append(result, inst)
+	}
+	return result
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package gameinstall
+
+import "syscall"
+
+// FreeSpace 回傳 path 所在檔案系統中目前使用者可用的空間（位元組）
+func FreeSpace(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package gameinstall
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// FreeSpace 回傳 path 所在磁碟中目前使用者可用的空間（位元組，已扣除配額）
+func FreeSpace(path string) (uint64, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	proc := syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+	var free, total, totalFree uint64
+	r, _, e := proc.Call(
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&free)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&totalFree)),
+	)
+	if r == 0 {
+		return 0, e
+	}
+	return free, nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package gameinstall
+
+// logicalDriveMask 其他平台沒有磁碟機代號
+func logicalDriveMask() uint32 {
+	return 0
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package gameinstall
+
+import "syscall"
+
+// logicalDriveMask 以 GetLogicalDrives 取得磁碟機的位元遮罩（bit 0 為 A:）
+func logicalDriveMask() uint32 {
+	r, _, _ := syscall.NewLazyDLL("kernel32.dll").NewProc("GetLogicalDrives").Call()
+	return uint32(r)
+}
@@ -0,0 +1,166 @@
+// Package gameinstall 尋找與驗證 Star Citizen 的安裝路徑
+package gameinstall
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultChannel 未指定版本時使用的版本資料夾
+const DefaultChannel = "LIVE"
+
+// Channels 支援的版本資料夾（依常用程度排列）
+var Channels = []string{"LIVE", "PTU", "EPTU", "TECH-PREVIEW"}
+
+// NormalizeChannel 將版本名稱轉為資料夾名稱；空字串視為 LIVE，不支援的名稱回傳錯誤
+func NormalizeChannel(channel string) (string, error) {
+	channel = strings.TrimSpace(channel)
+	if channel == "" {
+		return DefaultChannel, nil
+	}
+	for _, c := range Channels {
+		if strings.EqualFold(c, channel) {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported channel: %s", channel)
+}
+
+// ChannelDir 回傳版本資料夾：<scPath>/<channel>
+func ChannelDir(scPath, channel string) string {
+	return filepath.Join(scPath, channel)
+}
+
+// DataDir 回傳版本的 data 目錄：<scPath>/<channel>/data
+func DataDir(scPath, channel string) string {
+	return filepath.Join(ChannelDir(scPath, channel), "data")
+}
+
+// LocalizationDir 回傳遊戲中指定語系的資料夾：<scPath>/<channel>/data/Localization/<locale>
+func LocalizationDir(scPath, channel, localeName string) string {
+	return filepath.Join(DataDir(scPath, channel), "Localization", localeName)
+}
+
+// P4KPath 回傳版本資料夾中的 Data.p4k；不存在時回傳錯誤
+func P4KPath(scPath, channel string) (string, error) {
+	channel, err := NormalizeChannel(channel)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []string{"Data.p4k", "data.p4k"} {
+		p := filepath.Join(ChannelDir(scPath, channel), name)
+		if st, err := os.Stat(p); err == nil && !st.IsDir() {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("Data.p4k not found in %s", ChannelDir(scPath, channel))
+}
+
+// ListChannels 列出安裝根目錄下實際存在的版本資料夾
+func ListChannels(scPath string) []string {
+	result := []string{}
+	if scPath == "" {
+		return result
+	}
+	for _, c := range Channels {
+		if hasGameFiles(ChannelDir(scPath, c)) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// hasGameFiles 檢查目錄中是否有遊戲檔案（Bin64、Data 或 data.p4k）
+func hasGameFiles(base string) bool {
+	indicators := []string{
+		filepath.Join(base, "Bin64"),
+		filepath.Join(base, "Data"),
+		filepath.Join(base, "Data", "data.p4k"),
+		filepath.Join(base, "data.p4k"),
+	}
+	for _, p := range indicators {
+		if _, err := os.Stat(p); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 驗證路徑是否為有效的 Star Citizen 目錄
+func Validate(path string) bool {
+	if path == "" {
+		return false
+	}
+
+	// 安裝根目錄本身，或其下任一版本資料夾（LIVE / PTU / EPTU / TECH-PREVIEW）
+	if hasGameFiles(path) {
+		return true
+	}
+	return len(ListChannels(path)) > 0
+}
+
+// CommonPaths 回傳目前平台上常見的安裝路徑
+func CommonPaths() []string {
+	switch runtime.GOOS {
+	case "windows":
+		return []string{
+			filepath.Join(os.Getenv("ProgramFiles"), "Roberts Space Industries", "StarCitizen"),
+			filepath.Join(os.Getenv("ProgramFiles(x86)"), "Roberts Space Industries", "StarCitizen"),
+			filepath.Join("C:", "Program Files", "Roberts Space Industries", "StarCitizen"),
+		}
+	case "linux":
+		homeDir, _ := os.UserHomeDir()
+		return []string{
+			filepath.Join(homeDir, ".wine", "drive_c", "Program Files", "Roberts Space Industries", "StarCitizen"),
+			filepath.Join(homeDir, "Games", "StarCitizen"),
+		}
+	}
+	return nil
+}
+
+// Detect 依序使用 RSI Launcher 設定與日誌記錄的安裝、Wine / Proton 前綴中的安裝（Windows 以外）、常見路徑，
+// 最後才掃描磁碟機（僅 Windows）與 scan 指定的自訂遊戲庫；找不到或 ctx 取消時回傳空字串
+func Detect(ctx context.Context, scan ScanOptions) string {
+	if paths := DetectFromLauncher(); len(paths) > 0 {
+		return paths[0]
+	}
+	if runtime.GOOS != "windows" {
+		if paths := DetectInPrefixes(); len(paths) > 0 {
+			return paths[0]
+		}
+	}
+	for _, path := range CommonPaths() {
+		if _, err := os.Stat(path); err == nil && Validate(path) {
+			return path
+		}
+	}
+	if paths, _ := Scan(ctx, scan); len(paths) > 0 {
+		return paths[0]
+	}
+	return ""
+}
+
+// ScanDrives 以預設設定掃描所有磁碟機，回傳第一個找到的 Star Citizen 安裝路徑
+func ScanDrives() string {
+	if paths, _ := Scan(context.Background(), ScanOptions{}); len(paths) > 0 {
+		return paths[0]
+	}
+	return ""
+}
+
+// LogicalDrives 獲取所有邏輯磁碟機（Windows，略過 A: 與 B:）
+// 由系統的磁碟機清單取得，不存取磁碟機本身，已中斷的網路磁碟機不會讓列舉卡住
+func LogicalDrives() []string {
+	mask := logicalDriveMask()
+	drives := []string{}
+	for i := 'C'; i <= 'Z'; i++ {
+		if mask&(1<<uint(i-'A')) != 0 {
+			drives = append(drives, string(i)+":")
+		}
+	}
+	return drives
+}
@@ -0,0 +1,305 @@
+package gameinstall
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RSI Launcher 將設定（*.json）與日誌（logs/log.log 等）存放於 %APPDATA%\rsilauncher。
+// 設定中的遊戲庫資料夾（預設 C:\Program Files\Roberts Space Industries）之下為 StarCitizen\<版本>；
+// 日誌則記錄每次啟動、安裝與驗證的完整路徑。解析兩者即可找出所有已設定的安裝，不必猜測路徑。
+
+// maxLauncherFileSize 解析的設定或日誌檔大小上限，避免讀入異常大的檔案
+const maxLauncherFileSize = 16 << 20
+
+// LauncherInstall 自 RSI Launcher 設定或日誌找到的安裝
+type LauncherInstall struct {
+	Path     string   `json:"path"`     // 安裝根目錄（...\StarCitizen）
+	Channels []string `json:"channels"` // 設定或日誌中出現過的版本（依 Channels 排序）
+	Sources  []string `json:"sources"`  // 出現的檔案
+}
+
+// LauncherDir 回傳 RSI Launcher 的設定資料夾：%APPDATA%\rsilauncher
+func LauncherDir() string {
+	base := os.Getenv("APPDATA")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, "AppData", "Roaming")
+	}
+	return filepath.Join(base, "rsilauncher")
+}
+
+// drivePathStart 路徑的開頭（磁碟機代號）；日誌中一行可能有多個路徑，以此切開
+var drivePathStart = regexp.MustCompile(`[A-Za-z]:[\\/]`)
+
+// absPath 絕對路徑（Windows 磁碟機代號或 / 開頭）
+var absPath = regexp.MustCompile(`^(?:[A-Za-z]:[\\/]|/[^/])`)
+
+// splitPathElements 以 \ 或 / 切開路徑
+func splitPathElements(p string) []string {
+	return strings.FieldsFunc(p, func(r rune) bool { return r == '\\' || r == '/' })
+}
+
+// pathSeparator 回傳路徑使用的分隔符號（Windows 路徑為 \）
+func pathSeparator(p string) string {
+	if strings.Contains(p, "\\") || !strings.Contains(p, "/") {
+		return "\\"
+	}
+	return "/"
+}
+
+// channelPrefix 取出元素開頭的版本名稱（日誌中路徑後常接著 ) 或引號）
+func channelPrefix(elem string) string {
+	end := 0
+	for end < len(elem) {
+		c := elem[end]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || c == '-' {
+			end++
+			continue
+		}
+		break
+	}
+	ch, err := NormalizeChannel(elem[:end])
+	if err != nil || end == 0 {
+		return ""
+	}
+	return ch
+}
+
+// installFromPath 由路徑推出安裝根目錄與版本：路徑中含有 StarCitizen 資料夾時取到該層，
+// 後面接著版本資料夾時一併回傳；library 為 true 時路徑本身是遊戲庫資料夾，根目錄為其下的 StarCitizen
+func installFromPath(p string, library bool) (root, channel string, ok bool) {
+	p = strings.TrimSpace(p)
+	if !absPath.MatchString(p) {
+		return "", "", false
+	}
+	sep := pathSeparator(p)
+	elems := splitPathElements(p)
+	prefix := ""
+	if strings.HasPrefix(p, "/") {
+		prefix = "/"
+	}
+	for i, e := range elems {
+		if !strings.EqualFold(e, "StarCitizen") {
+			continue
+		}
+		root = prefix + strings.Join(elems[:i+1], sep)
+		if i+1 < len(elems) {
+			channel = channelPrefix(elems[i+1])
+		}
+		return root, channel, true
+	}
+	if library && len(elems) > 0 {
+		// 去掉結尾可能夾帶的引號等字元
+		elems[len(elems)-1] = strings.TrimRight(elems[len(elems)-1], `"') ,;`)
+		return prefix + strings.Join(elems, sep) + sep + "StarCitizen", "", true
+	}
+	return "", "", false
+}
+
+// launcherCollector 依安裝根目錄合併結果（不分大小寫），保留第一次出現的順序
+type launcherCollector struct {
+	order []string
+	byKey map[string]*LauncherInstall
+}
+
+func newLauncherCollector() *launcherCollector {
+	return &launcherCollector{byKey: map[string]*LauncherInstall{}}
+}
+
+func (c *launcherCollector) add(root, channel, source string) {
+	key := strings.ToLower(strings.ReplaceAll(root, "/", "\\"))
+	inst := c.byKey[key]
+	if inst == nil {
+		inst = &LauncherInstall{Path: root, Channels: []string{}, Sources: []string{}}
+		c.byKey[key] = inst
+		c.order = append(c.order, key)
+	}
+	if channel != "" && !containsFold(inst.Channels, channel) {
+		inst.Channels = append(inst.Channels, channel)
+	}
+	if source != "" && !containsFold(inst.Sources, source) {
+		inst.Sources = append(inst.Sources, source)
+	}
+}
+
+func (c *launcherCollector) result() []LauncherInstall {
+	result := make([]LauncherInstall, 0, len(c.order))
+	for _, key := range c.order {
+		inst := *c.byKey[key]
+		sort.SliceStable(inst.Channels, func(i, j int) bool {
+			return channelRank(inst.Channels[i]) < channelRank(inst.Channels[j])
+		})
+		result = append(result, inst)
+	}
+	return result
+}
+
+func channelRank(ch string) int {
+	for i, c := range Channels {
+		if c == ch {
+			return i
+		}
+	}
+	return len(Channels)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, x := range list {
+		if strings.EqualFold(x, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLauncherSettings 解析 RSI Launcher 的 JSON 設定檔，回傳其中的安裝
+// 鍵名（含上層鍵名）含有 library / install 的字串視為遊戲庫或安裝資料夾；其他字串值只在路徑含有 StarCitizen 時採用
+func ParseLauncherSettings(r io.Reader, source string) ([]LauncherInstall, error) {
+	var v interface{}
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, err
+	}
+	c := newLauncherCollector()
+	collectSettings(c, v, "", source)
+	return c.result(), nil
+}
+
+func collectSettings(c *launcherCollector, v interface{}, key, source string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectSettings(c, t[k], key+"."+k, source)
+		}
+	case []interface{}:
+		for _, x := range t {
+			collectSettings(c, x, key, source)
+		}
+	case string:
+		lower := strings.ToLower(key)
+		library := strings.Contains(lower, "library") || strings.Contains(lower, "install")
+		if root, ch, ok := installFromPath(t, library); ok {
+			c.add(root, ch, source)
+		}
+	}
+}
+
+// ParseLauncherLog 解析 RSI Launcher 的日誌，回傳其中出現過的安裝
+func ParseLauncherLog(r io.Reader, source string) ([]LauncherInstall, error) {
+	c := newLauncherCollector()
+	if err := collectLog(c, r, source); err != nil {
+		return nil, err
+	}
+	return c.result(), nil
+}
+
+func collectLog(c *launcherCollector, r io.Reader, source string) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		// 日誌中的 JSON 片段會把 \ 寫成 \\
+		line := strings.ReplaceAll(sc.Text(), `\\`, `\`)
+		lower := strings.ToLower(line)
+		if !strings.Contains(lower, "starcitizen") && !strings.Contains(lower, "library") {
+			continue
+		}
+		idx := drivePathStart.FindAllStringIndex(line, -1)
+		for i, m := range idx {
+			end := len(line)
+			if i+1 < len(idx) {
+				end = idx[i+1][0]
+			}
+			segment := line[m[0]:end]
+			// 遊戲庫設定的日誌只有資料夾本身，例如 libraryFolder: "D:\Games"
+			library := strings.Contains(strings.ToLower(line[:m[0]]), "library")
+			if !strings.Contains(strings.ToLower(segment), "starcitizen") && library {
+				segment = strings.TrimRight(strings.SplitN(segment, `"`, 2)[0], " ,;)")
+			}
+			if root, ch, ok := installFromPath(segment, library); ok {
+				c.add(root, ch, source)
+			}
+		}
+	}
+	return sc.Err()
+}
+
+// LauncherInstalls 讀取 RSI Launcher 設定資料夾中的設定檔與日誌，合併找到的安裝（不檢查是否存在）
+// 設定檔優先，其次為日誌（較新的檔案優先）；dir 可指定其他資料夾以便以範例檔驗證
+func LauncherInstalls(dir string) []LauncherInstall {
+	c := newLauncherCollector()
+	if dir == "" {
+		return c.result()
+	}
+	var settings, logs []string
+	for _, pattern := range []string{"*.json", filepath.Join("library", "*.json")} {
+		m, _ := filepath.Glob(filepath.Join(dir, pattern))
+		settings = append(settings, m...)
+	}
+	for _, pattern := range []string{"*.log", filepath.Join("logs", "*.log")} {
+		m, _ := filepath.Glob(filepath.Join(dir, pattern))
+		logs = append(logs, m...)
+	}
+	sort.SliceStable(logs, func(i, j int) bool { return modTime(logs[i]) > modTime(logs[j]) })
+
+	for _, p := range settings {
+		if f, ok := openLauncherFile(p); ok {
+			var v interface{}
+			if err := json.NewDecoder(f).Decode(&v); err == nil {
+				collectSettings(c, v, "", p)
+			}
+			f.Close()
+		}
+	}
+	for _, p := range logs {
+		if f, ok := openLauncherFile(p); ok {
+			_ = collectLog(c, f, p)
+			f.Close()
+		}
+	}
+	return c.result()
+}
+
+func openLauncherFile(p string) (*os.File, bool) {
+	st, err := os.Stat(p)
+	if err != nil || st.IsDir() || st.Size() > maxLauncherFileSize {
+		return nil, false
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
+
+func modTime(p string) int64 {
+	st, err := os.Stat(p)
+	if err != nil {
+		return 0
+	}
+	return st.ModTime().UnixNano()
+}
+
+// DetectFromLauncher 回傳 RSI Launcher 設定與日誌中記錄、且實際存在的安裝根目錄
+func DetectFromLauncher() []string {
+	result := []string{}
+	for _, inst := range LauncherInstalls(LauncherDir()) {
+		if Validate(inst.Path) {
+			result = append(result, inst.Path)
+		}
+	}
+	return result
+}
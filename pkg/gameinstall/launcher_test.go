@@ -0,0 +1,188 @@
+package gameinstall
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// launcherTestDir 範例 RSI Launcher 設定資料夾
+var launcherTestDir = filepath.Join("testdata", "launcher")
+
+// installSummary 只比對安裝根目錄與版本
+type installSummary struct {
+	Path     string
+	Channels []string
+}
+
+func summarize(installs []LauncherInstall) []installSummary {
+	out := make([]installSummary, 0, len(installs))
+	for _, inst := range installs {
+		out = append(out, installSummary{inst.Path, inst.Channels})
+	}
+	return out
+}
+
+func TestParseLauncherSettings(t *testing.T) {
+	fixture := filepath.Join(launcherTestDir, "settings.json")
+	cases := []struct {
+		name    string
+		file    string // testdata 中的檔案；空字串時使用 input
+		input   string
+		want    []installSummary
+		wantErr bool
+	}{
+		{
+			name: "fixture",
+			file: fixture,
+			// 依鍵名排序走訪：games、library、proton
+			want: []installSummary{
+				{`C:\Program Files\Roberts Space Industries\StarCitizen`, []string{"LIVE"}},
+				{`D:\Games\RSI\StarCitizen`, []string{"PTU", "EPTU"}},
+				{"E:/SC Library/StarCitizen", []string{}},
+				{"/home/pilot/Games/star-citizen/drive_c/Program Files/Roberts Space Industries/StarCitizen", []string{"TECH-PREVIEW"}},
+			},
+		},
+		{
+			name:    "truncated",
+			file:    filepath.Join(launcherTestDir, "library", "truncated.json"),
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			input:   "StarCitizen=C:\\Games\\StarCitizen\\LIVE",
+			wantErr: true,
+		},
+		{
+			name:  "library folder with trailing quote",
+			input: `{"libraryFolder": "K:\\RSI\" "}`,
+			want:  []installSummary{{`K:\RSI\StarCitizen`, []string{}}},
+		},
+		{
+			name:  "unknown channel folder",
+			input: `{"recent": ["C:\\StarCitizen\\EVOCATI\\Bin64", "C:\\starcitizen\\ptu"]}`,
+			want:  []installSummary{{`C:\StarCitizen`, []string{"PTU"}}},
+		},
+		{
+			name:  "no paths",
+			input: `{"theme": "dark", "paths": ["relative\\StarCitizen", 42, null, true]}`,
+			want:  []installSummary{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			input, source := c.input, "inline.json"
+			if c.file != "" {
+				data, err := os.ReadFile(c.file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				input, source = string(data), c.file
+			}
+			got, err := ParseLauncherSettings(strings.NewReader(input), source)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", summarize(got))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := summarize(got); !reflect.DeepEqual(s, c.want) {
+				t.Errorf("got  %v\nwant %v", s, c.want)
+			}
+			for _, inst := range got {
+				if !reflect.DeepEqual(inst.Sources, []string{source}) {
+					t.Errorf("%s: sources = %v, want [%s]", inst.Path, inst.Sources, source)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLauncherLog(t *testing.T) {
+	cases := []struct {
+		name  string
+		file  string // testdata 中的檔案；空字串時使用 input
+		input string
+		want  []installSummary
+	}{
+		{
+			name: "fixture",
+			file: filepath.Join(launcherTestDir, "logs", "log.log"),
+			want: []installSummary{
+				{`F:\RSI Games\StarCitizen`, []string{"PTU"}},
+				{`C:\Program Files\Roberts Space Industries\StarCitizen`, []string{"LIVE", "EPTU", "TECH-PREVIEW"}},
+				{`G:\SC\StarCitizen`, []string{"TECH-PREVIEW"}},
+				{`H:\StarCitizen`, []string{}},
+			},
+		},
+		{
+			name:  "escaped json path",
+			input: `[info] {"path":"D:\\\\Roberts Space Industries\\\\StarCitizen\\\\PTU\\\\Bin64"}`,
+			want:  []installSummary{{`D:\Roberts Space Industries\StarCitizen`, []string{"PTU"}}},
+		},
+		{
+			name:  "forward slashes",
+			input: "[info] installing to E:/Games/StarCitizen/EPTU/",
+			want:  []installSummary{{"E:/Games/StarCitizen", []string{"EPTU"}}},
+		},
+		{
+			name:  "paths without the game",
+			input: "[info] StarCitizen helper at C:\\Windows\\System32\\rsi.dll\n[info] starcitizen.exe crashed\n\x00\x01\x02",
+			want:  []installSummary{},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  []installSummary{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			input, source := c.input, "inline.log"
+			if c.file != "" {
+				data, err := os.ReadFile(c.file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				input, source = string(data), c.file
+			}
+			got, err := ParseLauncherLog(strings.NewReader(input), source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := summarize(got); !reflect.DeepEqual(s, c.want) {
+				t.Errorf("got  %v\nwant %v", s, c.want)
+			}
+		})
+	}
+}
+
+func TestLauncherInstallsMergesSettingsAndLogs(t *testing.T) {
+	got := LauncherInstalls(launcherTestDir)
+	byPath := map[string]LauncherInstall{}
+	for _, inst := range got {
+		byPath[inst.Path] = inst
+	}
+	// 設定檔在前：同一安裝的路徑以設定檔的寫法為準，日誌補上其他版本
+	live, ok := byPath[`C:\Program Files\Roberts Space Industries\StarCitizen`]
+	if !ok {
+		t.Fatalf("missing default install in %v", summarize(got))
+	}
+	if want := []string{"LIVE", "EPTU", "TECH-PREVIEW"}; !reflect.DeepEqual(live.Channels, want) {
+		t.Errorf("channels = %v, want %v", live.Channels, want)
+	}
+	if len(live.Sources) != 2 {
+		t.Errorf("sources = %v, want settings and log", live.Sources)
+	}
+	if len(got) != 7 {
+		t.Errorf("got %d installs, want 7: %v", len(got), summarize(got))
+	}
+	if got := LauncherInstalls(""); len(got) != 0 {
+		t.Errorf("LauncherInstalls(\"\") = %v", got)
+	}
+}
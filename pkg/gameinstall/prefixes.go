@@ -0,0 +1,302 @@
+package gameinstall
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Linux 玩家以 Wine / Proton 執行 RSI Launcher，遊戲位於某個前綴的 drive_c 之下：
+// 自訂的 WINEPREFIX、預設的 ~/.wine、Lutris 遊戲設定（~/.config/lutris/games/*.yml）中的 prefix，
+// 以及 Steam 各遊戲庫的 steamapps/compatdata/<appid>/pfx（以「非 Steam 遊戲」加入的 RSI Launcher）。
+// 列出這些前綴後，再於每個前綴中尋找預設安裝路徑與前綴內 RSI Launcher 設定記錄的遊戲庫。
+
+// 前綴的來源
+const (
+	PrefixSourceEnv    = "WINEPREFIX"
+	PrefixSourceWine   = "wine"
+	PrefixSourceLutris = "lutris"
+	PrefixSourceSteam  = "steam"
+	PrefixSourceGames  = "games"
+)
+
+// WinePrefix 找到的 Wine / Proton 前綴
+type WinePrefix struct {
+	Path   string `json:"path"`   // 前綴目錄（其下有 drive_c）
+	Source string `json:"source"` // 來源（PrefixSource*）
+}
+
+// lutrisKey Lutris 遊戲設定中 game: 區段的 prefix / exe 鍵
+var lutrisKey = regexp.MustCompile(`^\s+(prefix|exe):\s*(.*?)\s*$`)
+
+// steamLibraryPath libraryfolders.vdf 中的 "path" 鍵
+var steamLibraryPath = regexp.MustCompile(`"path"\s+"((?:[^"\\]|\\.)*)"`)
+
+// vdfUnescape 還原 VDF 字串中跳脫的反斜線與引號
+var vdfUnescape = strings.NewReplacer(`\\`, `\`, `\"`, `"`)
+
+// windowsDrive Windows 路徑的磁碟機代號
+var windowsDrive = regexp.MustCompile(`^([A-Za-z]):(?:[\\/]|$)`)
+
+// ParseLutrisGameConfig 解析 Lutris 的遊戲設定（YAML），回傳 game: 區段中的前綴
+// 沒有 prefix 時由 exe 路徑中 drive_c 之前的部分推出前綴
+func ParseLutrisGameConfig(r io.Reader) ([]string, error) {
+	var prefix, fromExe string
+	inGame := false
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			inGame = strings.TrimSpace(line) == "game:"
+			continue
+		}
+		if !inGame {
+			continue
+		}
+		m := lutrisKey.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value := strings.Trim(m[2], `"'`)
+		switch m[1] {
+		case "prefix":
+			prefix = value
+		case "exe":
+			if i := strings.Index(value, "/drive_c/"); i > 0 {
+				fromExe = value[:i]
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	result := []string{}
+	for _, p := range []string{prefix, fromExe} {
+		if p != "" && !containsFold(result, p) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// ParseSteamLibraryFolders 解析 Steam 的 libraryfolders.vdf，回傳各遊戲庫資料夾
+func ParseSteamLibraryFolders(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxLauncherFileSize))
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	for _, m := range steamLibraryPath.FindAllStringSubmatch(string(data), -1) {
+		p := vdfUnescape.Replace(m[1])
+		if p != "" && !containsFold(result, p) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// WindowsPathInPrefix 將前綴內程式看到的 Windows 路徑轉為實際路徑：
+// C: 對應 <prefix>/drive_c，其他磁碟機經由 <prefix>/dosdevices/<x>: 連結；已是 / 開頭的路徑原樣回傳
+func WindowsPathInPrefix(prefix, winPath string) (string, bool) {
+	winPath = strings.TrimSpace(winPath)
+	if strings.HasPrefix(winPath, "/") {
+		return filepath.Clean(winPath), true
+	}
+	m := windowsDrive.FindStringSubmatch(winPath)
+	if m == nil {
+		return "", false
+	}
+	drive := strings.ToLower(m[1])
+	base := filepath.Join(prefix, "dosdevices", drive+":")
+	if drive == "c" {
+		base = filepath.Join(prefix, "drive_c")
+	}
+	elems := append([]string{base}, splitPathElements(winPath[len(m[0]):])...)
+	return filepath.Join(elems...), true
+}
+
+// isPrefix 檢查目錄是否為 Wine 前綴（其下有 drive_c）
+func isPrefix(dir string) bool {
+	st, err := os.Stat(filepath.Join(dir, "drive_c"))
+	return err == nil && st.IsDir()
+}
+
+// prefixCollector 依實際路徑合併前綴（同一前綴可能經由符號連結出現多次），保留第一次出現的順序
+type prefixCollector struct {
+	result []WinePrefix
+	seen   map[string]bool
+}
+
+func (c *prefixCollector) add(dir, source string) {
+	if dir == "" || !isPrefix(dir) {
+		return
+	}
+	key := pathKey(dir)
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.result = append(c.result, WinePrefix{Path: filepath.Clean(dir), Source: source})
+}
+
+// envDir 回傳環境變數指定的資料夾，未設定時為 home 下的預設資料夾
+func envDir(name, home string, fallback ...string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return filepath.Join(append([]string{home}, fallback...)...)
+}
+
+// expandHome 展開設定檔中以 ~ 開頭的路徑
+func expandHome(p, home string) string {
+	if p == "~" {
+		return home
+	}
+	if strings.HasPrefix(p, "~/") {
+		return filepath.Join(home, p[2:])
+	}
+	return p
+}
+
+// lutrisGameDirs 回傳 Lutris 存放遊戲設定的資料夾（新版位於資料目錄，舊版位於設定目錄，另含 Flatpak 版）
+func lutrisGameDirs(home string) []string {
+	flatpak := filepath.Join(home, ".var", "app", "net.lutris.Lutris")
+	return []string{
+		filepath.Join(envDir("XDG_DATA_HOME", home, ".local", "share"), "lutris", "games"),
+		filepath.Join(envDir("XDG_CONFIG_HOME", home, ".config"), "lutris", "games"),
+		filepath.Join(flatpak, "data", "lutris", "games"),
+		filepath.Join(flatpak, "config", "lutris", "games"),
+	}
+}
+
+// steamRoots 回傳 Steam 的安裝資料夾（原生與 Flatpak 版）
+func steamRoots(home string) []string {
+	flatpak := filepath.Join(home, ".var", "app", "com.valvesoftware.Steam")
+	return []string{
+		filepath.Join(home, ".steam", "steam"),
+		filepath.Join(home, ".steam", "root"),
+		filepath.Join(envDir("XDG_DATA_HOME", home, ".local", "share"), "Steam"),
+		filepath.Join(flatpak, ".local", "share", "Steam"),
+		filepath.Join(flatpak, "data", "Steam"),
+	}
+}
+
+// SteamLibraries 回傳所有 Steam 遊戲庫資料夾：Steam 本身與 libraryfolders.vdf 中列出的資料夾
+func SteamLibraries(home string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	add := func(p string) {
+		key := pathKey(p)
+		if st, err := os.Stat(key); err != nil || !st.IsDir() || seen[key] {
+			return
+		}
+		seen[key] = true
+		result = append(result, filepath.Clean(p))
+	}
+	for _, root := range steamRoots(home) {
+		add(root)
+		for _, vdf := range []string{
+			filepath.Join(root, "steamapps", "libraryfolders.vdf"),
+			filepath.Join(root, "config", "libraryfolders.vdf"),
+		} {
+			f, ok := openLauncherFile(vdf)
+			if !ok {
+				continue
+			}
+			libs, _ := ParseSteamLibraryFolders(f)
+			f.Close()
+			for _, lib := range libs {
+				add(lib)
+			}
+		}
+	}
+	return result
+}
+
+// WinePrefixes 列出目前使用者的 Wine / Proton 前綴（只包含實際存在者）
+// 依序為 WINEPREFIX、~/.wine、Lutris 遊戲設定、~/Games 下的資料夾（Lutris 預設安裝位置）與 Steam compatdata
+func WinePrefixes() []WinePrefix {
+	home, _ := os.UserHomeDir()
+	c := &prefixCollector{seen: map[string]bool{}}
+
+	c.add(os.Getenv("WINEPREFIX"), PrefixSourceEnv)
+	if home == "" {
+		return c.result
+	}
+	c.add(filepath.Join(home, ".wine"), PrefixSourceWine)
+
+	for _, dir := range lutrisGameDirs(home) {
+		configs, _ := filepath.Glob(filepath.Join(dir, "*.yml"))
+		sort.Strings(configs)
+		for _, p := range configs {
+			f, ok := openLauncherFile(p)
+			if !ok {
+				continue
+			}
+			prefixes, _ := ParseLutrisGameConfig(f)
+			f.Close()
+			for _, prefix := range prefixes {
+				c.add(expandHome(prefix, home), PrefixSourceLutris)
+			}
+		}
+	}
+
+	games, _ := filepath.Glob(filepath.Join(home, "Games", "*"))
+	sort.Strings(games)
+	for _, dir := range games {
+		c.add(dir, PrefixSourceGames)
+	}
+
+	for _, lib := range SteamLibraries(home) {
+		compat, _ := filepath.Glob(filepath.Join(lib, "steamapps", "compatdata", "*", "pfx"))
+		sort.Strings(compat)
+		for _, dir := range compat {
+			c.add(dir, PrefixSourceSteam)
+		}
+	}
+	return c.result
+}
+
+// PrefixCandidates 回傳前綴中可能的安裝根目錄（不檢查是否存在）：
+// 預設安裝路徑，以及前綴內 RSI Launcher 設定與日誌（drive_c/users/*/AppData/Roaming/rsilauncher）記錄的安裝
+func PrefixCandidates(prefix string) []string {
+	result := []string{}
+	add := func(p string) {
+		if p != "" && !containsFold(result, p) {
+			result = append(result, p)
+		}
+	}
+	driveC := filepath.Join(prefix, "drive_c")
+	launcherDirs, _ := filepath.Glob(filepath.Join(driveC, "users", "*", "AppData", "Roaming", "rsilauncher"))
+	sort.Strings(launcherDirs)
+	for _, dir := range launcherDirs {
+		for _, inst := range LauncherInstalls(dir) {
+			if p, ok := WindowsPathInPrefix(prefix, inst.Path); ok {
+				add(p)
+			}
+		}
+	}
+	add(filepath.Join(driveC, "Program Files", "Roberts Space Industries", "StarCitizen"))
+	add(filepath.Join(driveC, "Program Files (x86)", "Roberts Space Industries", "StarCitizen"))
+	return result
+}
+
+// DetectInPrefixes 回傳所有 Wine / Proton 前綴中實際存在的安裝根目錄
+func DetectInPrefixes() []string {
+	result := []string{}
+	for _, prefix := range WinePrefixes() {
+		for _, p := range PrefixCandidates(prefix.Path) {
+			if Validate(p) && !containsFold(result, p) {
+				result = append(result, p)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+package gameinstall
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// prefixTestDir 範例 Lutris 遊戲設定與 Steam libraryfolders.vdf
+var prefixTestDir = filepath.Join("testdata", "prefixes")
+
+// fixtureOrInput 讀取 testdata 中的檔案；file 為空字串時使用 input
+func fixtureOrInput(t *testing.T, file, input string) string {
+	t.Helper()
+	if file == "" {
+		return input
+	}
+	data, err := os.ReadFile(filepath.Join(prefixTestDir, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseLutrisGameConfig(t *testing.T) {
+	cases := []struct {
+		name  string
+		file  string
+		input string
+		want  []string
+	}{
+		{
+			// exe 推出的前綴與 prefix 相同；system: 區段的 prefix 不採用
+			name: "fixture",
+			file: "star-citizen.yml",
+			want: []string{"/home/pilot/Games/star-citizen"},
+		},
+		{
+			name:  "double quoted",
+			input: "game:\n  prefix: \"/home/pilot/Games/star citizen\"\n",
+			want:  []string{"/home/pilot/Games/star citizen"},
+		},
+		{
+			name:  "single quoted with home",
+			input: "game:\n  prefix: '~/Games/sc'   \n",
+			want:  []string{"~/Games/sc"},
+		},
+		{
+			name:  "tab indented",
+			input: "game:\n\tprefix: /opt/wine/sc\n",
+			want:  []string{"/opt/wine/sc"},
+		},
+		{
+			name:  "prefix from exe only",
+			input: "game:\n  exe: /home/pilot/.wine-sc/drive_c/RSI/RSI Launcher.exe\n",
+			want:  []string{"/home/pilot/.wine-sc"},
+		},
+		{
+			name:  "prefix and exe differ",
+			input: "game:\n  exe: /b/drive_c/x.exe\n  prefix: /a\n",
+			want:  []string{"/a", "/b"},
+		},
+		{
+			name:  "empty prefix",
+			input: "game:\n  prefix: ''\n  exe: launcher.exe\n",
+			want:  []string{},
+		},
+		{
+			name:  "no game section",
+			input: "name: Star Citizen\nsystem:\n  prefix: /home/pilot/other\n",
+			want:  []string{},
+		},
+		{
+			name:  "commented out",
+			input: "game:\n#  prefix: /old\n  # prefix: /older\n",
+			want:  []string{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseLutrisGameConfig(strings.NewReader(fixtureOrInput(t, c.file, c.input)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got  %q\nwant %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseSteamLibraryFolders(t *testing.T) {
+	cases := []struct {
+		name  string
+		file  string
+		input string
+		want  []string
+	}{
+		{
+			// 沒有 path 鍵的項目略過
+			name: "fixture",
+			file: "libraryfolders.vdf",
+			want: []string{"/home/pilot/.local/share/Steam", "/mnt/Games Drive/SteamLibrary"},
+		},
+		{
+			name:  "escaped backslashes",
+			input: `"libraryfolders" { "1" { "path" "D:\\Steam Library\\" } }`,
+			want:  []string{`D:\Steam Library\`},
+		},
+		{
+			name:  "escaped quote",
+			input: `"path"		"/mnt/\"games\""`,
+			want:  []string{`/mnt/"games"`},
+		},
+		{
+			name:  "duplicates ignore case",
+			input: "\"path\" \"/mnt/Steam\"\n\"path\" \"/MNT/steam\"\n",
+			want:  []string{"/mnt/Steam"},
+		},
+		{
+			name:  "old format without path keys",
+			input: "\"LibraryFolders\"\n{\n\t\"TimeNextStatsReport\"\t\t\"1680000000\"\n\t\"1\"\t\t\"/mnt/old\"\n}\n",
+			want:  []string{},
+		},
+		{
+			name:  "empty path",
+			input: `"path" ""`,
+			want:  []string{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseSteamLibraryFolders(strings.NewReader(fixtureOrInput(t, c.file, c.input)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got  %q\nwant %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWindowsPathInPrefix(t *testing.T) {
+	prefix := filepath.Join("/home", "pilot", "Games", "star-citizen")
+	cases := []struct {
+		name    string
+		winPath string
+		want    string
+		ok      bool
+	}{
+		{"drive c", `C:\Program Files\Roberts Space Industries\StarCitizen`,
+			filepath.Join(prefix, "drive_c", "Program Files", "Roberts Space Industries", "StarCitizen"), true},
+		{"lowercase drive and forward slashes", "c:/Games/StarCitizen/",
+			filepath.Join(prefix, "drive_c", "Games", "StarCitizen"), true},
+		{"other drive via dosdevices", `D:\RSI\StarCitizen`,
+			filepath.Join(prefix, "dosdevices", "d:", "RSI", "StarCitizen"), true},
+		{"doubled backslashes", `E:\\SC\\StarCitizen`,
+			filepath.Join(prefix, "dosdevices", "e:", "SC", "StarCitizen"), true},
+		{"drive root", "C:", filepath.Join(prefix, "drive_c"), true},
+		{"surrounding spaces", "  F:\\SC  ", filepath.Join(prefix, "dosdevices", "f:", "SC"), true},
+		{"unix path", "/mnt/games/../StarCitizen", filepath.Clean("/mnt/StarCitizen"), true},
+		{"relative", `Games\StarCitizen`, "", false},
+		{"drive letter without separator", "C:Games", "", false},
+		{"empty", "", "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := WindowsPathInPrefix(prefix, c.winPath)
+			if got != c.want || ok != c.ok {
+				t.Errorf("WindowsPathInPrefix(%q) = %q, %v; want %q, %v", c.winPath, got, ok, c.want, c.ok)
+			}
+		})
+	}
+}
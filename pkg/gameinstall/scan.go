@@ -0,0 +1,270 @@
+package gameinstall
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 磁碟機掃描的預設值與上限
+const (
+	DefaultScanDepth    = 2
+	MaxScanDepth        = 6
+	DefaultScanWorkers  = 4
+	DefaultDriveTimeout = 15 * time.Second
+)
+
+// ErrScanCancelled 掃描被取消
+var ErrScanCancelled = errors.New("drive scan was cancelled")
+
+// 掃描目標的狀態
+const (
+	ScanStarted   = "started"
+	ScanDone      = "done"
+	ScanTimeout   = "timeout"   // 超過 DriveTimeout，略過（例如已中斷的網路磁碟機）
+	ScanCancelled = "cancelled" // 掃描被取消，未完成
+)
+
+// ScanOptions 磁碟機掃描設定；零值使用預設值
+type ScanOptions struct {
+	Depth        int                // 在遊戲目錄與自訂遊戲庫下往下尋找 StarCitizen 資料夾的層數
+	DriveTimeout time.Duration      // 每個磁碟機或自訂資料夾的時間上限
+	Workers      int                // 同時掃描的數量
+	Roots        []string           // 額外掃描的自訂遊戲庫資料夾（任何平台）
+	Progress     func(ScanProgress) // 每個目標開始與結束時呼叫；不會同時呼叫，可為 nil
+}
+
+// ScanProgress 掃描進度
+type ScanProgress struct {
+	Target string   `json:"target"` // 磁碟機（例如 D:）或自訂資料夾
+	State  string   `json:"state"`  // Scan*
+	Found  []string `json:"found"`  // 此目標找到的安裝
+	Done   int      `json:"done"`   // 已結束的目標數
+	Total  int      `json:"total"`
+}
+
+func (o ScanOptions) withDefaults() ScanOptions {
+	if o.Depth <= 0 {
+		o.Depth = DefaultScanDepth
+	}
+	if o.Depth > MaxScanDepth {
+		o.Depth = MaxScanDepth
+	}
+	if o.DriveTimeout <= 0 {
+		o.DriveTimeout = DefaultDriveTimeout
+	}
+	if o.Workers <= 0 {
+		o.Workers = DefaultScanWorkers
+	}
+	return o
+}
+
+// commonGameDirs 磁碟機上常見的遊戲安裝目錄名稱
+var commonGameDirs = []string{
+	"Games",
+	"Game",
+	"Steam",
+	"SteamLibrary",
+	"Epic Games",
+	"GOG Games",
+	"Program Files",
+	"Program Files (x86)",
+}
+
+// scanTarget 一個掃描目標：磁碟機（僅 Windows）或自訂遊戲庫資料夾
+type scanTarget struct {
+	name  string
+	drive bool
+}
+
+// Scan 平行掃描所有磁碟機（僅 Windows）與自訂遊戲庫資料夾，回傳找到的安裝（依磁碟機與資料夾順序）
+// 每個目標有各自的時間上限，逾時的目標略過；ctx 取消時回傳已找到的安裝與 ErrScanCancelled
+func Scan(ctx context.Context, opts ScanOptions) ([]string, error) {
+	opts = opts.withDefaults()
+	var targets []scanTarget
+	for _, d := range LogicalDrives() {
+		targets = append(targets, scanTarget{name: d, drive: true})
+	}
+	for _, r := range opts.Roots {
+		if strings.TrimSpace(r) != "" {
+			targets = append(targets, scanTarget{name: r})
+		}
+	}
+
+	var mu sync.Mutex
+	done := 0
+	report := func(t scanTarget, state string, found []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if state != ScanStarted {
+			done++
+		}
+		if found == nil {
+			found = []string{}
+		}
+		if opts.Progress != nil {
+			opts.Progress(ScanProgress{Target: t.name, State: state, Found: found, Done: done, Total: len(targets)})
+		}
+	}
+
+	results := make([][]string, len(targets))
+	sem := make(chan struct{}, opts.Workers)
+	var wg sync.WaitGroup
+launch:
+	for i, t := range targets {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for _, rest := range targets[i:] {
+				report(rest, ScanCancelled, nil)
+			}
+			break launch
+		}
+		wg.Add(1)
+		go func(i int, t scanTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			report(t, ScanStarted, nil)
+			found, state := scanWithTimeout(ctx, t, opts)
+			results[i] = found
+			report(t, state, found)
+		}(i, t)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	paths := []string{}
+	for _, found := range results {
+		for _, p := range found {
+			if key := pathKey(p); !seen[key] {
+				seen[key] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return paths, ErrScanCancelled
+	}
+	return paths, nil
+}
+
+// scanWithTimeout 在 DriveTimeout 內掃描一個目標
+// 無法存取的網路磁碟機會讓檔案系統呼叫卡住且無法中斷，因此在另一個 goroutine 中掃描，逾時即放棄等待
+func scanWithTimeout(ctx context.Context, t scanTarget, opts ScanOptions) ([]string, string) {
+	tctx, cancel := context.WithTimeout(ctx, opts.DriveTimeout)
+	defer cancel()
+	ch := make(chan []string, 1)
+	go func() {
+		if t.drive {
+			ch <- scanDrive(tctx, t.name, opts.Depth)
+		} else {
+			ch <- scanRoot(tctx, t.name, opts.Depth)
+		}
+	}()
+	select {
+	case found := <-ch:
+		if tctx.Err() != nil {
+			// 掃描因逾時或取消而提前結束，結果可能不完整
+			return found, timeoutState(ctx)
+		}
+		return found, ScanDone
+	case <-tctx.Done():
+		return nil, timeoutState(ctx)
+	}
+}
+
+func timeoutState(ctx context.Context) string {
+	if ctx.Err() != nil {
+		return ScanCancelled
+	}
+	return ScanTimeout
+}
+
+// isInstallDir 檢查資料夾是否為 StarCitizen 安裝根目錄
+func isInstallDir(p string) bool {
+	return strings.EqualFold(filepath.Base(p), "StarCitizen") && Validate(p)
+}
+
+// scanDrive 掃描一個磁碟機：根目錄下的 Roberts Space Industries\StarCitizen、常見遊戲目錄下 depth 層內的 StarCitizen，
+// 以及系統碟上使用者目錄中的常見位置
+func scanDrive(ctx context.Context, drive string, depth int) []string {
+	root := drive + string(filepath.Separator)
+	if _, err := os.Stat(root); err != nil {
+		return nil
+	}
+	found := []string{}
+	check := func(p string) {
+		if ctx.Err() == nil && isInstallDir(p) {
+			found = append(found, p)
+		}
+	}
+
+	check(filepath.Join(root, "Roberts Space Industries", "StarCitizen"))
+	for _, gameDir := range commonGameDirs {
+		if ctx.Err() != nil {
+			return found
+		}
+		found = append(found, walkForInstalls(ctx, filepath.Join(root, gameDir), depth)...)
+	}
+	if home, err := os.UserHomeDir(); err == nil && strings.EqualFold(filepath.VolumeName(home), drive) {
+		check(filepath.Join(home, "Games", "Roberts Space Industries", "StarCitizen"))
+		check(filepath.Join(home, "Documents", "StarCitizen"))
+		check(filepath.Join(home, "Desktop", "Roberts Space Industries", "StarCitizen"))
+	}
+	return found
+}
+
+// scanRoot 掃描自訂遊戲庫資料夾：資料夾本身即為安裝時直接回傳，否則往下 depth 層尋找
+func scanRoot(ctx context.Context, root string, depth int) []string {
+	if isInstallDir(root) {
+		return []string{root}
+	}
+	return walkForInstalls(ctx, root, depth)
+}
+
+// skipScanDir 掃描時略過的資料夾（系統資料夾與隱藏資料夾）
+func skipScanDir(name string) bool {
+	if strings.HasPrefix(name, "$") || strings.HasPrefix(name, ".") {
+		return true
+	}
+	switch strings.ToLower(name) {
+	case "windows", "system volume information", "windowsapps", "node_modules":
+		return true
+	}
+	return false
+}
+
+// walkForInstalls 在 dir 下逐層尋找名為 StarCitizen 的有效安裝，最多往下 depth 層；找到的安裝不再往下
+func walkForInstalls(ctx context.Context, dir string, depth int) []string {
+	found := []string{}
+	level := []string{dir}
+	for d := 0; d < depth && len(level) > 0; d++ {
+		var next []string
+		for _, p := range level {
+			if ctx.Err() != nil {
+				return found
+			}
+			entries, err := os.ReadDir(p)
+			if err != nil {
+				continue
+			}
+			for _, e := range entries {
+				if !e.IsDir() || skipScanDir(e.Name()) {
+					continue
+				}
+				child := filepath.Join(p, e.Name())
+				if isInstallDir(child) {
+					found = append(found, child)
+					continue
+				}
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+	return found
+}
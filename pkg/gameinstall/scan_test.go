@@ -0,0 +1,167 @@
+package gameinstall
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// makeInstall 在 dir 下建立含 LIVE 版本的 StarCitizen 安裝，回傳安裝根目錄
+func makeInstall(t *testing.T, dir ...string) string {
+	t.Helper()
+	root := filepath.Join(append(dir, "StarCitizen")...)
+	if err := os.MkdirAll(filepath.Join(root, "LIVE", "Bin64"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// progressLog 記錄 Progress 的呼叫（Scan 保證不會同時呼叫）
+type progressLog struct {
+	events []ScanProgress
+}
+
+func (l *progressLog) record(p ScanProgress) {
+	l.events = append(l.events, p)
+}
+
+// final 回傳每個目標的最後狀態
+func (l *progressLog) final() map[string]string {
+	states := map[string]string{}
+	for _, e := range l.events {
+		states[e.Target] = e.State
+	}
+	return states
+}
+
+// checkOrder 確認每個目標先開始才結束、每個目標只結束一次，且 Done 依序遞增到 Total
+func (l *progressLog) checkOrder(t *testing.T, total int) {
+	t.Helper()
+	started := map[string]bool{}
+	finished := map[string]bool{}
+	done := 0
+	for _, e := range l.events {
+		if e.Total != total {
+			t.Errorf("%s: total = %d, want %d", e.Target, e.Total, total)
+		}
+		if finished[e.Target] {
+			t.Errorf("%s: progress after the target finished: %+v", e.Target, e)
+		}
+		if e.State == ScanStarted {
+			started[e.Target] = true
+		} else {
+			// 取消前尚未開始的目標直接回報 cancelled
+			if !started[e.Target] && e.State != ScanCancelled {
+				t.Errorf("%s: %s reported before started", e.Target, e.State)
+			}
+			finished[e.Target] = true
+			done++
+		}
+		if e.Done != done {
+			t.Errorf("%s: done = %d, want %d", e.Target, e.Done, done)
+		}
+		if e.Found == nil {
+			t.Errorf("%s: found is nil", e.Target)
+		}
+	}
+	if done != total {
+		t.Errorf("%d of %d targets finished", done, total)
+	}
+}
+
+func TestScanRootsAndDedupe(t *testing.T) {
+	base := t.TempDir()
+	games := filepath.Join(base, "Games")
+	direct := makeInstall(t, games)
+	nested := makeInstall(t, base, "Library", "RSI")
+	// 超過深度的安裝不會被找到
+	makeInstall(t, base, "Deep", "a", "b", "c")
+	// 隱藏資料夾略過
+	makeInstall(t, base, "Hidden", ".cache")
+
+	log := &progressLog{}
+	roots := []string{games, direct, filepath.Join(base, "Library"), "  ", filepath.Join(base, "Deep"), filepath.Join(base, "Hidden"), filepath.Join(base, "missing")}
+	got, err := Scan(context.Background(), ScanOptions{Roots: roots, Workers: 2, Progress: log.record})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// games 與 direct 指向同一個安裝，只回傳一次
+	if want := []string{direct, nested}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+	log.checkOrder(t, 6)
+	for target, state := range log.final() {
+		if state != ScanDone {
+			t.Errorf("%s: state = %s", target, state)
+		}
+	}
+}
+
+func TestScanDriveTimeout(t *testing.T) {
+	root := makeInstall(t, t.TempDir())
+	log := &progressLog{}
+	// 時間上限極短：目標逾時略過，整體掃描仍然成功
+	got, err := Scan(context.Background(), ScanOptions{Roots: []string{root}, DriveTimeout: time.Nanosecond, Progress: log.record})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state := log.final()[root]; state != ScanTimeout {
+		t.Errorf("state = %s, want %s", state, ScanTimeout)
+	}
+	// 逾時的目標即使已有結果也可能不完整，最多只回傳這個安裝
+	if len(got) > 1 {
+		t.Errorf("got %v", got)
+	}
+	log.checkOrder(t, 1)
+}
+
+func TestScanCancelledMidScan(t *testing.T) {
+	base := t.TempDir()
+	var roots []string
+	for _, name := range []string{"A", "B", "C", "D"} {
+		roots = append(roots, filepath.Dir(makeInstall(t, base, name)))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log := &progressLog{}
+	progress := func(p ScanProgress) {
+		log.record(p)
+		// 第一個目標完成後取消
+		if p.State == ScanDone {
+			cancel()
+		}
+	}
+	got, err := Scan(ctx, ScanOptions{Roots: roots, Workers: 1, Progress: progress})
+	if !errors.Is(err, ErrScanCancelled) {
+		t.Fatalf("err = %v, want ErrScanCancelled", err)
+	}
+	// 取消前完成的目標結果仍然回傳
+	if want := []string{filepath.Join(roots[0], "StarCitizen")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	states := log.final()
+	if states[roots[0]] != ScanDone {
+		t.Errorf("%s: state = %s", roots[0], states[roots[0]])
+	}
+	for _, r := range roots[1:] {
+		if states[r] != ScanCancelled {
+			t.Errorf("%s: state = %s, want %s", r, states[r], ScanCancelled)
+		}
+	}
+	log.checkOrder(t, len(roots))
+}
+
+func TestScanOptionsDefaults(t *testing.T) {
+	o := ScanOptions{Depth: MaxScanDepth + 3}.withDefaults()
+	if o.Depth != MaxScanDepth || o.Workers != DefaultScanWorkers || o.DriveTimeout != DefaultDriveTimeout {
+		t.Errorf("withDefaults = %+v", o)
+	}
+	if o := (ScanOptions{}).withDefaults(); o.Depth != DefaultScanDepth {
+		t.Errorf("depth = %d", o.Depth)
+	}
+}
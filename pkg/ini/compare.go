@@ -0,0 +1,116 @@
+package ini
+
+import "fmt"
+
+// CompareResult 比對結果詳細資訊
+type CompareResult struct {
+	Missing        []KeyValue `json:"missing"`        // 缺少的項目
+	CurrentCount   int        `json:"currentCount"`   // 當前檔案的項目數量
+	ReferenceCount int        `json:"referenceCount"` // 參考檔案的項目數量
+}
+
+// ReadEntries 讀取 INI 檔案並回傳所有鍵值對（保持順序）
+func ReadEntries(filePath string) ([]KeyValue, error) {
+	var result []KeyValue
+	if _, err := ScanFile(filePath, func(ln *Line) {
+		if ln.Kind == LineEntry {
+			result = append(result, KeyValue{Key: ln.Key, Value: ln.Value})
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReadKeySet 以串流方式讀取 INI 檔案的鍵集合與項目數量（不保留值）
+func ReadKeySet(filePath string) (map[string]bool, int, error) {
+	keys := make(map[string]bool)
+	count := 0
+	_, err := ScanFile(filePath, func(ln *Line) {
+		if ln.Kind == LineEntry {
+			keys[ln.Key] = true
+			count++
+		}
+	})
+	return keys, count, err
+}
+
+// Compare 比對兩個 INI 檔案，找出 currentPath 中缺少的項目（相對於 referencePath，保持參考檔案的順序）
+func Compare(currentPath, referencePath string) (CompareResult, error) {
+	// 當前檔案只需要鍵集合，不保留值
+	currentKeys, currentCount, err := ReadKeySet(currentPath)
+	if err != nil {
+		return CompareResult{}, fmt.Errorf("read current file failed: %w", err)
+	}
+
+	// 串流讀取參考檔案，找出缺少的項目
+	var missing []KeyValue
+	referenceCount := 0
+	if _, err := ScanFile(referencePath, func(ln *Line) {
+		if ln.Kind != LineEntry {
+			return
+		}
+		referenceCount++
+		if !currentKeys[ln.Key] {
+			missing = append(missing, KeyValue{Key: ln.Key, Value: ln.Value})
+		}
+	}); err != nil {
+		return CompareResult{}, fmt.Errorf("read reference file failed: %w", err)
+	}
+
+	return CompareResult{
+		Missing:        missing,
+		CurrentCount:   currentCount,
+		ReferenceCount: referenceCount,
+	}, nil
+}
+
+// ApplyUpdates 將更新套用到文件：既有鍵就地改值，新鍵依參考檔案的順序插入，其餘行維持原樣
+func ApplyUpdates(doc *Document, referencePath string, updates []KeyValue) error {
+	// 讀取參考檔案以獲取正確的順序
+	reference, err := ReadEntries(referencePath)
+	if err != nil {
+		return fmt.Errorf("read reference file failed: %w", err)
+	}
+
+	// 既有鍵：直接修改；新鍵：留待依參考順序插入
+	added := make(map[string]string)
+	for _, item := range updates {
+		if len(doc.Lookup(item.Key)) > 0 {
+			doc.Set(item.Key, item.Value)
+		} else {
+			added[item.Key] = item.Value
+		}
+	}
+
+	// 新鍵放在參考檔案中前一個已存在鍵的後面；前面沒有既有鍵時放在第一個項目之前（與 MergeEnglish 相同）
+	var head []*Line
+	after := make(map[*Line][]*Line)
+	var anchor *Line
+	for _, refItem := range reference {
+		if lines := doc.Lookup(refItem.Key); len(lines) > 0 {
+			anchor = lines[len(lines)-1]
+			continue
+		}
+		newVal, ok := added[refItem.Key]
+		if !ok {
+			continue
+		}
+		delete(added, refItem.Key)
+		ln := newEntryLine(refItem.Key, newVal)
+		if anchor == nil {
+			head = append(head, ln)
+		} else {
+			after[anchor] = append(after[anchor], ln)
+		}
+	}
+	// 參考檔案中沒有的新鍵附加到結尾（依更新清單順序）
+	for _, item := range updates {
+		if newVal, ok := added[item.Key]; ok {
+			delete(added, item.Key)
+			after[nil] = append(after[nil], newEntryLine(item.Key, newVal))
+		}
+	}
+	doc.insertLines(head, after)
+	return nil
+}
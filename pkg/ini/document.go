@@ -0,0 +1,370 @@
+// Package ini 提供 Star Citizen global.ini 語系檔的解析、比對、合併與驗證
+//
+// 文件模型（Document）保留註解、空行、原始順序與重複項目，寫回時只改動真正變更的行；
+// 讀取時自動偵測編碼（UTF-8 / UTF-16 / Big5 / GBK）與檔案格式（行尾、BOM）。
+package ini
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LineKind 表示 INI 文件中一行的種類
+type LineKind int
+
+const (
+	LineBlank   LineKind = iota // 空行
+	LineComment                 // 註解（; 或 # 開頭）
+	LineEntry                   // key=value 項目
+	LineInvalid                 // 無法解析的行（原樣保留）
+)
+
+// KeyValue 表示 INI 檔案中的鍵值對
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Line 文件中的一行
+// Raw 為原始內容（不含行尾）；Key/Value 為清理後的值，RawValue 為 '=' 之後的原始內容
+type Line struct {
+	Kind     LineKind
+	Raw      string
+	Key      string
+	Value    string
+	RawValue string
+	LineNo   int // 原始檔案中的行號（從 1 開始），新增的行為 0
+
+	keyPart string // '=' 之前（含 '=' 與值前空白）的原始內容，改值時沿用
+	removed bool
+}
+
+// SetValue 就地修改項目的值；值未變動時保留原始內容不動
+func (l *Line) SetValue(value string) {
+	if l.Kind != LineEntry || l.Value == value {
+		return
+	}
+	l.Value = value
+	l.RawValue = value
+	l.Raw = l.keyPart + value
+}
+
+// Document 保留註解、空行、原始順序與重複項目的 INI 文件模型
+type Document struct {
+	Lines  []*Line
+	Format Format // 讀取時偵測到的格式，寫回時沿用
+
+	index map[string][]*Line
+}
+
+// NewDocument 建立空白文件
+func NewDocument() *Document {
+	return &Document{Format: DefaultFormat, index: make(map[string][]*Line)}
+}
+
+// Parse 解析 INI 內容
+func Parse(content string) *Document {
+	doc, _ := read(strings.NewReader(content))
+	return doc
+}
+
+// Load 讀取並解析 INI 檔案
+func Load(filePath string) (*Document, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("read ini file failed: %w", err)
+	}
+	defer f.Close()
+	doc, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("read ini file failed: %w", err)
+	}
+	return doc, nil
+}
+
+// read 以串流解析器建立文件，並記錄偵測到的格式
+func read(r io.Reader) (*Document, error) {
+	doc := NewDocument()
+	s := NewScanner(r)
+	for s.Scan() {
+		doc.Lines = append(doc.Lines, s.Line())
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	doc.Format = s.Format()
+	doc.reindex()
+	return doc, nil
+}
+
+// LoadOrEmpty 讀取既有檔案；檔案不存在時回傳空白文件
+func LoadOrEmpty(filePath string) (*Document, error) {
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		return NewDocument(), nil
+	}
+	return Load(filePath)
+}
+
+// cleanString 清理字串，移除前後空白和不可見字符
+func cleanString(s string) string {
+	// 先做基本的 trim
+	s = strings.TrimSpace(s)
+
+	// 移除常見的不可見字符
+	s = strings.Trim(s, "\u200B\u200C\u200D\uFEFF") // Zero-width spaces and BOM
+
+	return s
+}
+
+// parseLine 解析單行內容
+func parseLine(raw string) *Line {
+	ln := &Line{Raw: raw}
+	trimmed := strings.TrimSpace(raw)
+	switch {
+	case trimmed == "":
+		ln.Kind = LineBlank
+		return ln
+	case strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#"):
+		ln.Kind = LineComment
+		return ln
+	}
+
+	eq := strings.Index(raw, "=")
+	if eq < 0 {
+		ln.Kind = LineInvalid
+		return ln
+	}
+	// 清理 key 和 value，移除所有不可見字符
+	key := cleanString(raw[:eq])
+	if key == "" {
+		ln.Kind = LineInvalid
+		return ln
+	}
+	rawValue := raw[eq+1:]
+	valueStart := len(rawValue) - len(strings.TrimLeft(rawValue, " \t"))
+
+	ln.Kind = LineEntry
+	ln.Key = key
+	ln.Value = cleanString(rawValue)
+	ln.RawValue = rawValue
+	ln.keyPart = raw[:eq+1] + rawValue[:valueStart]
+	return ln
+}
+
+// newEntryLine 建立新的 key=value 行
+func newEntryLine(key, value string) *Line {
+	return &Line{
+		Kind:     LineEntry,
+		Raw:      key + "=" + value,
+		Key:      key,
+		Value:    value,
+		RawValue: value,
+		keyPart:  key + "=",
+	}
+}
+
+// reindex 移除已刪除的行並重建 key 索引
+func (d *Document) reindex() {
+	d.index = make(map[string][]*Line)
+	kept := d.Lines[:0]
+	for _, ln := range d.Lines {
+		if ln.removed {
+			continue
+		}
+		kept = append(kept, ln)
+		if ln.Kind == LineEntry {
+			d.index[ln.Key] = append(d.index[ln.Key], ln)
+		}
+	}
+	for i := len(kept); i < len(d.Lines); i++ {
+		d.Lines[i] = nil
+	}
+	d.Lines = kept
+}
+
+// Entries 依原始順序回傳所有項目（包含重複的鍵）
+func (d *Document) Entries() []KeyValue {
+	result := make([]KeyValue, 0, len(d.Lines))
+	for _, ln := range d.Lines {
+		if ln.Kind == LineEntry {
+			result = append(result, KeyValue{Key: ln.Key, Value: ln.Value})
+		}
+	}
+	return result
+}
+
+// Lookup 回傳指定鍵的所有項目行（依出現順序）
+func (d *Document) Lookup(key string) []*Line {
+	return d.index[key]
+}
+
+// Get 回傳指定鍵第一次出現的值
+func (d *Document) Get(key string) (string, bool) {
+	lines := d.index[key]
+	if len(lines) == 0 {
+		return "", false
+	}
+	return lines[0].Value, true
+}
+
+// Set 修改指定鍵所有出現位置的值；鍵不存在時附加到文件結尾
+func (d *Document) Set(key, value string) {
+	lines := d.index[key]
+	if len(lines) == 0 {
+		d.Append(key, value)
+		return
+	}
+	for _, ln := range lines {
+		ln.SetValue(value)
+	}
+}
+
+// Append 在文件結尾新增項目
+func (d *Document) Append(key, value string) *Line {
+	ln := newEntryLine(key, value)
+	d.Lines = append(d.Lines, ln)
+	d.index[key] = append(d.index[key], ln)
+	return ln
+}
+
+// InsertAfter 在 anchor 之後新增項目；anchor 為 nil 或不在文件中時附加到結尾
+func (d *Document) InsertAfter(anchor *Line, key, value string) *Line {
+	ln := newEntryLine(key, value)
+	d.insertLines(nil, map[*Line][]*Line{anchor: {ln}})
+	return ln
+}
+
+// Remove 刪除指定的行
+func (d *Document) Remove(lines ...*Line) {
+	for _, ln := range lines {
+		ln.removed = true
+	}
+	d.reindex()
+}
+
+// insertLines 一次插入多行：head 放在第一個項目之前，其餘依 after 放在對應行之後
+// after[nil] 的行附加到文件結尾
+func (d *Document) insertLines(head []*Line, after map[*Line][]*Line) {
+	total := len(d.Lines) + len(head)
+	for _, ls := range after {
+		total += len(ls)
+	}
+	out := make([]*Line, 0, total)
+	headPlaced := len(head) == 0
+	for _, ln := range d.Lines {
+		if !ln.removed {
+			if !headPlaced && ln.Kind == LineEntry {
+				out = append(out, head...)
+				headPlaced = true
+			}
+			out = append(out, ln)
+		}
+		// 錨點已刪除時，新行放在其原本的位置
+		if ls, ok := after[ln]; ok {
+			out = append(out, ls...)
+			delete(after, ln)
+		}
+	}
+	if !headPlaced {
+		out = append(out, head...)
+	}
+	out = append(out, after[nil]...)
+	d.Lines = out
+	d.reindex()
+}
+
+// SyncEntries 讓文件中的項目與 items 一致，且盡量不動到其他行：
+// 鍵與值皆相同的行原樣保留、同鍵不同值者就地改值、items 中沒有的行刪除，
+// 新的鍵插入在 items 中前一個既有項目之後
+func (d *Document) SyncEntries(items []KeyValue) {
+	pending := make(map[string][]int)
+	for i, it := range items {
+		pending[it.Key] = append(pending[it.Key], i)
+	}
+	matched := make([]*Line, len(items))
+
+	// 第一輪：鍵與值皆相同者直接配對
+	var unmatched []*Line
+	for _, ln := range d.Lines {
+		if ln.Kind != LineEntry {
+			continue
+		}
+		queue := pending[ln.Key]
+		found := -1
+		for j, idx := range queue {
+			if items[idx].Value == ln.Value {
+				found = j
+				break
+			}
+		}
+		if found < 0 {
+			unmatched = append(unmatched, ln)
+			continue
+		}
+		matched[queue[found]] = ln
+		pending[ln.Key] = append(queue[:found:found], queue[found+1:]...)
+	}
+
+	// 第二輪：同鍵不同值者就地改值，多出來的行刪除
+	for _, ln := range unmatched {
+		queue := pending[ln.Key]
+		if len(queue) == 0 {
+			ln.removed = true
+			continue
+		}
+		matched[queue[0]] = ln
+		ln.SetValue(items[queue[0]].Value)
+		pending[ln.Key] = queue[1:]
+	}
+
+	// 第三輪：新鍵插入在前一個既有項目之後
+	var head []*Line
+	after := make(map[*Line][]*Line)
+	var anchor *Line
+	for i, it := range items {
+		if matched[i] != nil {
+			anchor = matched[i]
+			continue
+		}
+		ln := newEntryLine(it.Key, it.Value)
+		if anchor == nil {
+			head = append(head, ln)
+		} else {
+			after[anchor] = append(after[anchor], ln)
+		}
+	}
+	d.insertLines(head, after)
+}
+
+// Bytes 依指定格式輸出文件內容
+func (d *Document) Bytes(format Format) []byte {
+	var b strings.Builder
+	if format.BOM {
+		b.WriteString("\uFEFF")
+	}
+	n := 0
+	for _, ln := range d.Lines {
+		if ln.removed {
+			continue
+		}
+		if n > 0 {
+			b.WriteString(format.EOL)
+		}
+		b.WriteString(ln.Raw)
+		n++
+	}
+	if n > 0 && format.TrailingNewline {
+		b.WriteString(format.EOL)
+	}
+	return []byte(b.String())
+}
+
+// WriteFile 依指定格式將文件寫入檔案
+func (d *Document) WriteFile(filePath string, format Format) error {
+	if err := os.WriteFile(filePath, d.Bytes(format), 0644); err != nil {
+		return fmt.Errorf("write file failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package ini
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// roundTripCases 各種格式的 global.ini：行尾、BOM、檔尾換行、註解、重複鍵與無法解析的行
+var roundTripCases = []struct {
+	name    string
+	content string
+	format  Format
+}{
+	{
+		name:    "game file",
+		content: "\uFEFF; comment\r\nui_Ok=確定\r\nui_Cancel = 取消\r\n\r\nvehicle_Name=Aurora MR\r\n",
+		format:  Format{EOL: "\r\n", BOM: true, TrailingNewline: true},
+	},
+	{
+		name:    "lf without bom",
+		content: "# header\nui_Ok=OK\nui_Ok=OK (duplicate)\nnot an entry\n=no key\nui_Cancel=\tCancel  ",
+		format:  Format{EOL: "\n", BOM: false, TrailingNewline: false},
+	},
+	{
+		name:    "placeholders and spaces",
+		content: "\uFEFFmission_Desc=~mission(Contractor|Name) 需要 %ls 個\\n目標\r\n  indented_key=值\r\n",
+		format:  Format{EOL: "\r\n", BOM: true, TrailingNewline: true},
+	},
+}
+
+func writeINI(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "global.ini")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// diffLines 回傳兩份內容中不同的行號（從 1 開始）
+func diffLines(a, b string) []int {
+	al, bl := strings.Split(a, "\n"), strings.Split(b, "\n")
+	var diff []int
+	for i := 0; i < len(al) || i < len(bl); i++ {
+		if i >= len(al) || i >= len(bl) || al[i] != bl[i] {
+			diff = append(diff, i+1)
+		}
+	}
+	return diff
+}
+
+func TestDocumentRoundTrip(t *testing.T) {
+	for _, c := range roundTripCases {
+		t.Run(c.name, func(t *testing.T) {
+			path := writeINI(t, c.content)
+			doc, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if doc.Format != c.format {
+				t.Errorf("format = %+v, want %+v", doc.Format, c.format)
+			}
+			if err := doc.WriteFile(path, doc.Format); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != c.content {
+				t.Errorf("round trip changed the file:\ngot  %q\nwant %q", got, c.content)
+			}
+		})
+	}
+}
+
+func TestDocumentSetChangesOneLine(t *testing.T) {
+	for _, c := range roundTripCases {
+		t.Run(c.name, func(t *testing.T) {
+			path := writeINI(t, c.content)
+			doc, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries := doc.Entries()
+			last := entries[len(entries)-1]
+			doc.Set(last.Key, "新的值")
+			if err := doc.WriteFile(path, doc.Format); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			diff := diffLines(c.content, string(got))
+			if len(diff) != 1 {
+				t.Fatalf("changed lines %v, want exactly one:\n%q", diff, got)
+			}
+			if !strings.Contains(string(got), "新的值") {
+				t.Errorf("new value missing: %q", got)
+			}
+			// 其他行與格式不變
+			if c.format.BOM != bytes.HasPrefix(got, utf8BOM) {
+				t.Errorf("BOM changed: %q", got)
+			}
+		})
+	}
+}
+
+func TestDocumentKeepsKeySpacing(t *testing.T) {
+	doc := Parse("ui_Cancel = 取消\n")
+	doc.Set("ui_Cancel", "Cancel")
+	if got := string(doc.Bytes(doc.Format)); got != "ui_Cancel = Cancel\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFormatOverrideApply(t *testing.T) {
+	detected := Format{EOL: "\n", BOM: false, TrailingNewline: false}
+	cases := []struct {
+		override FormatOverride
+		want     Format
+	}{
+		{FormatOverride{}, detected},
+		{FormatOverride{EOL: "auto", BOM: "AUTO"}, detected},
+		{FormatOverride{EOL: " CRLF ", BOM: "On", TrailingNewline: "on"}, Format{EOL: "\r\n", BOM: true, TrailingNewline: true}},
+		{FormatOverride{EOL: "lf", BOM: "off", TrailingNewline: "off"}, detected},
+	}
+	for _, c := range cases {
+		if err := c.override.Validate(); err != nil {
+			t.Errorf("Validate(%+v): %v", c.override, err)
+		}
+		if got := c.override.Apply(detected); got != c.want {
+			t.Errorf("Apply(%+v) = %+v, want %+v", c.override, got, c.want)
+		}
+	}
+	if err := (FormatOverride{EOL: "cr"}).Validate(); err == nil {
+		t.Error("Validate accepted eol=cr")
+	}
+}
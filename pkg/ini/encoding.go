@@ -0,0 +1,224 @@
+package ini
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// 支援的文字編碼名稱
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingBig5    = "big5"
+	EncodingGBK     = "gbk"
+)
+
+// sniffSize 推測編碼時最多檢查的位元組數
+const sniffSize = 64 * 1024
+
+// DetectEncoding 偵測文字內容的編碼：先判斷 BOM，再依內容推測
+func DetectEncoding(data []byte) string {
+	return detectEncoding(data, true)
+}
+
+// detectEncoding 偵測編碼；complete 為 false 表示 data 只是檔案開頭的一部分
+func detectEncoding(data []byte, complete bool) string {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return EncodingUTF8
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return EncodingUTF16BE
+	}
+
+	sample := data
+	if len(sample) > sniffSize {
+		sample = sample[:sniffSize]
+	}
+	if enc := sniffUTF16(sample); enc != "" {
+		return enc
+	}
+	if !complete {
+		// 截斷的樣本結尾可能切在多位元組字元中間
+		data = trimPartialRune(data)
+	}
+	if looksLikeUTF8(data) {
+		return EncodingUTF8
+	}
+	return guessLegacyChinese(data)
+}
+
+// trimPartialRune 移除結尾不完整的 UTF-8 字元
+func trimPartialRune(data []byte) []byte {
+	for k := 1; k <= utf8.UTFMax && k <= len(data); k++ {
+		if utf8.RuneStart(data[len(data)-k]) {
+			if !utf8.FullRune(data[len(data)-k:]) {
+				return data[:len(data)-k]
+			}
+			break
+		}
+	}
+	return data
+}
+
+// looksLikeUTF8 判斷內容是否為 UTF-8；僅含少量損毀位元組的 UTF-8 檔案仍視為 UTF-8
+func looksLikeUTF8(data []byte) bool {
+	if utf8.Valid(data) {
+		return true
+	}
+	valid, invalid := 0, 0
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		switch {
+		case r == utf8.RuneError && size <= 1:
+			invalid++
+		case size > 1:
+			valid++
+		}
+		i += size
+	}
+	return valid > invalid*10
+}
+
+// sniffUTF16 以 0x00 位元組出現在奇數或偶數位置的比例判斷無 BOM 的 UTF-16
+func sniffUTF16(sample []byte) string {
+	if len(sample) < 4 {
+		return ""
+	}
+	pairs := len(sample) / 2
+	evenZero, oddZero := 0, 0
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			evenZero++
+		}
+		if sample[i+1] == 0 {
+			oddZero++
+		}
+	}
+	// INI 內容以 ASCII 鍵名為主，UTF-16 時約半數以上的字元高位元組為 0
+	switch {
+	case oddZero*10 >= pairs*3 && evenZero*10 < pairs:
+		return EncodingUTF16LE
+	case evenZero*10 >= pairs*3 && oddZero*10 < pairs:
+		return EncodingUTF16BE
+	}
+	return ""
+}
+
+// guessLegacyChinese 在 Big5 與 GBK 之間推測較可能的編碼
+func guessLegacyChinese(data []byte) string {
+	big5Bad := countInvalid(data, traditionalchinese.Big5)
+	gbkBad := countInvalid(data, simplifiedchinese.GBK)
+	if big5Bad != gbkBad {
+		if big5Bad < gbkBad {
+			return EncodingBig5
+		}
+		return EncodingGBK
+	}
+
+	// 兩者皆可解碼時，依雙位元組字的分布判斷：
+	// 首位元組落在 0x81-0xA0 的只有 GBK；Big5 常用字約有四成的次位元組落在 0x40-0x7E，GB2312 範圍內則沒有
+	pairs, lowTrail := 0, 0
+	for i := 0; i+1 < len(data); i++ {
+		b := data[i]
+		if b < 0x81 || b == 0xFF {
+			continue
+		}
+		if b <= 0xA0 {
+			return EncodingGBK
+		}
+		pairs++
+		if t := data[i+1]; t >= 0x40 && t <= 0x7E {
+			lowTrail++
+		}
+		i++
+	}
+	if pairs > 0 && lowTrail*20 >= pairs {
+		return EncodingBig5
+	}
+	return EncodingGBK
+}
+
+// countInvalid 計算以指定編碼解碼後產生的替代字元數量
+func countInvalid(data []byte, enc encoding.Encoding) int {
+	out, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return len(data)
+	}
+	return bytes.Count(out, []byte("\uFFFD"))
+}
+
+// decoderFor 回傳指定編碼的解碼器；UTF-8 回傳 nil
+func decoderFor(enc string) *encoding.Decoder {
+	switch enc {
+	case EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+	case EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()
+	case EncodingBig5:
+		return traditionalchinese.Big5.NewDecoder()
+	case EncodingGBK:
+		return simplifiedchinese.GBK.NewDecoder()
+	}
+	return nil
+}
+
+// DecodeToUTF8 偵測編碼並轉為 UTF-8 文字（已移除 BOM），回傳偵測到的編碼
+// 若內容無法完整解碼，錯誤訊息會列出偵測到的編碼
+func DecodeToUTF8(data []byte) (string, string, error) {
+	enc := DetectEncoding(data)
+	if enc == EncodingUTF8 {
+		if !utf8.Valid(data) {
+			return "", enc, fmt.Errorf("file cannot be decoded cleanly (detected encoding: %s, invalid byte sequences)", enc)
+		}
+		return strings.TrimPrefix(string(data), "\uFEFF"), enc, nil
+	}
+
+	body := data
+	switch enc {
+	case EncodingUTF16LE:
+		body = bytes.TrimPrefix(body, []byte{0xFF, 0xFE})
+	case EncodingUTF16BE:
+		body = bytes.TrimPrefix(body, []byte{0xFE, 0xFF})
+	}
+	out, err := decoderFor(enc).Bytes(body)
+	if err != nil {
+		return "", enc, fmt.Errorf("decode failed (detected encoding: %s): %w", enc, err)
+	}
+	if n := bytes.Count(out, []byte("\uFFFD")) - bytes.Count(body, []byte("\uFFFD")); n > 0 {
+		return "", enc, fmt.Errorf("file cannot be decoded cleanly (detected encoding: %s, %d invalid sequences)", enc, n)
+	}
+	if enc == EncodingUTF16LE || enc == EncodingUTF16BE {
+		if len(body)%2 != 0 {
+			return "", enc, fmt.Errorf("file cannot be decoded cleanly (detected encoding: %s, odd byte length)", enc)
+		}
+	}
+	return string(out), enc, nil
+}
+
+// ReadFileAsUTF8 讀取文字檔並轉為可直接寫入的 UTF-8 內容
+// UTF-8 檔案原樣回傳；其他編碼轉為 UTF-8 並加上 BOM（遊戲需要 BOM 才能正確顯示中文）
+func ReadFileAsUTF8(filePath string) ([]byte, string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, "", err
+	}
+	text, enc, err := DecodeToUTF8(data)
+	if err != nil {
+		return nil, enc, err
+	}
+	if enc == EncodingUTF8 {
+		return data, enc, nil
+	}
+	return append(append([]byte{}, utf8BOM...), text...), enc, nil
+}
@@ -0,0 +1,130 @@
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// encodingTestDir 各種編碼的範例語系檔；expected-*.txt 為對應的 UTF-8 內容
+var encodingTestDir = filepath.Join("testdata", "encoding")
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(encodingTestDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDecodeFixtures(t *testing.T) {
+	cases := []struct {
+		file     string
+		encoding string
+		expected string
+	}{
+		{"utf8-bom.ini", EncodingUTF8, "expected-traditional.txt"},
+		{"utf16le-bom.ini", EncodingUTF16LE, "expected-traditional.txt"},
+		{"utf16le.ini", EncodingUTF16LE, "expected-traditional.txt"},
+		{"utf16be-bom.ini", EncodingUTF16BE, "expected-traditional.txt"},
+		{"utf16be.ini", EncodingUTF16BE, "expected-traditional.txt"},
+		{"big5.ini", EncodingBig5, "expected-traditional.txt"},
+		{"gbk.ini", EncodingGBK, "expected-simplified.txt"},
+	}
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			data := readFixture(t, c.file)
+			if got := DetectEncoding(data); got != c.encoding {
+				t.Errorf("DetectEncoding = %s, want %s", got, c.encoding)
+			}
+			text, enc, err := DecodeToUTF8(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if enc != c.encoding {
+				t.Errorf("DecodeToUTF8 encoding = %s, want %s", enc, c.encoding)
+			}
+			// 結果不含 BOM
+			if want := string(readFixture(t, c.expected)); text != want {
+				t.Errorf("text = %q\nwant %q", text, want)
+			}
+		})
+	}
+}
+
+func TestSniffUTF16(t *testing.T) {
+	cases := []struct {
+		name   string
+		sample []byte
+		want   string
+	}{
+		{"little endian", readFixture(t, "utf16le.ini"), EncodingUTF16LE},
+		{"big endian", readFixture(t, "utf16be.ini"), EncodingUTF16BE},
+		{"ascii", []byte("ui_Ok=OK\r\nui_Cancel=Cancel\r\n"), ""},
+		{"big5", readFixture(t, "big5.ini"), ""},
+		{"too short", []byte{'a', 0}, ""},
+		// 兩種位置都有大量 0x00 的二進位內容無法判斷
+		{"binary", []byte{0, 0, 0, 0, 'a', 0, 0, 'b'}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := sniffUTF16(c.sample); got != c.want {
+				t.Errorf("sniffUTF16 = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestGuessLegacyChinese(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"big5 fixture", readFixture(t, "big5.ini"), EncodingBig5},
+		{"gbk fixture", readFixture(t, "gbk.ini"), EncodingGBK},
+		// 「許」在 Big5 為 B3 5C，次位元組落在 0x40-0x7E
+		{"big5 low trail byte", []byte("name=\xB3\x5C\n"), EncodingBig5},
+		// 首位元組 0x81-0xA0 只出現在 GBK（「丂」為 81 40）
+		{"gbk only lead byte", []byte("name=\x81\x40\n"), EncodingGBK},
+		{"ascii only", []byte("ui_Ok=OK\n"), EncodingGBK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := guessLegacyChinese(c.data); got != c.want {
+				t.Errorf("guessLegacyChinese = %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDecodeToUTF8Undecodable(t *testing.T) {
+	utf16 := readFixture(t, "utf16le-bom.ini")
+	cases := []struct {
+		name     string
+		data     []byte
+		encoding string
+	}{
+		// 大部分為 UTF-8，夾雜一個無效位元組
+		{"utf-8 with a stray byte", append([]byte("ui_Ok=確定\r\nui_Cancel=取消\r\nship_Name=艦船名稱\r\nmission=任務目標\r\nui_Bad="), 0xFF, '\r', '\n'), EncodingUTF8},
+		{"utf-16 with an odd length", utf16[:len(utf16)-1], EncodingUTF16LE},
+		// 0xFF 在 Big5 與 GBK 中都不是有效的位元組
+		{"legacy with an invalid byte", []byte("name=\xB3\x5C\xFF\n"), EncodingBig5},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, enc, err := DecodeToUTF8(c.data)
+			if err == nil {
+				t.Fatal("expected a decode error")
+			}
+			if enc != c.encoding {
+				t.Errorf("encoding = %s, want %s", enc, c.encoding)
+			}
+			if !strings.Contains(err.Error(), "detected encoding: "+c.encoding) {
+				t.Errorf("error %q does not name %s", err, c.encoding)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package ini
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// SourceFingerprint 翻譯當時英文原文的指紋
+type SourceFingerprint struct {
+	Hash       string `json:"hash"`
+	RecordedAt string `json:"recordedAt"`
+}
+
+// SourceFingerprintDB 每個鍵翻譯時所依據的英文原文指紋
+type SourceFingerprintDB struct {
+	Version int                          `json:"version"`
+	Entries map[string]SourceFingerprint `json:"entries"`
+}
+
+// StaleEntry 英文原文在翻譯後已變動的鍵
+type StaleEntry struct {
+	Key         string `json:"key"`
+	Translation string `json:"translation"`
+	English     string `json:"english"`
+	RecordedAt  string `json:"recordedAt"`
+}
+
+// StaleCompareResult 過時譯文比對結果
+type StaleCompareResult struct {
+	Stale     []StaleEntry `json:"stale"`     // 英文已變動
+	Untracked []string     `json:"untracked"` // 尚無指紋記錄的鍵
+	Current   int          `json:"current"`   // 指紋相符的鍵數量
+}
+
+// sourceHash 計算英文原文的指紋
+func sourceHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:8])
+}
+
+// loadFingerprintDB 讀取指紋資料庫；不存在時回傳空資料庫
+func loadFingerprintDB(path string) (*SourceFingerprintDB, error) {
+	db := &SourceFingerprintDB{Version: 1, Entries: map[string]SourceFingerprint{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return db, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, db); err != nil {
+		return nil, fmt.Errorf("invalid fingerprint database %s: %w", path, err)
+	}
+	if db.Entries == nil {
+		db.Entries = map[string]SourceFingerprint{}
+	}
+	return db, nil
+}
+
+// save 寫入指紋資料庫（先寫暫存檔再替換）
+func (db *SourceFingerprintDB) save(path string) error {
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ChangedKeys 回傳 after 中值與 before 不同或新增的鍵（依鍵名排序）
+func ChangedKeys(before, after map[string]string) []string {
+	keys := []string{}
+	for k, v := range after {
+		if old, ok := before[k]; !ok || old != v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RecordFingerprints 將譯文檔中鍵所對應的英文原文指紋記錄到 dbPath
+// keys 為空時只替 iniPath 中尚無記錄的鍵建立基準；指定 keys 時覆寫這些鍵（表示已依目前英文重新翻譯），iniPath 可為空
+func RecordFingerprints(dbPath, iniPath, englishPath string, keys []string) (int, error) {
+	english, _, err := ReadValueMap(englishPath)
+	if err != nil {
+		return 0, fmt.Errorf("read english file failed: %w", err)
+	}
+	db, err := loadFingerprintDB(dbPath)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	recorded := 0
+	record := func(key string) {
+		if text, ok := english[key]; ok {
+			db.Entries[key] = SourceFingerprint{Hash: sourceHash(text), RecordedAt: now}
+			recorded++
+		}
+	}
+	if len(keys) > 0 {
+		for _, k := range keys {
+			record(k)
+		}
+	} else {
+		translated, _, err := ReadKeySet(iniPath)
+		if err != nil {
+			return 0, err
+		}
+		for k := range translated {
+			if _, ok := db.Entries[k]; !ok {
+				record(k)
+			}
+		}
+	}
+	if err := db.save(dbPath); err != nil {
+		return 0, fmt.Errorf("write fingerprint database failed: %w", err)
+	}
+	return recorded, nil
+}
+
+// CompareStale 依 dbPath 中的指紋記錄，列出譯文檔中英文原文在翻譯後已變動的鍵
+func CompareStale(currentPath, englishPath, dbPath string) (StaleCompareResult, error) {
+	result := StaleCompareResult{Stale: []StaleEntry{}, Untracked: []string{}}
+	db, err := loadFingerprintDB(dbPath)
+	if err != nil {
+		return result, err
+	}
+	english, _, err := ReadValueMap(englishPath)
+	if err != nil {
+		return result, fmt.Errorf("read reference file failed: %w", err)
+	}
+
+	seen := map[string]bool{}
+	if _, err := ScanFile(currentPath, func(ln *Line) {
+		if ln.Kind != LineEntry || seen[ln.Key] {
+			return
+		}
+		seen[ln.Key] = true
+		text, ok := english[ln.Key]
+		if !ok {
+			return
+		}
+		fp, tracked := db.Entries[ln.Key]
+		switch {
+		case !tracked:
+			result.Untracked = append(result.Untracked, ln.Key)
+		case fp.Hash != sourceHash(text):
+			result.Stale = append(result.Stale, StaleEntry{Key: ln.Key, Translation: ln.Value, English: text, RecordedAt: fp.RecordedAt})
+		default:
+			result.Current++
+		}
+	}); err != nil {
+		return result, fmt.Errorf("read current file failed: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// staleKeys 回傳過時的鍵，方便比對
+func staleKeys(r StaleCompareResult) []string {
+	keys := []string{}
+	for _, e := range r.Stale {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+func TestCompareStaleAfterEnglishChanges(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "source-fingerprints.json")
+	translated := writeINI(t, "a=甲\nb=乙\nc=丙\n")
+	oldEn := writeINI(t, "a=A\nb=B\nc=C\n")
+
+	// 沒有指紋檔：全部視為尚無記錄
+	r, err := CompareStale(translated, oldEn, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Untracked, []string{"a", "b", "c"}) || len(r.Stale) != 0 || r.Current != 0 {
+		t.Errorf("without fingerprints: %+v", r)
+	}
+
+	n, err := RecordFingerprints(db, translated, oldEn, nil)
+	if err != nil || n != 3 {
+		t.Fatalf("RecordFingerprints = %d, %v", n, err)
+	}
+
+	// b 的英文改寫、c 從英文移除：只有 b 過時，c 不回報
+	newEn := writeINI(t, "a=A\nb=B2\n")
+	r, err = CompareStale(translated, newEn, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(staleKeys(r), []string{"b"}) || len(r.Untracked) != 0 || r.Current != 1 {
+		t.Fatalf("after english change: %+v", r)
+	}
+	if e := r.Stale[0]; e.Translation != "乙" || e.English != "B2" || e.RecordedAt == "" {
+		t.Errorf("stale entry = %+v", e)
+	}
+
+	// 依新英文重新翻譯 b 後不再過時；英文沒有的 c 不會被記錄
+	if n, err := RecordFingerprints(db, "", newEn, []string{"b", "c"}); err != nil || n != 1 {
+		t.Fatalf("RecordFingerprints keys = %d, %v", n, err)
+	}
+	r, err = CompareStale(translated, newEn, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Stale) != 0 || r.Current != 2 {
+		t.Errorf("after re-recording: %+v", r)
+	}
+}
+
+func TestRecordFingerprintsKeepsBaseline(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "source-fingerprints.json")
+	translated := writeINI(t, "a=甲\n")
+	if _, err := RecordFingerprints(db, translated, writeINI(t, "a=A\n"), nil); err != nil {
+		t.Fatal(err)
+	}
+	// 不指定 keys 時，已有記錄的鍵不會改用新的英文
+	newEn := writeINI(t, "a=A2\n")
+	if n, err := RecordFingerprints(db, translated, newEn, nil); err != nil || n != 0 {
+		t.Fatalf("RecordFingerprints = %d, %v", n, err)
+	}
+	r, err := CompareStale(translated, newEn, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(staleKeys(r), []string{"a"}) {
+		t.Errorf("stale = %v, want a", staleKeys(r))
+	}
+}
+
+func TestCompareStaleInvalidDatabase(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "source-fingerprints.json")
+	if err := os.WriteFile(db, []byte("{broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := CompareStale(writeINI(t, "a=甲\n"), writeINI(t, "a=A\n"), db)
+	if err == nil || !strings.Contains(err.Error(), "invalid fingerprint database") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestChangedKeys(t *testing.T) {
+	before := map[string]string{"a": "甲", "b": "乙", "c": "丙"}
+	after := map[string]string{"a": "甲", "b": "乙2", "d": "丁"}
+	if got := ChangedKeys(before, after); !reflect.DeepEqual(got, []string{"b", "d"}) {
+		t.Errorf("ChangedKeys = %v", got)
+	}
+}
@@ -0,0 +1,81 @@
+package ini
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format 描述 INI 檔案的外觀格式：行尾、BOM 與檔尾是否有換行
+type Format struct {
+	EOL             string `json:"eol"`
+	BOM             bool   `json:"bom"`
+	TrailingNewline bool   `json:"trailingNewline"`
+}
+
+// DefaultFormat 新檔案使用的格式（與遊戲原檔一致：Windows CRLF + UTF-8 BOM）
+var DefaultFormat = Format{EOL: "\r\n", BOM: true, TrailingNewline: true}
+
+// GameFormat 安裝到遊戲資料夾時使用的格式；遊戲需要 UTF-8 BOM 才能正確顯示中文
+var GameFormat = DefaultFormat
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// FormatOverride 寫入 INI 檔案時的格式覆寫設定；空字串或 "auto" 表示沿用偵測結果
+type FormatOverride struct {
+	EOL             string `json:"eol"`             // auto | crlf | lf
+	BOM             string `json:"bom"`             // auto | on | off
+	TrailingNewline string `json:"trailingNewline"` // auto | on | off
+}
+
+// Normalize 回傳去除空白並轉為小寫的覆寫設定（"CRLF" 與 "crlf" 視為相同）
+func (o FormatOverride) Normalize() FormatOverride {
+	norm := func(v string) string { return strings.ToLower(strings.TrimSpace(v)) }
+	return FormatOverride{EOL: norm(o.EOL), BOM: norm(o.BOM), TrailingNewline: norm(o.TrailingNewline)}
+}
+
+// Validate 檢查覆寫設定的值是否合法（不分大小寫）
+func (o FormatOverride) Validate() error {
+	o = o.Normalize()
+	check := func(name, v string, allowed ...string) error {
+		if v == "" || v == "auto" {
+			return nil
+		}
+		for _, x := range allowed {
+			if v == x {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid %s override: %s", name, v)
+	}
+	if err := check("eol", o.EOL, "crlf", "lf"); err != nil {
+		return err
+	}
+	if err := check("bom", o.BOM, "on", "off"); err != nil {
+		return err
+	}
+	return check("trailingNewline", o.TrailingNewline, "on", "off")
+}
+
+// Apply 將覆寫設定套用到偵測到的格式
+func (o FormatOverride) Apply(f Format) Format {
+	o = o.Normalize()
+	switch o.EOL {
+	case "crlf":
+		f.EOL = "\r\n"
+	case "lf":
+		f.EOL = "\n"
+	}
+	switch o.BOM {
+	case "on":
+		f.BOM = true
+	case "off":
+		f.BOM = false
+	}
+	switch o.TrailingNewline {
+	case "on":
+		f.TrailingNewline = true
+	case "off":
+		f.TrailingNewline = false
+	}
+	return f
+}
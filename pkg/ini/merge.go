@@ -0,0 +1,117 @@
+package ini
+
+import "fmt"
+
+// MergeEntry 三方合併中單一鍵的比對資訊
+type MergeEntry struct {
+	Key         string `json:"key"`
+	OldEnglish  string `json:"oldEnglish"`
+	NewEnglish  string `json:"newEnglish"`
+	Translation string `json:"translation"`
+}
+
+// MergeReport 三方合併結果
+//   - Added：新版英文新增的鍵（以英文原文寫入合併檔）
+//   - Removed：新版英文已移除的鍵（自合併檔刪除）
+//   - Changed：英文原文有變動，現有譯文可能已過時（保留譯文）
+//   - Untranslated：新舊英文都有、譯文缺少的鍵（以英文原文補上）
+type MergeReport struct {
+	OutputPath     string       `json:"outputPath"`
+	Added          []MergeEntry `json:"added"`
+	Removed        []MergeEntry `json:"removed"`
+	Changed        []MergeEntry `json:"changed"`
+	Untranslated   []MergeEntry `json:"untranslated"`
+	UnchangedCount int          `json:"unchangedCount"`
+}
+
+// ReadValueMap 讀取 INI 檔案為 key -> value（重複鍵以第一次出現為準）與鍵的順序
+func ReadValueMap(filePath string) (map[string]string, []string, error) {
+	values := make(map[string]string)
+	var order []string
+	_, err := ScanFile(filePath, func(ln *Line) {
+		if ln.Kind != LineEntry {
+			return
+		}
+		if _, ok := values[ln.Key]; !ok {
+			values[ln.Key] = ln.Value
+			order = append(order, ln.Key)
+		}
+	})
+	return values, order, err
+}
+
+// MergeEnglish 以舊英文、新英文與譯文三方合併，回傳合併後的文件（尚未寫入）
+// 既有譯文的註解、順序與格式保持不變，新鍵依新版英文的順序插入
+func MergeEnglish(oldEnglishPath, newEnglishPath, translatedPath string) (*Document, MergeReport, error) {
+	report := MergeReport{
+		Added:        []MergeEntry{},
+		Removed:      []MergeEntry{},
+		Changed:      []MergeEntry{},
+		Untranslated: []MergeEntry{},
+	}
+
+	oldEn, oldOrder, err := ReadValueMap(oldEnglishPath)
+	if err != nil {
+		return nil, report, fmt.Errorf("read old english file failed: %w", err)
+	}
+	newEn, newOrder, err := ReadValueMap(newEnglishPath)
+	if err != nil {
+		return nil, report, fmt.Errorf("read new english file failed: %w", err)
+	}
+	doc, err := Load(translatedPath)
+	if err != nil {
+		return nil, report, fmt.Errorf("read translated file failed: %w", err)
+	}
+
+	// 新版已移除的鍵：自譯文刪除
+	var toRemove []*Line
+	for _, key := range oldOrder {
+		if _, ok := newEn[key]; ok {
+			continue
+		}
+		entry := MergeEntry{Key: key, OldEnglish: oldEn[key]}
+		if lines := doc.Lookup(key); len(lines) > 0 {
+			entry.Translation = lines[0].Value
+			toRemove = append(toRemove, lines...)
+		}
+		report.Removed = append(report.Removed, entry)
+	}
+	doc.Remove(toRemove...)
+
+	// 依新版英文的順序分類，缺少的鍵插入在前一個已存在鍵之後；前面沒有既有鍵時放在第一個項目之前
+	var head []*Line
+	after := make(map[*Line][]*Line)
+	var anchor *Line
+	for _, key := range newOrder {
+		newVal := newEn[key]
+		oldVal, inOld := oldEn[key]
+		lines := doc.Lookup(key)
+		if len(lines) == 0 {
+			entry := MergeEntry{Key: key, OldEnglish: oldVal, NewEnglish: newVal}
+			if inOld {
+				report.Untranslated = append(report.Untranslated, entry)
+			} else {
+				report.Added = append(report.Added, entry)
+			}
+			ln := newEntryLine(key, newVal)
+			if anchor == nil {
+				head = append(head, ln)
+			} else {
+				after[anchor] = append(after[anchor], ln)
+			}
+			continue
+		}
+		anchor = lines[len(lines)-1]
+		switch {
+		case !inOld:
+			// 譯文已先行加入新鍵
+			report.UnchangedCount++
+		case oldVal != newVal:
+			report.Changed = append(report.Changed, MergeEntry{Key: key, OldEnglish: oldVal, NewEnglish: newVal, Translation: lines[0].Value})
+		default:
+			report.UnchangedCount++
+		}
+	}
+	doc.insertLines(head, after)
+	return doc, report, nil
+}
@@ -0,0 +1,97 @@
+package ini
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func entryKeys(entries []MergeEntry) []string {
+	keys := []string{}
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+func TestMergeEnglish(t *testing.T) {
+	oldEn := writeINI(t, "a=A\nb=B\nc=C\nd=D\ne=E\n")
+	// z 新增在開頭、x 新增在中間、b 改寫、d 移除；e 兩版都有但尚未翻譯
+	newEn := writeINI(t, "z=Z\na=A\nb=B2\nx=X\nc=C\ne=E\n")
+	translated := writeINI(t, "; header comment\na=甲\n; about b\nb=乙\nc=丙\nd=丁\n; trailer\n")
+
+	doc, report, err := MergeEnglish(oldEn, newEn, translated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"; header comment",
+		"z=Z",
+		"a=甲",
+		"; about b",
+		"b=乙",
+		"x=X",
+		"c=丙",
+		"e=E",
+		"; trailer",
+	}, "\n") + "\n"
+	if got := string(doc.Bytes(doc.Format)); got != want {
+		t.Errorf("merged:\n%s\nwant:\n%s", got, want)
+	}
+	checks := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"added", entryKeys(report.Added), []string{"z", "x"}},
+		{"removed", entryKeys(report.Removed), []string{"d"}},
+		{"changed", entryKeys(report.Changed), []string{"b"}},
+		{"untranslated", entryKeys(report.Untranslated), []string{"e"}},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if report.UnchangedCount != 2 {
+		t.Errorf("unchanged = %d, want 2", report.UnchangedCount)
+	}
+	if report.Removed[0].Translation != "丁" || report.Changed[0].Translation != "乙" {
+		t.Errorf("report translations: removed %+v, changed %+v", report.Removed, report.Changed)
+	}
+}
+
+func TestApplyUpdates(t *testing.T) {
+	reference := writeINI(t, "a=A\nb=B\nc=C\nd=D\n")
+	doc := Parse("; header\r\nb=乙\r\n; keep me\r\nd=丁\r\n")
+	updates := []KeyValue{{Key: "a", Value: "甲"}, {Key: "c", Value: "丙"}, {Key: "b", Value: "乙2"}, {Key: "q", Value: "Q"}}
+	if err := ApplyUpdates(doc, reference, updates); err != nil {
+		t.Fatal(err)
+	}
+	// a 前面沒有既有鍵：放在第一個項目之前；c 接在 b 之後；參考檔沒有的 q 附加到結尾
+	want := "; header\r\na=甲\r\nb=乙2\r\nc=丙\r\n; keep me\r\nd=丁\r\nq=Q\r\n"
+	if got := string(doc.Bytes(doc.Format)); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+// 參考順序中排在所有既有鍵之前的新鍵，兩種更新方式都放在第一個項目之前
+func TestNewKeyWithoutAnchorGoesToHead(t *testing.T) {
+	content := "; comment\nb=乙\n"
+	english := writeINI(t, "a=A\nb=B\n")
+
+	merged, _, err := MergeEnglish(writeINI(t, "b=B\n"), english, writeINI(t, content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := Parse(content)
+	if err := ApplyUpdates(applied, english, []KeyValue{{Key: "a", Value: "A"}}); err != nil {
+		t.Fatal(err)
+	}
+	want := "; comment\na=A\nb=乙\n"
+	for name, doc := range map[string]*Document{"MergeEnglish": merged, "ApplyUpdates": applied} {
+		if got := string(doc.Bytes(doc.Format)); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package ini
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 佔位符問題的種類
+const (
+	IssueMissing    = "missing"    // 參考值有、譯文缺少
+	IssueExtra      = "extra"      // 譯文多出參考值沒有的佔位符
+	IssueMalformed  = "malformed"  // 佔位符格式錯誤（例如缺少右括號）
+	IssueUnbalanced = "unbalanced" // 標籤未成對
+	IssueOrder      = "order"      // printf 佔位符順序與參考值不同
+)
+
+// 問題嚴重度：error 會阻擋安裝，warning 僅提示
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// PlaceholderIssue 單一鍵的佔位符/標記問題
+type PlaceholderIssue struct {
+	Key       string `json:"key"`
+	Line      int    `json:"line"`
+	Kind      string `json:"kind"`
+	Severity  string `json:"severity"`
+	Token     string `json:"token"`
+	Reference string `json:"reference"`
+	Value     string `json:"value"`
+}
+
+// ValidationReport 譯文驗證結果
+type ValidationReport struct {
+	ReferencePath string             `json:"referencePath"` // 使用的英文參考檔（無則只檢查格式）
+	Checked       int                `json:"checked"`
+	Errors        int                `json:"errors"`
+	Warnings      int                `json:"warnings"`
+	Issues        []PlaceholderIssue `json:"issues"`
+}
+
+var (
+	// placeholderPattern 遊戲字串中的佔位符與標記：
+	// ~mission(Contractor)、printf（%s、%d、%.2f…）、跳脫字元 \n、<EM4>…</EM4>、[BR]
+	placeholderPattern = regexp.MustCompile(`~[A-Za-z_][A-Za-z0-9_]*\([^()]*\)` +
+		`|%%` +
+		`|%(?:\d+\$)?[-+#0]*(?:\d+|\*)?(?:\.(?:\d+|\*))?(?:hh|h|ll|l|L|I64)?[diouxXeEfgGcsSp]` +
+		`|\\[nrt]` +
+		`|</?[A-Za-z][A-Za-z0-9_]*(?:\s[^<>]*)?/?>` +
+		`|\[[A-Z][A-Z0-9_]*\]`)
+	// placeholderStartPattern 佔位符的開頭；未被完整比對涵蓋者視為格式錯誤
+	placeholderStartPattern = regexp.MustCompile(`~[A-Za-z_][A-Za-z0-9_]*\(|</?[A-Za-z]`)
+	tagNamePattern          = regexp.MustCompile(`^</?([A-Za-z][A-Za-z0-9_]*)`)
+)
+
+// placeholderToken 解析出的單一佔位符
+type placeholderToken struct {
+	text string
+	kind byte // '~' 函式、'%' printf、'\\' 跳脫、'<' 標籤、'[' 方括號標記
+}
+
+// valueTokens 字串中的佔位符與格式錯誤的片段
+type valueTokens struct {
+	tokens    []placeholderToken
+	malformed []string
+}
+
+// extractPlaceholders 擷取字串中的佔位符
+func extractPlaceholders(s string) valueTokens {
+	var vt valueTokens
+	spans := placeholderPattern.FindAllStringIndex(s, -1)
+	for _, sp := range spans {
+		text := s[sp[0]:sp[1]]
+		if text == "%%" {
+			continue
+		}
+		vt.tokens = append(vt.tokens, placeholderToken{text: text, kind: text[0]})
+	}
+
+	// 找出沒有被完整佔位符涵蓋的開頭
+	for _, st := range placeholderStartPattern.FindAllStringIndex(s, -1) {
+		covered := false
+		for _, sp := range spans {
+			if st[0] >= sp[0] && st[0] < sp[1] {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			end := st[0] + 24
+			if end > len(s) {
+				end = len(s)
+			}
+			vt.malformed = append(vt.malformed, strings.ToValidUTF8(s[st[0]:end], ""))
+		}
+	}
+	return vt
+}
+
+// tagName 回傳標籤名稱（小寫）；不是標籤時回傳空字串
+func tagName(text string) string {
+	m := tagNamePattern.FindStringSubmatch(text)
+	if m == nil {
+		return ""
+	}
+	return strings.ToLower(m[1])
+}
+
+// unbalancedTags 回傳未成對的標籤（依出現順序）
+func unbalancedTags(tokens []placeholderToken) []string {
+	type open struct{ name, text string }
+	var stack []open
+	var bad []string
+	for _, t := range tokens {
+		if t.kind != '<' || strings.HasSuffix(t.text, "/>") {
+			continue
+		}
+		name := tagName(t.text)
+		if name == "" {
+			continue
+		}
+		if !strings.HasPrefix(t.text, "</") {
+			stack = append(stack, open{name: name, text: t.text})
+			continue
+		}
+		if len(stack) > 0 && stack[len(stack)-1].name == name {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		bad = append(bad, t.text)
+	}
+	for _, o := range stack {
+		bad = append(bad, o.text)
+	}
+	return bad
+}
+
+// tokenSeverity 依佔位符種類決定數量不符時的嚴重度
+// ~函式與 printf 缺漏會讓遊戲顯示錯誤或當機；換行與標記不符僅影響排版
+func tokenSeverity(kind byte) string {
+	if kind == '~' || kind == '%' {
+		return SeverityError
+	}
+	return SeverityWarning
+}
+
+// comparePlaceholders 比對單一鍵的譯文與參考值，ref 為 nil 表示沒有參考值
+func comparePlaceholders(key string, line int, refValue string, ref *valueTokens, value string) []PlaceholderIssue {
+	cur := extractPlaceholders(value)
+	var issues []PlaceholderIssue
+	add := func(kind, severity, token string) {
+		issues = append(issues, PlaceholderIssue{
+			Key: key, Line: line, Kind: kind, Severity: severity, Token: token,
+			Reference: refValue, Value: value,
+		})
+	}
+
+	refMalformed := map[string]bool{}
+	if ref != nil {
+		for _, m := range ref.malformed {
+			refMalformed[m] = true
+		}
+	}
+	for _, m := range cur.malformed {
+		// 參考值中同樣的片段（例如英文的 a<b）不算譯文錯誤
+		if !refMalformed[m] {
+			add(IssueMalformed, SeverityError, m)
+		}
+	}
+	// 參考值本身標籤就不成對時（例如單獨使用的標籤），不檢查譯文
+	// 已回報不成對的標籤不再另外回報缺少或多出
+	unbalanced := map[string]bool{}
+	if ref == nil || len(unbalancedTags(ref.tokens)) == 0 {
+		for _, t := range unbalancedTags(cur.tokens) {
+			add(IssueUnbalanced, SeverityError, t)
+			unbalanced[tagName(t)] = true
+		}
+	}
+	if ref == nil {
+		return issues
+	}
+
+	// 依數量比對缺少與多出的佔位符
+	count := map[string]int{}
+	kinds := map[string]byte{}
+	var order []string
+	for _, t := range ref.tokens {
+		if count[t.text] == 0 {
+			order = append(order, t.text)
+		}
+		count[t.text]++
+		kinds[t.text] = t.kind
+	}
+	for _, t := range cur.tokens {
+		if _, ok := kinds[t.text]; !ok {
+			order = append(order, t.text)
+			kinds[t.text] = t.kind
+		}
+		count[t.text]--
+	}
+	mismatch := false
+	for _, text := range order {
+		if kinds[text] == '<' && unbalanced[tagName(text)] {
+			mismatch = mismatch || count[text] != 0
+			continue
+		}
+		switch n := count[text]; {
+		case n > 0:
+			mismatch = true
+			for i := 0; i < n; i++ {
+				add(IssueMissing, tokenSeverity(kinds[text]), text)
+			}
+		case n < 0:
+			mismatch = true
+			for i := 0; i < -n; i++ {
+				add(IssueExtra, tokenSeverity(kinds[text]), text)
+			}
+		}
+	}
+
+	// 數量一致時，非位置式的 printf 佔位符順序必須相同
+	if !mismatch {
+		refSeq := printfSequence(ref.tokens)
+		curSeq := printfSequence(cur.tokens)
+		if refSeq != curSeq && !strings.Contains(refSeq, "$") {
+			add(IssueOrder, SeverityError, curSeq)
+		}
+	}
+	return issues
+}
+
+// printfSequence 將 printf 佔位符依序串成字串
+func printfSequence(tokens []placeholderToken) string {
+	var parts []string
+	for _, t := range tokens {
+		if t.kind == '%' {
+			parts = append(parts, t.text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ValidateFile 以參考檔（可為空字串）驗證譯文檔的佔位符與標記
+func ValidateFile(translatedPath, referencePath string) (ValidationReport, error) {
+	report := ValidationReport{ReferencePath: referencePath, Issues: []PlaceholderIssue{}}
+
+	refValues := map[string]string{}
+	if referencePath != "" {
+		if _, err := ScanFile(referencePath, func(ln *Line) {
+			if ln.Kind != LineEntry {
+				return
+			}
+			if _, ok := refValues[ln.Key]; !ok {
+				refValues[ln.Key] = ln.Value
+			}
+		}); err != nil {
+			return report, fmt.Errorf("read reference file failed: %w", err)
+		}
+	}
+
+	// 串流讀取譯文，問題依行號順序加入
+	if _, err := ScanFile(translatedPath, func(ln *Line) {
+		if ln.Kind != LineEntry {
+			return
+		}
+		report.Checked++
+		var ref *valueTokens
+		refValue, ok := refValues[ln.Key]
+		if ok {
+			vt := extractPlaceholders(refValue)
+			ref = &vt
+		}
+		report.Issues = append(report.Issues, comparePlaceholders(ln.Key, ln.LineNo, refValue, ref, ln.Value)...)
+	}); err != nil {
+		return report, fmt.Errorf("read translated file failed: %w", err)
+	}
+
+	for _, is := range report.Issues {
+		if is.Severity == SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	return report, nil
+}
+
+// GateError 將驗證結果中的錯誤整理為安裝前檢查的錯誤訊息；沒有錯誤時回傳 nil
+func (report ValidationReport) GateError() error {
+	if report.Errors == 0 {
+		return nil
+	}
+	var samples []string
+	for _, is := range report.Issues {
+		if is.Severity != SeverityError {
+			continue
+		}
+		samples = append(samples, fmt.Sprintf("line %d %s: %s %s", is.Line, is.Key, is.Kind, is.Token))
+		if len(samples) == 3 {
+			break
+		}
+	}
+	return fmt.Errorf("translation has %d placeholder errors, install aborted (%s)", report.Errors, strings.Join(samples, "; "))
+}
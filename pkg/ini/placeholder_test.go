@@ -0,0 +1,136 @@
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// issueStrings 將問題轉為 kind:severity:token，方便比對
+func issueStrings(issues []PlaceholderIssue) []string {
+	out := []string{}
+	for _, is := range issues {
+		out = append(out, is.Kind+":"+is.Severity+":"+is.Token)
+	}
+	return out
+}
+
+func TestComparePlaceholders(t *testing.T) {
+	cases := []struct {
+		name  string
+		ref   string
+		noRef bool // 沒有英文參考值
+		value string
+		want  []string
+	}{
+		{name: "function ok", ref: "Deliver to ~mission(Location|Address)", value: "送到 ~mission(Location|Address)"},
+		{name: "function missing", ref: "Deliver to ~mission(Location)", value: "送到目的地",
+			want: []string{"missing:error:~mission(Location)"}},
+		{name: "function renamed", ref: "~mission(Location)", value: "~mission(Loc)",
+			want: []string{"missing:error:~mission(Location)", "extra:error:~mission(Loc)"}},
+		{name: "printf ok", ref: "%s has %d items", value: "%s 有 %d 個"},
+		{name: "printf reordered", ref: "%s has %d items", value: "%d 個在 %s",
+			want: []string{"order:error:%d %s"}},
+		{name: "positional reorder", ref: "%1$s of %2$s", value: "%2$s 的 %1$s"},
+		{name: "printf flags and precision", ref: "%.2f%% of %-5ls", value: "%-5ls 的 %.2f%%",
+			want: []string{"order:error:%-5ls %.2f"}},
+		{name: "printf missing", ref: "%.2f%% done", value: "已完成",
+			want: []string{"missing:error:%.2f"}},
+		{name: "literal percent", ref: "100%% done", value: "完成 100%%"},
+		{name: "escaped percent is not a verb", ref: "%%d literal", value: "字面 %%d"},
+		{name: "single percent ignored", ref: "100% done", value: "完成 100%"},
+		{name: "newline missing", ref: `Line one\nLine two`, value: "第一行第二行",
+			want: []string{`missing:warning:\n`}},
+		{name: "newline extra", ref: "One line", value: `一\n行`,
+			want: []string{`extra:warning:\n`}},
+		{name: "emphasis ok", ref: "<EM4>Warning</EM4> stay clear", value: "<EM4>警告</EM4> 請遠離"},
+		{name: "emphasis unclosed", ref: "<EM4>Warning</EM4>", value: "<EM4>警告",
+			want: []string{"unbalanced:error:<EM4>"}},
+		{name: "stray closing tag", ref: "Warning", value: "警告</EM4>",
+			want: []string{"unbalanced:error:</EM4>"}},
+		{name: "emphasis dropped", ref: "<EM4>Warning</EM4>", value: "警告",
+			want: []string{"missing:warning:<EM4>", "missing:warning:</EM4>"}},
+		{name: "line break marker", ref: "A[BR]B", value: "AB",
+			want: []string{"missing:warning:[BR]"}},
+		{name: "line break marker extra", ref: "AB", value: "A[BR]B",
+			want: []string{"extra:warning:[BR]"}},
+		{name: "malformed function", noRef: true, value: "~mission(Location 任務",
+			want: []string{"malformed:error:~mission(Location 任務"}},
+		{name: "malformed tag", noRef: true, value: "<EM4 警告",
+			want: []string{"malformed:error:<EM4 警告"}},
+		{name: "same fragment in reference", ref: "if a<b", value: "若 a<b"},
+		{name: "reference itself unbalanced", ref: "<EM4>Note", value: "<EM4>註"},
+		{name: "no reference unbalanced", noRef: true, value: "<EM4>警告",
+			want: []string{"unbalanced:error:<EM4>"}},
+		{name: "no reference skips counts", noRef: true, value: "%s ~mission(X) [BR]"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var ref *valueTokens
+			if !c.noRef {
+				vt := extractPlaceholders(c.ref)
+				ref = &vt
+			}
+			got := issueStrings(comparePlaceholders("key", 1, c.ref, ref, c.value))
+			want := c.want
+			if want == nil {
+				want = []string{}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got  %v\nwant %v", got, want)
+			}
+		})
+	}
+}
+
+func TestExtractPlaceholders(t *testing.T) {
+	vt := extractPlaceholders(`~action(v_interact) %1$d%% <EM4>x</EM4> [BR] \n <br/> %I64d`)
+	var got []string
+	for _, tk := range vt.tokens {
+		got = append(got, string(tk.kind)+" "+tk.text)
+	}
+	want := []string{
+		"~ ~action(v_interact)",
+		"% %1$d",
+		"< <EM4>",
+		"< </EM4>",
+		"[ [BR]",
+		`\ \n`,
+		"< <br/>",
+		"% %I64d",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+	if len(vt.malformed) != 0 {
+		t.Errorf("malformed = %v", vt.malformed)
+	}
+}
+
+func TestValidateFileGate(t *testing.T) {
+	dir := t.TempDir()
+	ref := filepath.Join(dir, "english.ini")
+	tr := filepath.Join(dir, "chinese.ini")
+	os.WriteFile(ref, []byte("a=Hello %s\nb=<EM4>Hi</EM4>\nc=Line\\nbreak\n"), 0644)
+	os.WriteFile(tr, []byte("a=你好\nb=<EM4>嗨\nc=換行\nd=新鍵 %s\n"), 0644)
+
+	report, err := ValidateFile(tr, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Checked != 4 || report.Errors != 2 || report.Warnings != 1 {
+		t.Errorf("report = checked %d errors %d warnings %d: %v", report.Checked, report.Errors, report.Warnings, issueStrings(report.Issues))
+	}
+	err = report.GateError()
+	if err == nil || !strings.Contains(err.Error(), "line 1 a: missing %s") || !strings.Contains(err.Error(), "line 2 b: unbalanced <EM4>") {
+		t.Errorf("GateError = %v", err)
+	}
+
+	// 只有警告時不阻擋安裝
+	os.WriteFile(tr, []byte("c=換行\n"), 0644)
+	if report, err := ValidateFile(tr, ref); err != nil || report.GateError() != nil {
+		t.Errorf("warnings only: %v, %v", report.GateError(), err)
+	}
+}
@@ -0,0 +1,225 @@
+package ini
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// 解析警告的種類
+const (
+	WarnMissingSeparator = "missing_separator" // 非註解、非空行卻沒有 '='
+	WarnEmptyKey         = "empty_key"         // '=' 之前沒有鍵名
+	WarnControlChar      = "control_char"      // 含有控制字元
+	WarnInvalidUTF8      = "invalid_utf8"      // 含有無效的 UTF-8 位元組
+)
+
+// maxLineSize 單行允許的最大長度
+const maxLineSize = 16 * 1024 * 1024
+
+// Warning 解析時發現的問題行
+type Warning struct {
+	Line    int    `json:"line"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Text    string `json:"text"`
+}
+
+// Scanner 以串流方式逐行解析 INI 內容
+// 用法與 bufio.Scanner 相同：反覆呼叫 Scan，再以 Line 取得目前的行
+type Scanner struct {
+	sc       *bufio.Scanner
+	line     *Line
+	lineNo   int
+	encoding string
+	warnings []Warning
+	err      error
+
+	hasBOM        bool
+	crlf, lf      int
+	lastTerm      bool
+	replacedRunes int
+}
+
+// NewScanner 建立串流解析器；會先偵測編碼並轉為 UTF-8
+func NewScanner(r io.Reader) *Scanner {
+	s := &Scanner{}
+	br := bufio.NewReaderSize(r, sniffSize)
+	sample, err := br.Peek(sniffSize)
+	if err != nil && err != io.EOF {
+		s.err = err
+		return s
+	}
+	s.encoding = detectEncoding(sample, err == io.EOF)
+
+	var src io.Reader = br
+	switch s.encoding {
+	case EncodingUTF8:
+		if bytes.HasPrefix(sample, utf8BOM) {
+			s.hasBOM = true
+			_, _ = br.Discard(len(utf8BOM))
+		}
+	case EncodingUTF16LE, EncodingUTF16BE:
+		if bytes.HasPrefix(sample, []byte{0xFF, 0xFE}) || bytes.HasPrefix(sample, []byte{0xFE, 0xFF}) {
+			_, _ = br.Discard(2)
+		}
+	}
+	if dec := decoderFor(s.encoding); dec != nil {
+		src = transform.NewReader(br, dec)
+	}
+
+	s.sc = bufio.NewScanner(src)
+	s.sc.Buffer(make([]byte, 64*1024), maxLineSize)
+	s.sc.Split(s.splitLines)
+	return s
+}
+
+// splitLines 以 \r\n、\n 或單獨的 \r 分行，並記錄行尾種類
+func (s *Scanner) splitLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			s.lf++
+			s.lastTerm = true
+			return i + 1, data[:i], nil
+		}
+		// '\r' 在緩衝區結尾時需再讀取，才能判斷是否為 \r\n
+		if i+1 >= len(data) && !atEOF {
+			return 0, nil, nil
+		}
+		s.lastTerm = true
+		if i+1 < len(data) && data[i+1] == '\n' {
+			s.crlf++
+			return i + 2, data[:i], nil
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		s.lastTerm = false
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// Scan 讀取下一行；讀完或發生錯誤時回傳 false
+func (s *Scanner) Scan() bool {
+	if s.err != nil || s.sc == nil {
+		return false
+	}
+	if !s.sc.Scan() {
+		if err := s.sc.Err(); err != nil {
+			s.err = fmt.Errorf("line %d: %w", s.lineNo+1, err)
+		} else if s.encoding != EncodingUTF8 && s.replacedRunes > 0 {
+			s.err = fmt.Errorf("file cannot be decoded cleanly (detected encoding: %s, %d invalid sequences)", s.encoding, s.replacedRunes)
+		}
+		return false
+	}
+	s.lineNo++
+	raw := s.sc.Text()
+	s.line = parseLine(raw)
+	s.line.LineNo = s.lineNo
+	s.diagnose(raw)
+	return true
+}
+
+// diagnose 檢查目前的行並記錄警告
+func (s *Scanner) diagnose(raw string) {
+	if s.encoding != EncodingUTF8 {
+		s.replacedRunes += strings.Count(raw, "\uFFFD")
+	} else if !utf8.ValidString(raw) {
+		s.warn(WarnInvalidUTF8, "invalid UTF-8 byte sequence", raw)
+	}
+	switch s.line.Kind {
+	case LineInvalid:
+		if strings.Contains(raw, "=") {
+			s.warn(WarnEmptyKey, "entry has an empty key", raw)
+		} else {
+			s.warn(WarnMissingSeparator, "line is not a comment and has no '='", raw)
+		}
+	case LineEntry:
+		if i := strings.IndexFunc(raw, isControlChar); i >= 0 {
+			s.warn(WarnControlChar, fmt.Sprintf("control character U+%04X at column %d", raw[i], utf8.RuneCountInString(raw[:i])+1), raw)
+		}
+	}
+}
+
+// isControlChar 判斷是否為不應出現在值中的控制字元（Tab 除外）
+func isControlChar(r rune) bool {
+	return (r < 0x20 && r != '\t') || r == 0x7F
+}
+
+func (s *Scanner) warn(kind, msg, raw string) {
+	const maxText = 200
+	if len(raw) > maxText {
+		raw = strings.ToValidUTF8(raw[:maxText], "") + "..."
+	}
+	s.warnings = append(s.warnings, Warning{Line: s.lineNo, Kind: kind, Message: msg, Text: raw})
+}
+
+// Line 回傳目前解析的行
+func (s *Scanner) Line() *Line {
+	return s.line
+}
+
+// Warnings 回傳目前為止累積的警告
+func (s *Scanner) Warnings() []Warning {
+	return s.warnings
+}
+
+// Encoding 回傳偵測到的來源編碼
+func (s *Scanner) Encoding() string {
+	return s.encoding
+}
+
+// Err 回傳解析過程中的錯誤
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// Format 回傳讀取到的檔案格式（應於讀取完畢後呼叫）
+func (s *Scanner) Format() Format {
+	if s.lineNo == 0 {
+		// 空檔案沒有可參考的格式
+		return DefaultFormat
+	}
+	f := Format{
+		// 非 UTF-8 的檔案寫回時轉為 UTF-8，需加上 BOM
+		BOM:             s.hasBOM || s.encoding != EncodingUTF8,
+		TrailingNewline: s.lastTerm,
+	}
+	switch {
+	case s.crlf == 0 && s.lf == 0:
+		f.EOL = DefaultFormat.EOL
+	case s.crlf >= s.lf:
+		f.EOL = "\r\n"
+	default:
+		f.EOL = "\n"
+	}
+	return f
+}
+
+// ScanFile 以串流方式讀取 INI 檔案，對每一行呼叫 fn，回傳解析警告
+func ScanFile(filePath string, fn func(*Line)) ([]Warning, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("read ini file failed: %w", err)
+	}
+	defer f.Close()
+
+	s := NewScanner(f)
+	for s.Scan() {
+		fn(s.Line())
+	}
+	if err := s.Err(); err != nil {
+		return s.Warnings(), fmt.Errorf("read ini file failed: %w", err)
+	}
+	return s.Warnings(), nil
+}
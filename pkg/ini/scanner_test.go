@@ -0,0 +1,120 @@
+package ini
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// scanAll 讀取全部內容，回傳各行原文與掃描器
+func scanAll(t *testing.T, content string) ([]string, *Scanner) {
+	t.Helper()
+	s := NewScanner(strings.NewReader(content))
+	lines := []string{}
+	for s.Scan() {
+		lines = append(lines, s.Line().Raw)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return lines, s
+}
+
+func TestScannerLineEndings(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    []string
+		format  Format
+	}{
+		{"lf", "a=1\nb=2\n", []string{"a=1", "b=2"}, Format{EOL: "\n", TrailingNewline: true}},
+		{"crlf with bom", "\uFEFFa=1\r\nb=2\r\n", []string{"a=1", "b=2"}, Format{EOL: "\r\n", BOM: true, TrailingNewline: true}},
+		// 單獨的 \r 也是行尾，但不計入 CRLF 或 LF
+		{"lone cr", "a=1\rb=2\r\nc=3", []string{"a=1", "b=2", "c=3"}, Format{EOL: "\r\n"}},
+		{"only lone cr", "a=1\rb=2\r", []string{"a=1", "b=2"}, Format{EOL: DefaultFormat.EOL, TrailingNewline: true}},
+		{"mixed, lf majority", "a=1\r\nb=2\nc=3\n", []string{"a=1", "b=2", "c=3"}, Format{EOL: "\n", TrailingNewline: true}},
+		{"no trailing newline", "a=1\nb=2", []string{"a=1", "b=2"}, Format{EOL: "\n"}},
+		{"blank lines kept", "a=1\n\n\nb=2\n", []string{"a=1", "", "", "b=2"}, Format{EOL: "\n", TrailingNewline: true}},
+		{"empty", "", []string{}, DefaultFormat},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			lines, s := scanAll(t, c.content)
+			if !reflect.DeepEqual(lines, c.want) {
+				t.Errorf("lines = %q, want %q", lines, c.want)
+			}
+			if got := s.Format(); got != c.format {
+				t.Errorf("Format = %+v, want %+v", got, c.format)
+			}
+		})
+	}
+}
+
+// \r 剛好落在第一次讀取的結尾時，需等下一次讀取才能判斷是否為 \r\n
+func TestScannerCRLFAcrossBufferBoundary(t *testing.T) {
+	first := "a=" + strings.Repeat("x", sniffSize-len("a=")-1)
+	content := first + "\r\nb=2\r\n"
+	if content[sniffSize-1] != '\r' || content[sniffSize] != '\n' {
+		t.Fatal("fixture does not split the CRLF at the read boundary")
+	}
+	lines, s := scanAll(t, content)
+	if len(lines) != 2 || lines[0] != first || lines[1] != "b=2" {
+		t.Fatalf("got %d lines, second %q", len(lines), lines[len(lines)-1])
+	}
+	if f := s.Format(); f.EOL != "\r\n" || !f.TrailingNewline {
+		t.Errorf("Format = %+v", f)
+	}
+}
+
+func TestScannerFormatOfUTF16(t *testing.T) {
+	// 無 BOM 的 UTF-16 寫回時轉為 UTF-8，需加上 BOM
+	_, s := scanAll(t, string(readFixture(t, "utf16le.ini")))
+	if s.Encoding() != EncodingUTF16LE {
+		t.Fatalf("encoding = %s", s.Encoding())
+	}
+	if f := s.Format(); f != (Format{EOL: "\r\n", BOM: true, TrailingNewline: true}) {
+		t.Errorf("Format = %+v", f)
+	}
+}
+
+func TestScannerWarnings(t *testing.T) {
+	// 含足夠的中文，整體仍判斷為 UTF-8
+	utf8Text := "ui_Ok=確定\nui_Cancel=取消\nship_Name=艦船名稱\nmission=任務目標\n"
+	cases := []struct {
+		name    string
+		content string
+		want    []Warning
+	}{
+		{"clean", "; comment\n\na=1\n", nil},
+		{"missing separator", "a=1\njust text\n", []Warning{{Line: 2, Kind: WarnMissingSeparator, Text: "just text"}}},
+		{"empty key", "=value\n", []Warning{{Line: 1, Kind: WarnEmptyKey, Text: "=value"}}},
+		{"control char", "a=b\x01c\n", []Warning{{Line: 1, Kind: WarnControlChar, Text: "a=b\x01c"}}},
+		{"tab allowed", "a=b\tc\n", nil},
+		{"invalid utf-8", utf8Text + "bad=\xFF\n", []Warning{{Line: 5, Kind: WarnInvalidUTF8, Text: "bad=\xFF"}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, s := scanAll(t, c.content)
+			var got []Warning
+			for _, w := range s.Warnings() {
+				if w.Message == "" {
+					t.Errorf("warning %+v has no message", w)
+				}
+				w.Message = ""
+				got = append(got, w)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("warnings = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestScannerLegacyDecodeError(t *testing.T) {
+	s := NewScanner(strings.NewReader("name=\xB3\x5C\xFF\n"))
+	for s.Scan() {
+	}
+	if err := s.Err(); err == nil || !strings.Contains(err.Error(), "detected encoding: "+EncodingBig5) {
+		t.Errorf("Err = %v", err)
+	}
+}
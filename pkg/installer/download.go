@@ -0,0 +1,122 @@
+package installer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"zh-tool/pkg/gamebackup"
+	"zh-tool/pkg/gameinstall"
+)
+
+// MinGlobalINILines 完整的 global.ini 至少應有的行數，用於檢查下載是否中斷
+const MinGlobalINILines = 80000
+
+// DownloadAndInstall 從指定 URL 下載 global.ini 並直接寫入 <channel>/data/Localization/chinese_(traditional)
+func DownloadAndInstall(scPath, channel, url string) (string, error) {
+	channel, err := resolveChannel(scPath, channel)
+	if err != nil {
+		return "", err
+	}
+
+	// 下載檔案
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("download failed: status %d", resp.StatusCode)
+	}
+
+	// 目標路徑：<scPath>/<channel>/data/Localization/chinese_(traditional)/global.ini
+	targetDir := gameinstall.LocalizationDir(scPath, channel, "chinese_(traditional)")
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", fmt.Errorf("mkdir failed: %w", err)
+	}
+	targetFile := filepath.Join(targetDir, "global.ini")
+	if err := gamebackup.Protect(gameinstall.ChannelDir(scPath, channel), targetFile); err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(targetFile)
+	if err != nil {
+		return "", fmt.Errorf("create file failed: %w", err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return "", fmt.Errorf("write file failed: %w", err)
+	}
+
+	return targetFile, nil
+}
+
+// Download 下載檔案到 dest
+// 如果是 global.ini 檔案，會檢查檔案完整性（行數應至少 MinGlobalINILines 行）
+func Download(url, dest string) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "zh-tool/1.0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("download failed: status %d", resp.StatusCode)
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// 如果是 global.ini 檔案，檢查檔案完整性
+	if strings.HasSuffix(strings.ToLower(dest), "global.ini") || strings.Contains(strings.ToLower(url), "global.ini") {
+		lineCount, err := CountLines(dest)
+		if err != nil {
+			// 如果無法讀取檔案，刪除下載的檔案並返回錯誤
+			os.Remove(dest)
+			return fmt.Errorf("無法驗證下載檔案完整性: %w", err)
+		}
+		if lineCount < MinGlobalINILines {
+			// 檔案不完整，刪除下載的檔案並返回錯誤
+			os.Remove(dest)
+			return fmt.Errorf("下載的檔案不完整（僅有 %d 行，應至少 %d 行）。請檢查網路連線並重試", lineCount, MinGlobalINILines)
+		}
+	}
+	return nil
+}
+
+// CountLines 計算檔案的行數
+func CountLines(filePath string) (int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	lineCount := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineCount++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return lineCount, nil
+}
@@ -0,0 +1,228 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"zh-tool/pkg/config"
+	"zh-tool/pkg/gameinstall"
+	"zh-tool/pkg/installtx"
+)
+
+// CopierName 提權拷貝程式的檔名（Windows 另加 .exe），與主程式放在同一目錄
+const CopierName = "zh-tool-copier"
+
+// ErrElevationCancelled 使用者在 UAC 提示中拒絕提權
+var ErrElevationCancelled = errors.New("elevation was cancelled")
+
+// ErrElevationTimeout 等待 UAC 回應或拷貝程式結束逾時
+var ErrElevationTimeout = errors.New("timed out waiting for the elevated copier")
+
+// ElevationTimeout 等待 UAC 回應與拷貝程式結束的時間上限
+var ElevationTimeout = 3 * time.Minute
+
+// NeedsElevation 判斷寫入 <channel> 是否需要透過提權拷貝程式
+// Windows 一律透過拷貝程式（UAC）；其他平台（Proton / Wine）在版本資料夾可寫入時直接寫入，否則以 pkexec 執行拷貝程式
+func NeedsElevation(scPath, channel string) bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return !gameinstall.ChannelWritable(scPath, channel)
+}
+
+// Discover 找出所有安裝（見 gameinstall.Discover），並依可寫入狀態標記各版本是否需要提權（與 NeedsElevation 相同）
+func Discover(ctx context.Context, saved string, scan gameinstall.ScanOptions) []gameinstall.Install {
+	installs := gameinstall.Discover(ctx, saved, scan)
+	for i := range installs {
+		for j := range installs[i].Channels {
+			ch := &installs[i].Channels[j]
+			ch.NeedsElevation = runtime.GOOS == "windows" || !ch.Writable
+		}
+	}
+	return installs
+}
+
+// RunElevated 以一次提權執行完成 req：安裝語系檔並設定或重設 system.cfg / user.cfg 的語系
+// 需要提權時（見 NeedsElevation）執行拷貝程式並等待結果，否則直接寫入；兩者使用相同的檢查與備份
+func RunElevated(scPath string, req installtx.Request) (*installtx.Result, error) {
+	channel, err := resolveChannel(scPath, req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	req.Channel = channel
+	if req.Source != "" {
+		if st, err := os.Stat(req.Source); err != nil || st.IsDir() {
+			return nil, fmt.Errorf("source file not found: %s", req.Source)
+		}
+	}
+	// 先檢查參數，避免為無效的要求跳出 UAC
+	if _, err := req.Manifest(); err != nil {
+		return nil, err
+	}
+	if !NeedsElevation(scPath, channel) {
+		return installtx.Run(gameinstall.ChannelDir(scPath, channel), req)
+	}
+
+	args := []string{
+		"--game", scPath,
+		"--channel", req.Channel,
+	}
+	if req.Source != "" {
+		args = append(args, "--source", req.Source, "--locale", req.Locale)
+	}
+	if req.Language != "" {
+		args = append(args, "--set-language", req.Language)
+	}
+	if req.ResetLanguage {
+		args = append(args, "--reset-language")
+	}
+	return runCopier(args)
+}
+
+// ApplyManifestElevated 以交易方式套用安裝清單（多個檔案與 cfg 設定）到 <channel>：
+// 需要提權時將清單寫入本機暫存後執行 zh-tool-copier --manifest，否則直接寫入
+func ApplyManifestElevated(scPath string, m *installtx.Manifest) (*installtx.Result, error) {
+	channel, err := resolveChannel(scPath, m.Channel)
+	if err != nil {
+		return nil, err
+	}
+	m.Channel = channel
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if !NeedsElevation(scPath, channel) {
+		return installtx.RunManifest(gameinstall.ChannelDir(scPath, channel), m)
+	}
+	tmpDir := config.TmpDir()
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return nil, err
+	}
+	manifestPath := filepath.Join(tmpDir, fmt.Sprintf("install-%s.json", channel))
+	if err := m.Save(manifestPath); err != nil {
+		return nil, fmt.Errorf("write install manifest failed: %w", err)
+	}
+	defer os.Remove(manifestPath)
+	return runCopier([]string{
+		"--game", scPath,
+		"--manifest", manifestPath,
+	})
+}
+
+// UninstallElevated 解除安裝：需要提權時執行 zh-tool-copier --uninstall 並等待結果，否則直接執行
+func UninstallElevated(scPath, channel, localeName string) (*installtx.Result, error) {
+	channel, err := resolveChannel(scPath, channel)
+	if err != nil {
+		return nil, err
+	}
+	if !NeedsElevation(scPath, channel) {
+		return installtx.Uninstall(gameinstall.ChannelDir(scPath, channel), channel, localeName)
+	}
+	return runCopier([]string{
+		"--uninstall",
+		"--game", scPath,
+		"--channel", channel,
+		"--locale", strings.TrimSpace(localeName),
+	})
+}
+
+// runCopier 以提權執行拷貝程式，等待結束後讀取其寫入的結果
+// 拷貝程式沒有留下結果時（例如啟動即失敗），以結束代碼判斷並指向其日誌
+func runCopier(args []string) (*installtx.Result, error) {
+	helper, err := helperPath()
+	if err != nil {
+		return nil, err
+	}
+	tmpDir := config.TmpDir()
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return nil, err
+	}
+	resultPath := filepath.Join(tmpDir, fmt.Sprintf("copier-result-%d.json", time.Now().UnixNano()))
+	defer os.Remove(resultPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), ElevationTimeout)
+	defer cancel()
+	code, err := runElevated(ctx, helper, append(args, "--result", resultPath))
+	if err != nil {
+		return nil, err
+	}
+	res, err := installtx.ReadResult(resultPath)
+	if err != nil {
+		if code != 0 {
+			return nil, fmt.Errorf("copier failed with exit code %d (see %s)", code, CopierLogDir())
+		}
+		return nil, fmt.Errorf("copier finished without a result: %w", err)
+	}
+	return res, res.Err()
+}
+
+// CopierLogDir 拷貝程式寫入日誌的資料夾
+func CopierLogDir() string {
+	return filepath.Join(filepath.Dir(config.TmpDir()), "logs")
+}
+
+// helperPath 回傳與主程式同目錄的 zh-tool-copier（Windows 為 zh-tool-copier.exe）
+func helperPath() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve executable path: %w", err)
+	}
+	name := CopierName
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	helper := filepath.Join(filepath.Dir(exePath), name)
+	if _, err := os.Stat(helper); err != nil {
+		return "", fmt.Errorf("helper not found: %s", helper)
+	}
+	return helper, nil
+}
+
+// windowsJoinArgs 以 Windows 規則組合命令列參數，必要時加上雙引號並跳脫
+func windowsJoinArgs(args []string) string {
+	var b strings.Builder
+	for i, a := range args {
+		needQuote := false
+		for _, r := range a {
+			if r == ' ' || r == '"' || r == '\t' || r == '\n' || r == '\r' {
+				needQuote = true
+				break
+			}
+		}
+		if needQuote {
+			b.WriteByte('"')
+			// 依據 Windows 規則跳脫反斜線與雙引號
+			backslashes := 0
+			for _, ch := range a {
+				if ch == '\\' {
+					backslashes++
+					b.WriteRune(ch)
+					continue
+				}
+				if ch == '"' {
+					b.WriteString(strings.Repeat("\\", backslashes))
+					backslashes = 0
+					b.WriteString("\\\"")
+					continue
+				}
+				backslashes = 0
+				b.WriteRune(ch)
+			}
+			if backslashes > 0 {
+				b.WriteString(strings.Repeat("\\", backslashes))
+			}
+			b.WriteByte('"')
+		} else {
+			b.WriteString(a)
+		}
+		if i != len(args)-1 {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+//go:build !windows
+
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// pkexecDismissed pkexec 在使用者關閉驗證視窗或未通過驗證時的結束代碼
+const pkexecDismissed = 126
+
+// runElevated 以 pkexec（polkit）執行 helper，等待結束並回傳結束代碼
+// 使用者取消驗證時回傳 ErrElevationCancelled；ctx 到期時回傳 ErrElevationTimeout（不終止拷貝程式，未完成的安裝由拷貝程式下次執行時還原）
+func runElevated(ctx context.Context, helper string, args []string) (int, error) {
+	pkexec, err := exec.LookPath("pkexec")
+	if err != nil {
+		return -1, fmt.Errorf("game folder is not writable and pkexec was not found: %w", err)
+	}
+	cmd := exec.Command(pkexec, append([]string{helper}, args...)...)
+	if err := cmd.Start(); err != nil {
+		return -1, fmt.Errorf("start pkexec failed: %w", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		return -1, ErrElevationTimeout
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.ExitCode() == pkexecDismissed {
+			return exitErr.ExitCode(), ErrElevationCancelled
+		}
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,117 @@
+//go:build windows
+
+package installer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	seeMaskNoCloseProcess = 0x00000040
+	seeMaskNoAsync        = 0x00000100
+	seeMaskFlagNoUI       = 0x00000400
+	errorCancelled        = syscall.Errno(1223)
+	waitTimeout           = 0x00000102
+)
+
+// shellExecuteInfo 對應 SHELLEXECUTEINFOW
+type shellExecuteInfo struct {
+	cbSize       uint32
+	fMask        uint32
+	hwnd         uintptr
+	lpVerb       *uint16
+	lpFile       *uint16
+	lpParameters *uint16
+	lpDirectory  *uint16
+	nShow        int32
+	hInstApp     uintptr
+	lpIDList     uintptr
+	lpClass      *uint16
+	hkeyClass    uintptr
+	dwHotKey     uint32
+	hIcon        uintptr
+	hProcess     syscall.Handle
+}
+
+// runElevated 以 ShellExecuteExW "runas" 提權啟動 helper，等待結束並回傳結束代碼
+// 使用者在 UAC 提示選擇「否」時回傳 ErrElevationCancelled；ctx 到期時回傳 ErrElevationTimeout（不終止已啟動的拷貝程式，未完成的安裝由拷貝程式下次執行時還原）
+func runElevated(ctx context.Context, helper string, args []string) (int, error) {
+	type started struct {
+		process syscall.Handle
+		err     error
+	}
+	ch := make(chan started, 1)
+	// ShellExecuteExW 在使用者回應 UAC 提示前不會返回
+	go func() {
+		process, err := shellExecuteRunas(helper, windowsJoinArgs(args), filepath.Dir(helper))
+		ch <- started{process, err}
+	}()
+
+	var process syscall.Handle
+	select {
+	case s := <-ch:
+		if s.err != nil {
+			return -1, s.err
+		}
+		process = s.process
+	case <-ctx.Done():
+		// 提示仍未回應；稍後若取得行程控制代碼則直接關閉
+		go func() {
+			if s := <-ch; s.err == nil {
+				syscall.CloseHandle(s.process)
+			}
+		}()
+		return -1, ErrElevationTimeout
+	}
+	defer syscall.CloseHandle(process)
+
+	for {
+		ev, err := syscall.WaitForSingleObject(process, 200)
+		if err != nil {
+			return -1, fmt.Errorf("wait for copier failed: %w", err)
+		}
+		if ev != waitTimeout {
+			break
+		}
+		if ctx.Err() != nil {
+			return -1, ErrElevationTimeout
+		}
+	}
+	var code uint32
+	if err := syscall.GetExitCodeProcess(process, &code); err != nil {
+		return -1, fmt.Errorf("read copier exit code failed: %w", err)
+	}
+	return int(code), nil
+}
+
+// shellExecuteRunas 呼叫 ShellExecuteExW 並回傳行程控制代碼
+func shellExecuteRunas(helper, param, cwd string) (syscall.Handle, error) {
+	modShell32 := syscall.NewLazyDLL("shell32.dll")
+	procShellExecuteExW := modShell32.NewProc("ShellExecuteExW")
+
+	info := shellExecuteInfo{
+		fMask:        seeMaskNoCloseProcess | seeMaskNoAsync | seeMaskFlagNoUI,
+		lpVerb:       syscall.StringToUTF16Ptr("runas"),
+		lpFile:       syscall.StringToUTF16Ptr(helper),
+		lpParameters: syscall.StringToUTF16Ptr(param),
+		lpDirectory:  syscall.StringToUTF16Ptr(cwd),
+		nShow:        0, // SW_HIDE
+	}
+	info.cbSize = uint32(unsafe.Sizeof(info))
+
+	r, _, e := procShellExecuteExW.Call(uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		if e == errorCancelled {
+			return 0, ErrElevationCancelled
+		}
+		return 0, fmt.Errorf("ShellExecuteExW failed: %v", e)
+	}
+	if info.hProcess == 0 {
+		return 0, fmt.Errorf("ShellExecuteExW returned no process handle")
+	}
+	return info.hProcess, nil
+}
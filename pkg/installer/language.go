@@ -0,0 +1,42 @@
+// Package installer 負責把語系檔安裝到遊戲資料夾，以及修改遊戲的語系設定（system.cfg / user.cfg）
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"zh-tool/pkg/gameinstall"
+)
+
+// resolveChannel 驗證安裝路徑並回傳正規化後的版本資料夾名稱
+func resolveChannel(scPath, channel string) (string, error) {
+	if scPath == "" || !gameinstall.Validate(scPath) {
+		return "", fmt.Errorf("invalid Star Citizen path")
+	}
+	return gameinstall.NormalizeChannel(channel)
+}
+
+// GetLanguage 讀取 <scPath>/<channel>/data/system.cfg 的 g_language 值，若不存在或讀取失敗回傳空字串
+func GetLanguage(scPath, channel string) string {
+	channel, err := resolveChannel(scPath, channel)
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(gameinstall.DataDir(scPath, channel), "system.cfg"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "g_language ") || strings.HasPrefix(trimmed, "g_language=") {
+			// 允許有或沒有空白，抓 '=' 之後的值
+			parts := strings.SplitN(strings.ReplaceAll(trimmed, " ", ""), "=", 2)
+			if len(parts) == 2 {
+				return strings.TrimSpace(parts[1])
+			}
+		}
+	}
+	return ""
+}
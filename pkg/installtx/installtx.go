@@ -0,0 +1,571 @@
+// Package installtx 以交易方式安裝多個檔案到遊戲的版本資料夾
+//
+// 安裝清單（Manifest）列出要複製的檔案與要修改的 cfg 設定。套用時先把所有新內容與
+// 目前內容暫存到版本資料夾下的 .zh-tool-staging，寫入日誌（zh-tool-journal.json）後才逐一替換；
+// 任何一步失敗都依日誌還原。程式中途結束時，下次執行時會先依日誌還原未完成的安裝（見 Recover）。
+// 執行入口為 Run、RunManifest 與 Uninstall，每次執行只在開始時還原一次。提權拷貝程式與主程式共用此套件。
+package installtx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"zh-tool/pkg/gamebackup"
+)
+
+// JournalName 版本資料夾中的安裝日誌
+const JournalName = "zh-tool-journal.json"
+
+// StagingDirName 版本資料夾中的暫存資料夾
+const StagingDirName = ".zh-tool-staging"
+
+// 日誌狀態
+const (
+	statePrepared   = "prepared"   // 已暫存，遊戲檔案尚未變動
+	stateCommitting = "committing" // 正在替換遊戲檔案
+	stateCommitted  = "committed"  // 已全部替換，只剩清理
+)
+
+// Manifest 安裝清單；路徑皆相對於版本資料夾，以 / 分隔
+type Manifest struct {
+	Version int       `json:"version"`
+	Channel string    `json:"channel"`
+	Files   []FileOp  `json:"files"`
+	Config  []CfgEdit `json:"config"`
+	Remove  []string  `json:"remove,omitempty"` // 要刪除的檔案
+
+	restoring bool // 解除安裝：目標正在還原為原檔，不再交由 gamebackup 建立備份
+}
+
+// FileOp 將來源檔案複製到版本資料夾下的 Target
+type FileOp struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// CfgEdit 修改 cfg 檔案的設定：Set 依序設定（不存在則附加），Defaults 只在檔案中沒有時附加，
+// Remove 移除指定設定。檔案不存在時建立；修改後沒有任何設定時刪除檔案
+type CfgEdit struct {
+	Target   string    `json:"target"`
+	Set      []Setting `json:"set,omitempty"`
+	Defaults []Setting `json:"defaults,omitempty"`
+	Remove   []string  `json:"remove,omitempty"`
+}
+
+// Setting cfg 的單一設定
+type Setting struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// LoadManifest 讀取安裝清單
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid install manifest: %w", err)
+	}
+	return m, nil
+}
+
+// Save 寫入安裝清單
+func (m *Manifest) Save(path string) error {
+	if m.Version == 0 {
+		m.Version = 1
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// journalEntry 單一目標檔案的交易記錄
+type journalEntry struct {
+	Target  string `json:"target"`  // 相對於版本資料夾
+	Staged  string `json:"staged"`  // 暫存資料夾中的新內容
+	Backup  string `json:"backup"`  // 暫存資料夾中的目前內容；Existed 為 false 時為空
+	Existed bool   `json:"existed"` // 安裝前目標檔案是否存在
+	Delete  bool   `json:"delete"`  // 提交時刪除目標檔案（cfg 修改後沒有任何設定）
+}
+
+// journal 安裝日誌
+type journal struct {
+	Version     int            `json:"version"`
+	ID          string         `json:"id"`
+	State       string         `json:"state"`
+	CreatedAt   string         `json:"createdAt"`
+	Entries     []journalEntry `json:"entries"`
+	CreatedDirs []string       `json:"createdDirs"` // 安裝時建立的資料夾（由淺到深），還原時刪除
+}
+
+// checkTarget 驗證目標路徑：只允許 data/ 之下與 user.cfg，且不得是本工具的記錄檔
+func checkTarget(target string) (string, error) {
+	rel := filepath.ToSlash(filepath.Clean(filepath.FromSlash(strings.TrimSpace(target))))
+	if rel == "." || rel == "" || filepath.IsAbs(filepath.FromSlash(rel)) || strings.HasPrefix(rel, "/") ||
+		rel == ".." || strings.HasPrefix(rel, "../") || strings.Contains(rel, ":") {
+		return "", fmt.Errorf("invalid target path: %s", target)
+	}
+	lower := strings.ToLower(rel)
+	if lower != "user.cfg" && !strings.HasPrefix(lower, "data/") {
+		return "", fmt.Errorf("target not allowed: %s", target)
+	}
+	if strings.HasSuffix(lower, gamebackup.BackupSuffix) || strings.HasSuffix(lower, ".tmp") {
+		return "", fmt.Errorf("target not allowed: %s", target)
+	}
+	return rel, nil
+}
+
+// Validate 檢查清單的來源檔案與目標路徑
+func (m *Manifest) Validate() error {
+	if len(m.Files) == 0 && len(m.Config) == 0 && len(m.Remove) == 0 {
+		return fmt.Errorf("install manifest is empty")
+	}
+	seen := map[string]bool{}
+	check := func(target string) error {
+		rel, err := checkTarget(target)
+		if err != nil {
+			return err
+		}
+		key := strings.ToLower(rel)
+		if seen[key] {
+			return fmt.Errorf("duplicate target: %s", target)
+		}
+		seen[key] = true
+		return nil
+	}
+	for _, f := range m.Files {
+		if err := check(f.Target); err != nil {
+			return err
+		}
+		if st, err := os.Stat(f.Source); err != nil || st.IsDir() {
+			return fmt.Errorf("invalid source file: %s", f.Source)
+		}
+	}
+	for _, c := range m.Config {
+		if err := check(c.Target); err != nil {
+			return err
+		}
+		for _, s := range append(append([]Setting{}, c.Set...), c.Defaults...) {
+			if !validKey(s.Key) || strings.ContainsAny(s.Value, "\r\n") {
+				return fmt.Errorf("invalid setting %q in %s", s.Key, c.Target)
+			}
+		}
+		for _, k := range c.Remove {
+			if !validKey(k) {
+				return fmt.Errorf("invalid setting %q in %s", k, c.Target)
+			}
+		}
+	}
+	for _, r := range m.Remove {
+		if err := check(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validKey(k string) bool {
+	return k != "" && strings.TrimSpace(k) == k && !strings.ContainsAny(k, "=\r\n ")
+}
+
+func journalPath(channelDir string) string {
+	return filepath.Join(channelDir, JournalName)
+}
+
+func stagingDir(channelDir string) string {
+	return filepath.Join(channelDir, StagingDirName)
+}
+
+func loadJournal(channelDir string) (*journal, error) {
+	data, err := os.ReadFile(journalPath(channelDir))
+	if err != nil {
+		return nil, err
+	}
+	j := &journal{}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("invalid install journal: %w", err)
+	}
+	return j, nil
+}
+
+// save 寫入日誌並確保落盤，之後才變動遊戲檔案
+func (j *journal) save(channelDir string) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	p := journalPath(channelDir)
+	if err := writeFileSync(p+".tmp", data); err != nil {
+		return fmt.Errorf("write install journal failed: %w", err)
+	}
+	return os.Rename(p+".tmp", p)
+}
+
+// begin 檢查版本資料夾並處理上次未完成的安裝（見 Recover），記錄於 res.Recovered / res.SetAside
+// 每次執行只在入口呼叫一次
+func begin(channelDir string, res *Result) error {
+	if st, err := os.Stat(channelDir); err != nil || !st.IsDir() {
+		return res.fail(fmt.Errorf("channel directory not found: %s", channelDir))
+	}
+	recovered, aside, err := recoverChannel(channelDir)
+	if err != nil {
+		return res.fail(fmt.Errorf("recover interrupted install failed: %w", err))
+	}
+	res.Recovered = recovered
+	res.SetAside = aside
+	return nil
+}
+
+// commit 套用清單並將寫入或刪除的檔案記錄於 res
+func commit(channelDir string, m *Manifest, res *Result) error {
+	changed, err := apply(channelDir, m)
+	if err != nil {
+		return res.fail(err)
+	}
+	res.OK = true
+	res.Changed = changed
+	return nil
+}
+
+// RunManifest 在版本資料夾直接套用安裝清單（需要寫入權限），回傳執行結果；失敗時結果中也會記錄錯誤
+func RunManifest(channelDir string, m *Manifest) (*Result, error) {
+	res := &Result{Channel: m.Channel, Changed: []string{}}
+	if err := begin(channelDir, res); err != nil {
+		return res, err
+	}
+	return res, commit(channelDir, m, res)
+}
+
+// apply 以交易方式套用清單到版本資料夾；失敗時還原所有已替換的檔案
+// 呼叫前須已處理上次未完成的安裝（見 begin）；第一次修改的檔案同時交由 gamebackup 保留原檔
+// 回傳實際寫入或刪除的檔案（相對於版本資料夾）
+func apply(channelDir string, m *Manifest) ([]string, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	j, err := stage(channelDir, m)
+	if err != nil {
+		_ = os.RemoveAll(stagingDir(channelDir))
+		return nil, err
+	}
+	if err := j.save(channelDir); err != nil {
+		_ = os.RemoveAll(stagingDir(channelDir))
+		return nil, err
+	}
+	for _, e := range j.Entries {
+		if m.restoring {
+			break
+		}
+		if err := gamebackup.Protect(channelDir, filepath.Join(channelDir, filepath.FromSlash(e.Target))); err != nil {
+			return nil, abort(channelDir, j, fmt.Errorf("backup %s failed: %w", e.Target, err))
+		}
+	}
+	// 記錄要建立的資料夾，解除安裝後已清空者一併刪除
+	if !m.restoring {
+		if err := gamebackup.RecordDirs(channelDir, j.CreatedDirs); err != nil {
+			return nil, abort(channelDir, j, fmt.Errorf("record created folders failed: %w", err))
+		}
+	}
+
+	j.State = stateCommitting
+	if err := j.save(channelDir); err != nil {
+		return nil, abort(channelDir, j, err)
+	}
+	for _, d := range j.CreatedDirs {
+		if err := os.MkdirAll(filepath.Join(channelDir, filepath.FromSlash(d)), 0755); err != nil {
+			return nil, abort(channelDir, j, fmt.Errorf("mkdir failed: %w", err))
+		}
+	}
+	staging := stagingDir(channelDir)
+	changed := []string{}
+	for _, e := range j.Entries {
+		target := filepath.Join(channelDir, filepath.FromSlash(e.Target))
+		if e.Delete {
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return nil, abort(channelDir, j, fmt.Errorf("remove %s failed: %w", e.Target, err))
+			}
+		} else if err := os.Rename(filepath.Join(staging, e.Staged), target); err != nil {
+			return nil, abort(channelDir, j, fmt.Errorf("replace %s failed: %w", e.Target, err))
+		}
+		changed = append(changed, e.Target)
+	}
+
+	// 檔案已全部替換；日誌無法更新時直接清理，避免下次執行誤將完成的安裝還原
+	j.State = stateCommitted
+	_ = j.save(channelDir)
+	return changed, cleanup(channelDir)
+}
+
+// stage 將新內容與目前內容寫入暫存資料夾，回傳待提交的日誌
+func stage(channelDir string, m *Manifest) (*journal, error) {
+	id := time.Now().Format("20060102-150405.000")
+	staging := stagingDir(channelDir)
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return nil, fmt.Errorf("create staging folder failed: %w", err)
+	}
+	j := &journal{Version: 1, ID: id, State: statePrepared, CreatedAt: time.Now().Format(time.RFC3339), Entries: []journalEntry{}, CreatedDirs: []string{}}
+	dirs := map[string]bool{}
+
+	add := func(target string, content func(current []byte) ([]byte, bool, error)) error {
+		rel, err := checkTarget(target)
+		if err != nil {
+			return err
+		}
+		full := filepath.Join(channelDir, filepath.FromSlash(rel))
+		e := journalEntry{Target: rel, Staged: fmt.Sprintf("%d.new", len(j.Entries))}
+		current, err := os.ReadFile(full)
+		switch {
+		case err == nil:
+			e.Existed = true
+			e.Backup = fmt.Sprintf("%d.old", len(j.Entries))
+			if err := writeFileSync(filepath.Join(staging, e.Backup), current); err != nil {
+				return fmt.Errorf("stage %s failed: %w", rel, err)
+			}
+		case os.IsNotExist(err):
+			current = nil
+		default:
+			return fmt.Errorf("read %s failed: %w", rel, err)
+		}
+		data, del, err := content(current)
+		if err != nil {
+			return fmt.Errorf("stage %s failed: %w", rel, err)
+		}
+		if del {
+			// 不存在的檔案不需要刪除
+			if !e.Existed {
+				return nil
+			}
+			e.Delete = true
+			e.Staged = ""
+			j.Entries = append(j.Entries, e)
+			return nil
+		}
+		if err := writeFileSync(filepath.Join(staging, e.Staged), data); err != nil {
+			return fmt.Errorf("stage %s failed: %w", rel, err)
+		}
+		// 記錄需要建立的資料夾（由淺到深）
+		var missing []string
+		for d := filepath.Dir(full); d != channelDir && len(d) > len(channelDir); d = filepath.Dir(d) {
+			if _, err := os.Stat(d); err == nil {
+				break
+			}
+			r, _ := filepath.Rel(channelDir, d)
+			missing = append([]string{filepath.ToSlash(r)}, missing...)
+		}
+		for _, d := range missing {
+			if !dirs[d] {
+				dirs[d] = true
+				j.CreatedDirs = append(j.CreatedDirs, d)
+			}
+		}
+		j.Entries = append(j.Entries, e)
+		return nil
+	}
+
+	for _, f := range m.Files {
+		src := f.Source
+		if err := add(f.Target, func([]byte) ([]byte, bool, error) {
+			data, err := os.ReadFile(src)
+			return data, false, err
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range m.Config {
+		edit := c
+		if err := add(c.Target, func(current []byte) ([]byte, bool, error) {
+			data := edit.Edit(current)
+			return data, len(data) == 0, nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range m.Remove {
+		if err := add(r, func([]byte) ([]byte, bool, error) { return nil, true, nil }); err != nil {
+			return nil, err
+		}
+	}
+	return j, nil
+}
+
+// abort 還原已替換的檔案並回傳原始錯誤
+func abort(channelDir string, j *journal, cause error) error {
+	if err := rollback(channelDir, j); err != nil {
+		return fmt.Errorf("%v (rollback failed: %v)", cause, err)
+	}
+	return cause
+}
+
+// rollback 依日誌還原所有目標檔案；可重複執行
+func rollback(channelDir string, j *journal) error {
+	staging := stagingDir(channelDir)
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		e := j.Entries[i]
+		target := filepath.Join(channelDir, filepath.FromSlash(e.Target))
+		if !e.Existed {
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(staging, e.Backup))
+		if err != nil {
+			return fmt.Errorf("read backup of %s failed: %w", e.Target, err)
+		}
+		if err := writeFileSync(target+".tmp", data); err != nil {
+			return err
+		}
+		if err := os.Rename(target+".tmp", target); err != nil {
+			return err
+		}
+	}
+	for i := len(j.CreatedDirs) - 1; i >= 0; i-- {
+		// 只刪除空資料夾
+		_ = os.Remove(filepath.Join(channelDir, filepath.FromSlash(j.CreatedDirs[i])))
+	}
+	return cleanup(channelDir)
+}
+
+// cleanup 刪除暫存資料夾與日誌
+func cleanup(channelDir string) error {
+	if err := os.RemoveAll(stagingDir(channelDir)); err != nil {
+		return err
+	}
+	if err := os.Remove(journalPath(channelDir)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Recover 處理上次未完成的安裝：替換途中中斷的安裝會被還原，已完成的只做清理
+// 有還原任何檔案時回傳 true；日誌無法讀取時移到一旁（見 setAside），不阻擋之後的執行
+func Recover(channelDir string) (bool, error) {
+	recovered, _, err := recoverChannel(channelDir)
+	return recovered, err
+}
+
+// recoverChannel 同 Recover，另外回傳被移到一旁的日誌（相對於版本資料夾，沒有則為空字串）
+func recoverChannel(channelDir string) (bool, string, error) {
+	j, err := loadJournal(channelDir)
+	if os.IsNotExist(err) {
+		// 沒有日誌：暫存資料夾（若有）來自尚未開始替換的安裝
+		return false, "", os.RemoveAll(stagingDir(channelDir))
+	}
+	if err != nil {
+		// 無法得知中斷在哪個階段，不還原也不刪除：保留日誌與暫存檔供事後查看
+		aside, serr := setAside(channelDir)
+		if serr != nil {
+			return false, "", fmt.Errorf("%v; move it aside failed: %w", err, serr)
+		}
+		return false, aside, nil
+	}
+	switch j.State {
+	case stateCommitted, statePrepared:
+		return false, "", cleanup(channelDir)
+	default:
+		return true, "", rollback(channelDir, j)
+	}
+}
+
+// setAside 將無法讀取的日誌與暫存資料夾加上 .corrupt-<時間> 後綴，回傳日誌的新名稱
+func setAside(channelDir string) (string, error) {
+	suffix := ".corrupt-" + time.Now().Format("20060102-150405")
+	if err := os.Rename(stagingDir(channelDir), stagingDir(channelDir)+suffix); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := os.Rename(journalPath(channelDir), journalPath(channelDir)+suffix); err != nil {
+		return "", err
+	}
+	return JournalName + suffix, nil
+}
+
+// Edit 依設定修改 cfg 內容，保留其他行與原本的換行格式；沒有任何內容時回傳空切片
+func (c CfgEdit) Edit(content []byte) []byte {
+	text := string(content)
+	newline := "\n"
+	if strings.Contains(text, "\r\n") {
+		newline = "\r\n"
+	}
+	var lines []string
+	if text != "" {
+		lines = strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+	}
+	// 設定可寫成 key=value 或 key value
+	keyOf := func(line string) string {
+		line = strings.TrimSpace(line)
+		if i := strings.IndexAny(line, "= \t"); i >= 0 {
+			return line[:i]
+		}
+		return line
+	}
+	removed := map[string]bool{}
+	for _, k := range c.Remove {
+		removed[strings.ToLower(k)] = true
+	}
+	done := map[string]bool{}
+	var out []string
+	for _, line := range lines {
+		key := strings.ToLower(keyOf(line))
+		if removed[key] {
+			continue
+		}
+		replaced := false
+		for _, s := range c.Set {
+			if strings.ToLower(s.Key) == key {
+				if !done[key] {
+					out = append(out, s.Key+"="+s.Value)
+					done[key] = true
+				}
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, line)
+			if key != "" {
+				done[key] = true
+			}
+		}
+	}
+	for _, s := range append(append([]Setting{}, c.Set...), c.Defaults...) {
+		if key := strings.ToLower(s.Key); !done[key] && !removed[key] {
+			out = append(out, s.Key+"="+s.Value)
+			done[key] = true
+		}
+	}
+	if len(out) == 0 {
+		return []byte{}
+	}
+	return []byte(strings.Join(out, newline) + newline)
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
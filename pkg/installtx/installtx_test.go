@@ -0,0 +1,309 @@
+package installtx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"zh-tool/pkg/gamebackup"
+)
+
+const localeTarget = "data/Localization/chinese/global.ini"
+
+func writeFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func assertMissing(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s should not exist", path)
+	}
+}
+
+// newChannel 建立版本資料夾（含 data 與使用者設定的 user.cfg）與要安裝的語系檔
+func newChannel(t *testing.T) (channelDir, source string) {
+	t.Helper()
+	root := t.TempDir()
+	channelDir = filepath.Join(root, "LIVE")
+	writeFile(t, filepath.Join(channelDir, UserCfg), "r_width=1920\r\nr_height=1080\r\n")
+	if err := os.MkdirAll(filepath.Join(channelDir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	source = writeFile(t, filepath.Join(root, "global.ini"), "\uFEFFui_Ok=確定\r\n")
+	return channelDir, source
+}
+
+func installManifest(source string) *Manifest {
+	return &Manifest{
+		Version: 1,
+		Channel: "LIVE",
+		Files:   []FileOp{{Source: source, Target: localeTarget}},
+		Config: []CfgEdit{
+			{Target: SystemCfg, Set: []Setting{{Key: "g_language", Value: "chinese"}}, Defaults: []Setting{{Key: "g_languageAudio", Value: "english"}}},
+			{Target: UserCfg, Set: []Setting{{Key: "g_language", Value: "chinese"}}},
+		},
+	}
+}
+
+// assertClean 交易結束後不留下日誌與暫存資料夾
+func assertClean(t *testing.T, channelDir string) {
+	t.Helper()
+	assertMissing(t, filepath.Join(channelDir, JournalName))
+	assertMissing(t, filepath.Join(channelDir, StagingDirName))
+}
+
+func TestRunManifestCommits(t *testing.T) {
+	dir, source := newChannel(t)
+	res, err := RunManifest(dir, installManifest(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.OK || res.Recovered || len(res.Changed) != 3 {
+		t.Errorf("result = %+v", res)
+	}
+	if got := readFile(t, filepath.Join(dir, localeTarget)); got != "\uFEFFui_Ok=確定\r\n" {
+		t.Errorf("global.ini = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, SystemCfg)); got != "g_language=chinese\ng_languageAudio=english\n" {
+		t.Errorf("system.cfg = %q", got)
+	}
+	// 保留使用者的其他設定與換行格式
+	if got := readFile(t, filepath.Join(dir, UserCfg)); got != "r_width=1920\r\nr_height=1080\r\ng_language=chinese\r\n" {
+		t.Errorf("user.cfg = %q", got)
+	}
+	assertClean(t, dir)
+
+	// 第一次修改前的狀態交由 gamebackup 記錄
+	backups, err := gamebackup.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups.Entries) != 3 {
+		t.Errorf("backup entries = %+v", backups.Entries)
+	}
+	if got := readFile(t, filepath.Join(dir, UserCfg)+gamebackup.BackupSuffix); got != "r_width=1920\r\nr_height=1080\r\n" {
+		t.Errorf("user.cfg backup = %q", got)
+	}
+}
+
+func TestRunManifestRollsBackOnFailure(t *testing.T) {
+	dir, source := newChannel(t)
+	// 備份記錄無法讀取：在替換任何檔案前失敗
+	writeFile(t, filepath.Join(dir, gamebackup.ManifestName), "{not json")
+
+	res, err := RunManifest(dir, installManifest(source))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if res.OK || res.Error == "" {
+		t.Errorf("result = %+v", res)
+	}
+	if got := readFile(t, filepath.Join(dir, UserCfg)); got != "r_width=1920\r\nr_height=1080\r\n" {
+		t.Errorf("user.cfg changed: %q", got)
+	}
+	assertMissing(t, filepath.Join(dir, SystemCfg))
+	assertMissing(t, filepath.Join(dir, "data", "Localization"))
+	assertClean(t, dir)
+}
+
+func TestRunManifestRejectsInvalidTargets(t *testing.T) {
+	dir, source := newChannel(t)
+	for _, target := range []string{"../escape.ini", "Bin64/StarCitizen.exe", "data/system.cfg.bak", "C:/Windows/win.ini", ""} {
+		m := &Manifest{Files: []FileOp{{Source: source, Target: target}}}
+		if _, err := RunManifest(dir, m); err == nil {
+			t.Errorf("target %q accepted", target)
+		}
+	}
+	assertClean(t, dir)
+}
+
+// interrupt 模擬替換途中結束的安裝：日誌為 committing，只有第一個檔案已替換
+func interrupt(t *testing.T, channelDir string, m *Manifest, state string) {
+	t.Helper()
+	j, err := stage(channelDir, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.State = state
+	if err := j.save(channelDir); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range j.CreatedDirs {
+		if err := os.MkdirAll(filepath.Join(channelDir, filepath.FromSlash(d)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e := j.Entries[0]
+	if err := os.Rename(filepath.Join(stagingDir(channelDir), e.Staged), filepath.Join(channelDir, filepath.FromSlash(e.Target))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecoverRollsBackInterruptedCommit(t *testing.T) {
+	dir, source := newChannel(t)
+	m := &Manifest{
+		Files:  []FileOp{{Source: source, Target: localeTarget}},
+		Config: []CfgEdit{{Target: UserCfg, Set: []Setting{{Key: "g_language", Value: "chinese"}}}},
+	}
+	interrupt(t, dir, m, stateCommitting)
+
+	recovered, err := Recover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !recovered {
+		t.Error("Recover reported nothing to roll back")
+	}
+	assertMissing(t, filepath.Join(dir, "data", "Localization"))
+	if got := readFile(t, filepath.Join(dir, UserCfg)); got != "r_width=1920\r\nr_height=1080\r\n" {
+		t.Errorf("user.cfg = %q", got)
+	}
+	assertClean(t, dir)
+
+	// 已沒有未完成的安裝
+	if recovered, err := Recover(dir); recovered || err != nil {
+		t.Errorf("second Recover = %v, %v", recovered, err)
+	}
+}
+
+func TestRecoverFinishedOrUnstartedInstall(t *testing.T) {
+	for _, state := range []string{statePrepared, stateCommitted} {
+		t.Run(state, func(t *testing.T) {
+			dir, source := newChannel(t)
+			m := &Manifest{Files: []FileOp{{Source: source, Target: localeTarget}}}
+			interrupt(t, dir, m, state)
+			recovered, err := Recover(dir)
+			if err != nil || recovered {
+				t.Errorf("Recover = %v, %v; want only cleanup", recovered, err)
+			}
+			// 只清理日誌與暫存資料夾，不變動遊戲檔案
+			if _, err := os.Stat(filepath.Join(dir, localeTarget)); err != nil {
+				t.Errorf("installed file removed: %v", err)
+			}
+			assertClean(t, dir)
+		})
+	}
+}
+
+func TestRunSetsAsideCorruptJournal(t *testing.T) {
+	dir, source := newChannel(t)
+	writeFile(t, filepath.Join(dir, JournalName), "{not json")
+	writeFile(t, filepath.Join(dir, StagingDirName, "0001"), "staged")
+
+	res, err := Run(dir, Request{Channel: "LIVE", Locale: "chinese", Source: source})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.OK || res.Recovered || res.SetAside == "" {
+		t.Fatalf("result = %+v", res)
+	}
+	// 壞掉的日誌與暫存資料夾保留在一旁，不影響這次安裝
+	if got := readFile(t, filepath.Join(dir, res.SetAside)); got != "{not json" {
+		t.Errorf("set-aside journal = %q", got)
+	}
+	suffix := strings.TrimPrefix(res.SetAside, JournalName)
+	if got := readFile(t, filepath.Join(dir, StagingDirName+suffix, "0001")); got != "staged" {
+		t.Errorf("set-aside staging file = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, localeTarget)); err != nil {
+		t.Errorf("global.ini not installed: %v", err)
+	}
+	assertClean(t, dir)
+
+	// 之後的執行不再受影響
+	if res, err := Run(dir, Request{Channel: "LIVE", Language: "chinese"}); err != nil || res.SetAside != "" {
+		t.Errorf("second Run = %+v, %v", res, err)
+	}
+}
+
+func TestRunRecoversOnceAtStart(t *testing.T) {
+	dir, source := newChannel(t)
+	interrupt(t, dir, &Manifest{Files: []FileOp{{Source: source, Target: localeTarget}}}, stateCommitting)
+
+	res, err := Run(dir, Request{Channel: "LIVE", Language: "chinese"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Recovered {
+		t.Error("result does not report the recovered install")
+	}
+	assertMissing(t, filepath.Join(dir, localeTarget))
+	if got := readFile(t, filepath.Join(dir, UserCfg)); got != "r_width=1920\r\nr_height=1080\r\nsys_languages=chinese\r\ng_language=chinese\r\n" {
+		t.Errorf("user.cfg = %q", got)
+	}
+	assertClean(t, dir)
+}
+
+func TestRecoverRemovesStrayStaging(t *testing.T) {
+	dir, _ := newChannel(t)
+	writeFile(t, filepath.Join(dir, StagingDirName, "0.new"), "partial")
+	if recovered, err := Recover(dir); recovered || err != nil {
+		t.Errorf("Recover = %v, %v", recovered, err)
+	}
+	assertClean(t, dir)
+}
+
+func TestCfgEdit(t *testing.T) {
+	cases := []struct {
+		name    string
+		edit    CfgEdit
+		content string
+		want    string
+	}{
+		{
+			name:    "set replaces in place",
+			edit:    CfgEdit{Set: []Setting{{Key: "g_language", Value: "chinese"}}},
+			content: "r_width=1920\r\nG_LANGUAGE = english\r\nr_height=1080\r\n",
+			want:    "r_width=1920\r\ng_language=chinese\r\nr_height=1080\r\n",
+		},
+		{
+			name:    "set appends to a new file",
+			edit:    CfgEdit{Set: []Setting{{Key: "g_language", Value: "chinese"}}, Defaults: []Setting{{Key: "g_languageAudio", Value: "english"}}},
+			content: "",
+			want:    "g_language=chinese\ng_languageAudio=english\n",
+		},
+		{
+			name:    "defaults keep existing values",
+			edit:    CfgEdit{Defaults: []Setting{{Key: "g_languageAudio", Value: "english"}}},
+			content: "g_languageAudio german\n",
+			want:    "g_languageAudio german\n",
+		},
+		{
+			name:    "remove drops duplicates",
+			edit:    CfgEdit{Remove: languageKeys},
+			content: "sys_languages=chinese\nr_width=1920\ng_language=chinese\ng_language=english",
+			want:    "r_width=1920\n",
+		},
+		{
+			name:    "nothing left",
+			edit:    CfgEdit{Remove: languageKeys},
+			content: "sys_languages=chinese\r\ng_language=chinese\r\n",
+			want:    "",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := string(c.edit.Edit([]byte(c.content))); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
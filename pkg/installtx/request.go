@@ -0,0 +1,152 @@
+package installtx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 遊戲的語系設定檔（相對於版本資料夾）
+const (
+	SystemCfg = "data/system.cfg"
+	UserCfg   = "user.cfg"
+)
+
+// languageKeys 本工具會寫入與重設的語系設定
+var languageKeys = []string{"sys_languages", "g_language"}
+
+// Request 一次提權執行要完成的工作：安裝語系檔、設定或重設語系，可同時進行
+type Request struct {
+	Channel       string `json:"channel"`
+	Locale        string `json:"locale"`        // 語系資料夾名稱（安裝 global.ini 時使用）
+	Source        string `json:"source"`        // 要安裝的 global.ini；空字串表示不安裝
+	Language      string `json:"language"`      // 要設定的 sys_languages / g_language；空字串表示不變更
+	ResetLanguage bool   `json:"resetLanguage"` // 回復原版語系：刪除 system.cfg，並移除 user.cfg 中的語系設定
+}
+
+// checkName 檢查語系名稱不得跳出 Localization 資料夾，也不得寫入 cfg 以外的內容
+func checkName(kind, name string) error {
+	if name != strings.TrimSpace(name) || name == "" || name != filepath.Base(name) ||
+		name == "." || name == ".." || strings.ContainsAny(name, "/\\\r\n= ") {
+		return fmt.Errorf("invalid %s: %q", kind, name)
+	}
+	return nil
+}
+
+// Manifest 將工作轉為安裝清單
+func (r Request) Manifest() (*Manifest, error) {
+	m := &Manifest{Version: 1, Channel: r.Channel, Files: []FileOp{}, Config: []CfgEdit{}}
+	if r.Source != "" {
+		if err := checkName("locale", r.Locale); err != nil {
+			return nil, err
+		}
+		m.Files = append(m.Files, FileOp{Source: r.Source, Target: "data/Localization/" + r.Locale + "/global.ini"})
+	}
+	switch {
+	case r.ResetLanguage && r.Language != "":
+		return nil, fmt.Errorf("cannot set and reset the language at the same time")
+	case r.ResetLanguage:
+		// 與先前的 ResetLanguage 相同刪除 system.cfg；user.cfg 可能有其他使用者設定，只移除語系設定
+		m.Remove = append(m.Remove, SystemCfg)
+		m.Config = append(m.Config, CfgEdit{Target: UserCfg, Remove: languageKeys})
+	case r.Language != "":
+		if err := checkName("language", r.Language); err != nil {
+			return nil, err
+		}
+		set := []Setting{{Key: "sys_languages", Value: r.Language}, {Key: "g_language", Value: r.Language}}
+		// 遊戲只有英文語音
+		defaults := []Setting{{Key: "g_languageAudio", Value: "english"}}
+		m.Config = append(m.Config,
+			CfgEdit{Target: SystemCfg, Set: set, Defaults: defaults},
+			CfgEdit{Target: UserCfg, Set: set},
+		)
+	}
+	if len(m.Files) == 0 && len(m.Config) == 0 && len(m.Remove) == 0 {
+		return nil, fmt.Errorf("nothing to do: specify a source file, a language or a reset")
+	}
+	return m, nil
+}
+
+// Result 提權拷貝程式的執行結果，寫入 --result 指定的檔案供主程式讀取
+type Result struct {
+	OK         bool     `json:"ok"`
+	Error      string   `json:"error,omitempty"`
+	Channel    string   `json:"channel"`
+	Changed    []string `json:"changed"`             // 寫入或刪除的檔案（相對於版本資料夾）
+	Installed  string   `json:"installed,omitempty"` // 安裝的 global.ini（相對於版本資料夾）
+	Language   string   `json:"language,omitempty"`  // 設定的語系；重設時為空
+	Reset      bool     `json:"reset"`
+	Recovered  bool     `json:"recovered"`          // 執行前還原了上次中斷的安裝
+	SetAside   string   `json:"setAside,omitempty"` // 無法讀取而移到一旁的日誌（相對於版本資料夾）
+	FinishedAt string   `json:"finishedAt"`
+}
+
+// WriteResult 寫入執行結果（先寫暫存檔再替換，讀取端不會讀到一半的內容）
+func WriteResult(path string, r *Result) error {
+	r.FinishedAt = time.Now().Format(time.RFC3339)
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// ReadResult 讀取執行結果
+func ReadResult(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Result{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("invalid copier result: %w", err)
+	}
+	return r, nil
+}
+
+// Err 將失敗的結果轉為錯誤
+func (r *Result) Err() error {
+	if r.OK {
+		return nil
+	}
+	if r.Error == "" {
+		return fmt.Errorf("copier failed")
+	}
+	return fmt.Errorf("%s", r.Error)
+}
+
+// fail 將錯誤記錄於結果並回傳
+func (r *Result) fail(err error) error {
+	r.OK = false
+	r.Error = err.Error()
+	return err
+}
+
+// Run 在版本資料夾直接執行工作（需要寫入權限），回傳執行結果；失敗時結果中也會記錄錯誤
+func Run(channelDir string, req Request) (*Result, error) {
+	res := &Result{Channel: req.Channel, Changed: []string{}, Reset: req.ResetLanguage}
+	m, err := req.Manifest()
+	if err != nil {
+		return res, res.fail(err)
+	}
+	if err := begin(channelDir, res); err != nil {
+		return res, err
+	}
+	if err := commit(channelDir, m, res); err != nil {
+		return res, err
+	}
+	res.Language = req.Language
+	if len(m.Files) > 0 {
+		res.Installed = m.Files[0].Target
+	}
+	return res, nil
+}
@@ -0,0 +1,145 @@
+package installtx
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"zh-tool/pkg/gamebackup"
+)
+
+// uninstallPlan 解除安裝要套用的清單與完成後的清理
+type uninstallPlan struct {
+	manifest  *Manifest
+	restored  []string // 依備份記錄還原的檔案，完成後交由 gamebackup.Forget 刪除備份
+	localeDir string   // 原本沒有此語系時，完成後刪除的語系資料夾
+}
+
+// Uninstall 以交易方式還原版本資料夾中被本工具修改過的檔案（依 gamebackup 的備份記錄），回傳執行結果：
+//   - data/Localization/<locale>/global.ini：有原檔時還原，否則刪除整個語系資料夾
+//   - data/system.cfg、user.cfg：還原為第一次修改前的內容；沒有記錄時只移除 sys_languages / g_language 設定
+//   - 安裝時建立的資料夾（例如 data/Localization）：已清空時刪除
+//
+// 任何一步失敗都依日誌還原所有檔案，完成後才刪除用過的備份與記錄；執行前先還原上次中斷的安裝
+func Uninstall(channelDir, channel, localeName string) (*Result, error) {
+	res := &Result{Channel: channel, Changed: []string{}, Reset: true}
+	localeName = strings.TrimSpace(localeName)
+	if err := checkName("locale", localeName); err != nil {
+		return res, res.fail(err)
+	}
+	if strings.EqualFold(localeName, "english") {
+		return res, res.fail(fmt.Errorf("refusing to remove the game's english localization"))
+	}
+	if err := begin(channelDir, res); err != nil {
+		return res, err
+	}
+	plan, err := planUninstall(channelDir, localeName)
+	if err != nil {
+		return res, res.fail(err)
+	}
+	m := plan.manifest
+	if len(m.Files) > 0 || len(m.Config) > 0 || len(m.Remove) > 0 {
+		if err := commit(channelDir, m, res); err != nil {
+			return res, err
+		}
+	}
+	res.OK = true
+	// 檔案已還原；清理失敗不影響結果（殘留的 .bak 與原檔相同）
+	_ = gamebackup.Forget(channelDir, plan.restored)
+	if plan.localeDir != "" {
+		_ = os.RemoveAll(plan.localeDir)
+	}
+	_ = gamebackup.RemoveEmptyDirs(channelDir)
+	return res, nil
+}
+
+// planUninstall 依備份記錄列出要還原、刪除與修改的檔案
+func planUninstall(channelDir, localeName string) (*uninstallPlan, error) {
+	backups, err := gamebackup.Load(channelDir)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{Version: 1, Files: []FileOp{}, Config: []CfgEdit{}, restoring: true}
+	p := &uninstallPlan{manifest: m}
+
+	// restore 依記錄還原為原檔或刪除；沒有記錄時回傳 false
+	restore := func(rel string) (bool, error) {
+		file := filepath.Join(channelDir, filepath.FromSlash(rel))
+		orig, ok, err := backups.OriginalOf(channelDir, file)
+		if err != nil || !ok {
+			return false, err
+		}
+		if orig.Backup != "" {
+			m.Files = append(m.Files, FileOp{Source: orig.Backup, Target: rel})
+		} else {
+			m.Remove = append(m.Remove, rel)
+		}
+		p.restored = append(p.restored, file)
+		return true, nil
+	}
+
+	localeRel := "data/Localization/" + localeName
+	iniFile := filepath.Join(channelDir, filepath.FromSlash(localeRel), "global.ini")
+	orig, ok, err := backups.OriginalOf(channelDir, iniFile)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		p.restored = append(p.restored, iniFile)
+	}
+	if orig.Backup != "" {
+		m.Files = append(m.Files, FileOp{Source: orig.Backup, Target: localeRel + "/global.ini"})
+	} else {
+		// 原本沒有這個語系：語系資料夾中的檔案經由日誌刪除（失敗時可還原），完成後再移除資料夾
+		localeDir := filepath.Join(channelDir, filepath.FromSlash(localeRel))
+		if st, err := os.Stat(localeDir); err == nil && st.IsDir() {
+			p.localeDir = localeDir
+			if err := filepath.WalkDir(localeDir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(channelDir, path)
+				if err != nil {
+					return err
+				}
+				if rel, err := checkTarget(filepath.ToSlash(rel)); err == nil {
+					m.Remove = append(m.Remove, rel)
+				}
+				return nil
+			}); err != nil {
+				return nil, fmt.Errorf("list locale folder failed: %w", err)
+			}
+		}
+	}
+
+	for _, cfg := range []string{SystemCfg, UserCfg} {
+		ok, err := restore(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			continue
+		}
+		// 沒有備份：只移除本工具寫入的語系設定；只剩語音設定時刪除檔案
+		current, err := os.ReadFile(filepath.Join(channelDir, filepath.FromSlash(cfg)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		edit := CfgEdit{Target: cfg, Remove: languageKeys}
+		edited := edit.Edit(current)
+		switch {
+		case bytes.Equal(edited, current):
+		case strings.TrimSpace(strings.ReplaceAll(string(edited), "g_languageAudio=english", "")) == "":
+			m.Remove = append(m.Remove, cfg)
+		default:
+			m.Config = append(m.Config, edit)
+		}
+	}
+	return p, nil
+}
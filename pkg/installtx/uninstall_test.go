@@ -0,0 +1,146 @@
+package installtx
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"zh-tool/pkg/gamebackup"
+)
+
+func TestUninstallRestoresOriginals(t *testing.T) {
+	dir, source := newChannel(t)
+	if _, err := Run(dir, Request{Channel: "LIVE", Locale: "chinese", Source: source, Language: "chinese"}); err != nil {
+		t.Fatal(err)
+	}
+	// 安裝建立的資料夾記錄於備份記錄
+	backups, err := gamebackup.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"data/Localization", "data/Localization/chinese"}; !reflect.DeepEqual(backups.Dirs, want) {
+		t.Errorf("recorded dirs = %v, want %v", backups.Dirs, want)
+	}
+
+	res, err := Uninstall(dir, "LIVE", "chinese")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.OK || !res.Reset {
+		t.Errorf("result = %+v", res)
+	}
+	// 原本沒有的語系資料夾（含安裝時建立的 Localization）與 system.cfg 移除，user.cfg 還原為原檔
+	assertMissing(t, filepath.Join(dir, "data", "Localization"))
+	if _, err := os.Stat(filepath.Join(dir, "data")); err != nil {
+		t.Errorf("pre-existing data folder removed: %v", err)
+	}
+	assertMissing(t, filepath.Join(dir, SystemCfg))
+	if got := readFile(t, filepath.Join(dir, UserCfg)); got != "r_width=1920\r\nr_height=1080\r\n" {
+		t.Errorf("user.cfg = %q", got)
+	}
+	// 用過的備份與記錄一併刪除
+	assertMissing(t, filepath.Join(dir, UserCfg)+gamebackup.BackupSuffix)
+	assertMissing(t, filepath.Join(dir, gamebackup.ManifestName))
+	assertClean(t, dir)
+}
+
+func TestUninstallKeepsCreatedDirsInUse(t *testing.T) {
+	dir, source := newChannel(t)
+	if _, err := Run(dir, Request{Channel: "LIVE", Locale: "chinese", Source: source}); err != nil {
+		t.Fatal(err)
+	}
+	// 使用者之後在同一個 Localization 下放了其他語系
+	other := writeFile(t, filepath.Join(dir, "data", "Localization", "japanese", "global.ini"), "ui_Ok=OK\n")
+
+	if _, err := Uninstall(dir, "LIVE", "chinese"); err != nil {
+		t.Fatal(err)
+	}
+	assertMissing(t, filepath.Join(dir, "data", "Localization", "chinese"))
+	if _, err := os.Stat(other); err != nil {
+		t.Fatalf("other locale removed: %v", err)
+	}
+	// 仍在使用的資料夾保留記錄，之後清空時可再刪除
+	backups, err := gamebackup.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(backups.Dirs, []string{"data/Localization"}) {
+		t.Errorf("recorded dirs = %v", backups.Dirs)
+	}
+}
+
+func TestUninstallRestoresReplacedLocale(t *testing.T) {
+	dir, source := newChannel(t)
+	ini := writeFile(t, filepath.Join(dir, filepath.FromSlash(localeTarget)), "community translation")
+	if _, err := Run(dir, Request{Channel: "LIVE", Locale: "chinese", Source: source}); err != nil {
+		t.Fatal(err)
+	}
+	if readFile(t, ini) == "community translation" {
+		t.Fatal("install did not replace global.ini")
+	}
+
+	if _, err := Uninstall(dir, "LIVE", "chinese"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, ini); got != "community translation" {
+		t.Errorf("global.ini = %q, want the original", got)
+	}
+	assertMissing(t, ini+gamebackup.BackupSuffix)
+}
+
+func TestUninstallWithoutRecordStripsLanguage(t *testing.T) {
+	dir, _ := newChannel(t)
+	writeFile(t, filepath.Join(dir, UserCfg), "r_width=1920\nsys_languages=chinese\ng_language=chinese\n")
+	writeFile(t, filepath.Join(dir, SystemCfg), "sys_languages=chinese\ng_language=chinese\ng_languageAudio=english\n")
+	writeFile(t, filepath.Join(dir, "data", "Localization", "chinese", "global.ini"), "ui_Ok=確定\n")
+
+	if _, err := Uninstall(dir, "LIVE", "chinese"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dir, UserCfg)); got != "r_width=1920\n" {
+		t.Errorf("user.cfg = %q", got)
+	}
+	// 只剩語音設定的 system.cfg 由本工具建立，刪除
+	assertMissing(t, filepath.Join(dir, SystemCfg))
+	assertMissing(t, filepath.Join(dir, "data", "Localization", "chinese"))
+	assertClean(t, dir)
+}
+
+func TestUninstallMissingBackupChangesNothing(t *testing.T) {
+	dir, source := newChannel(t)
+	if _, err := Run(dir, Request{Channel: "LIVE", Locale: "chinese", Source: source, Language: "chinese"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, UserCfg) + gamebackup.BackupSuffix); err != nil {
+		t.Fatal(err)
+	}
+	installed := readFile(t, filepath.Join(dir, UserCfg))
+
+	res, err := Uninstall(dir, "LIVE", "chinese")
+	if err == nil {
+		t.Fatal("expected an error for the missing backup")
+	}
+	if res.OK {
+		t.Errorf("result = %+v", res)
+	}
+	if _, err := os.Stat(filepath.Join(dir, localeTarget)); err != nil {
+		t.Errorf("global.ini removed by a failed uninstall: %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, UserCfg)); got != installed {
+		t.Errorf("user.cfg changed by a failed uninstall: %q", got)
+	}
+}
+
+func TestUninstallRejectsEnglish(t *testing.T) {
+	dir, _ := newChannel(t)
+	english := writeFile(t, filepath.Join(dir, "data", "Localization", "english", "global.ini"), "ui_Ok=OK\n")
+	for _, name := range []string{"english", "ENGLISH", "../english", ""} {
+		if _, err := Uninstall(dir, "LIVE", name); err == nil {
+			t.Errorf("Uninstall(%q) succeeded", name)
+		}
+	}
+	if _, err := os.Stat(english); err != nil {
+		t.Errorf("english global.ini removed: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package localestore
+
+import (
+	"path/filepath"
+
+	"zh-tool/pkg/ini"
+)
+
+// fingerprintFileName 語系資料夾中記錄英文原文指紋的檔案：每個鍵翻譯時所依據的英文原文
+const fingerprintFileName = "source-fingerprints.json"
+
+// FingerprintPath 回傳語系的英文原文指紋資料庫路徑：<root>/<locale>/source-fingerprints.json
+func (s *Store) FingerprintPath(localeName string) string {
+	return filepath.Join(s.Root, localeName, fingerprintFileName)
+}
+
+// Values 回傳語系目前生效的譯文（基底疊加啟用的圖層）；語系不存在或無法組合時回傳空對照表
+func (s *Store) Values(localeName string) map[string]string {
+	values := map[string]string{}
+	doc, err := s.Compose(localeName)
+	if err != nil {
+		return values
+	}
+	for _, kv := range doc.Entries() {
+		if _, ok := values[kv.Key]; !ok {
+			values[kv.Key] = kv.Value
+		}
+	}
+	return values
+}
+
+// RecordFingerprints 記錄語系中鍵所對應的英文原文指紋
+// keys 為空時只替尚無記錄的鍵建立基準；指定 keys 時覆寫這些鍵
+func (s *Store) RecordFingerprints(localeName, englishPath string, keys []string) (int, error) {
+	iniPath, err := s.INIPath(localeName)
+	if err != nil {
+		return 0, err
+	}
+	return ini.RecordFingerprints(s.FingerprintPath(localeName), iniPath, englishPath, keys)
+}
+
+// RecordChanges 比對修改前的譯文（見 Values）與目前的譯文，替值有變動或新增的鍵記錄英文原文指紋
+func (s *Store) RecordChanges(localeName string, before map[string]string, englishPath string) (int, error) {
+	keys := ini.ChangedKeys(before, s.Values(localeName))
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	return s.RecordFingerprints(localeName, englishPath, keys)
+}
+
+// CompareStale 列出語系檔中英文原文在翻譯後已變動的鍵
+func (s *Store) CompareStale(localeName, englishPath string) (ini.StaleCompareResult, error) {
+	iniPath, err := s.INIPath(localeName)
+	if err != nil {
+		return ini.StaleCompareResult{}, err
+	}
+	return ini.CompareStale(iniPath, englishPath, s.FingerprintPath(localeName))
+}
@@ -0,0 +1,70 @@
+package localestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"zh-tool/pkg/ini"
+)
+
+func TestRecordChangesMarksEditedKeysCurrent(t *testing.T) {
+	dir := t.TempDir()
+	s := New(filepath.Join(dir, "Localization"))
+	if _, err := s.Import("chinese", writeFile(t, filepath.Join(dir, "base.ini"), "a=甲\nb=乙\n")); err != nil {
+		t.Fatal(err)
+	}
+	oldEn := writeFile(t, filepath.Join(dir, "old-english.ini"), "a=A\nb=B\n")
+	newEn := writeFile(t, filepath.Join(dir, "new-english.ini"), "a=A2\nb=B2\n")
+
+	// 語系還沒有指紋檔
+	if _, err := os.Stat(s.FingerprintPath("chinese")); !os.IsNotExist(err) {
+		t.Fatalf("fingerprint file exists before recording: %v", err)
+	}
+	r, err := s.CompareStale("chinese", newEn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Untracked) != 2 {
+		t.Errorf("untracked = %v, want a and b", r.Untracked)
+	}
+
+	if n, err := s.RecordFingerprints("chinese", oldEn, nil); err != nil || n != 2 {
+		t.Fatalf("RecordFingerprints = %d, %v", n, err)
+	}
+
+	// 依新英文修改 a：只有 a 記錄新的指紋，b 仍過時
+	before := s.Values("chinese")
+	if err := s.SetOverrides("chinese", []ini.KeyValue{{Key: "a", Value: "甲2"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.RecordChanges("chinese", before, newEn); err != nil || n != 1 {
+		t.Fatalf("RecordChanges = %d, %v", n, err)
+	}
+	r, err = s.CompareStale("chinese", newEn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Stale) != 1 || r.Stale[0].Key != "b" || r.Current != 1 {
+		t.Errorf("after editing a: %+v", r)
+	}
+
+	// 沒有變動時不寫入
+	if n, err := s.RecordChanges("chinese", s.Values("chinese"), newEn); err != nil || n != 0 {
+		t.Errorf("RecordChanges without edits = %d, %v", n, err)
+	}
+}
+
+func TestFingerprintsOfMissingLocale(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "Localization"))
+	english := writeFile(t, filepath.Join(t.TempDir(), "english.ini"), "a=A\n")
+	if _, err := s.CompareStale("chinese", english); err == nil {
+		t.Error("CompareStale succeeded without a locale file")
+	}
+	if _, err := s.RecordFingerprints("chinese", english, nil); err == nil {
+		t.Error("RecordFingerprints succeeded without a locale file")
+	}
+	if len(s.Values("chinese")) != 0 {
+		t.Error("Values of a missing locale not empty")
+	}
+}
@@ -0,0 +1,485 @@
+package localestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"zh-tool/pkg/ini"
+)
+
+// 覆寫 global.ini 或圖層前先保留快照：基底、layers.json 與 layers/ 中的圖層檔各自存為
+// <root>/<locale>/history/<hash>.ini，清單記錄於 history/index.json。內容相同的檔案共用同一個快照檔。
+const (
+	historyDirName   = "history"
+	historyIndexName = "index.json"
+	snapshotIDLayout = "20060102-150405.000"
+)
+
+// Retention 快照保留原則：超過 MaxCount 個或早於 MaxAge 的快照會被刪除（最新一個一律保留）
+type Retention struct {
+	MaxCount int
+	MaxAge   time.Duration
+}
+
+// DefaultRetention 預設保留最近 30 個、90 天內的快照
+var DefaultRetention = Retention{MaxCount: 30, MaxAge: 90 * 24 * time.Hour}
+
+// Snapshot 語系檔的單一快照
+type Snapshot struct {
+	ID        string          `json:"id"`
+	Hash      string          `json:"hash"` // 基底 global.ini
+	CreatedAt string          `json:"createdAt"`
+	Reason    string          `json:"reason"` // import / edit / layer / restore / manual
+	Size      int64           `json:"size"`
+	Layers    []SnapshotLayer `json:"layers,omitempty"`    // 圖層檔案
+	LayerList string          `json:"layerList,omitempty"` // layers.json；沒有圖層清單時為空
+	// WithLayers 快照包含圖層；較早的快照只有基底，還原時不變動圖層
+	WithLayers bool `json:"withLayers"`
+}
+
+// SnapshotLayer 快照中的單一圖層檔案
+type SnapshotLayer struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+}
+
+// hashes 回傳快照參照的所有快照檔
+func (sn Snapshot) hashes() []string {
+	result := []string{sn.Hash}
+	if sn.LayerList != "" {
+		result = append(result, sn.LayerList)
+	}
+	for _, l := range sn.Layers {
+		result = append(result, l.Hash)
+	}
+	return result
+}
+
+// sameState 判斷兩個快照的內容是否相同
+func (sn Snapshot) sameState(o Snapshot) bool {
+	if sn.Hash != o.Hash || sn.LayerList != o.LayerList || sn.WithLayers != o.WithLayers || len(sn.Layers) != len(o.Layers) {
+		return false
+	}
+	for i := range sn.Layers {
+		if sn.Layers[i] != o.Layers[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// snapshotIndex 快照清單（依建立時間由舊到新）
+type snapshotIndex struct {
+	Version   int        `json:"version"`
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// ChangedEntry 快照與目前檔案值不同的鍵
+type ChangedEntry struct {
+	Key      string `json:"key"`
+	Snapshot string `json:"snapshot"`
+	Current  string `json:"current"`
+}
+
+// SnapshotDiff 快照與目前檔案的差異
+type SnapshotDiff struct {
+	Snapshot Snapshot       `json:"snapshot"`
+	Added    []ini.KeyValue `json:"added"`   // 目前檔案新增的鍵
+	Removed  []ini.KeyValue `json:"removed"` // 目前檔案已刪除的鍵（值為快照中的值）
+	Changed  []ChangedEntry `json:"changed"`
+}
+
+func (s *Store) historyDir(localeName string) string {
+	return filepath.Join(s.Root, localeName, historyDirName)
+}
+
+func (s *Store) blobPath(localeName, hash string) string {
+	return filepath.Join(s.historyDir(localeName), hash[:16]+".ini")
+}
+
+func (s *Store) retention() Retention {
+	if s.Retention.MaxCount <= 0 && s.Retention.MaxAge <= 0 {
+		return DefaultRetention
+	}
+	return s.Retention
+}
+
+func (s *Store) loadIndex(localeName string) (*snapshotIndex, error) {
+	idx := &snapshotIndex{Version: 1}
+	data, err := os.ReadFile(filepath.Join(s.historyDir(localeName), historyIndexName))
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("invalid snapshot index %s: %w", localeName, err)
+	}
+	return idx, nil
+}
+
+func (s *Store) saveIndex(localeName string, idx *snapshotIndex) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	p := filepath.Join(s.historyDir(localeName), historyIndexName)
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write snapshot index failed: %w", err)
+	}
+	return os.Rename(tmp, p)
+}
+
+// LocaleOf 判斷路徑是否為儲存區中某個語系的 global.ini，是則回傳語系名稱
+func (s *Store) LocaleOf(filePath string) (string, bool) {
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", false
+	}
+	root, err := filepath.Abs(s.Root)
+	if err != nil {
+		return "", false
+	}
+	localeDir := filepath.Dir(abs)
+	if !strings.EqualFold(filepath.Base(abs), INIFileName) || !strings.EqualFold(filepath.Dir(localeDir), root) {
+		return "", false
+	}
+	return filepath.Base(localeDir), true
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// layerFiles 讀取 layers/ 中的圖層檔（依名稱排序）
+func (s *Store) layerFiles(localeName string) (map[string][]byte, []string, error) {
+	dir := filepath.Join(s.Root, localeName, layersDirName)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return map[string][]byte{}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	files := map[string][]byte{}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".ini") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		files[name] = data
+		names = append(names, name)
+	}
+	return files, names, nil
+}
+
+// Snapshot 保留語系檔與圖層目前的內容；基底不存在或與最新快照相同時回傳 nil
+func (s *Store) Snapshot(localeName, reason string) (*Snapshot, error) {
+	data, err := os.ReadFile(s.Path(localeName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	idx, err := s.loadIndex(localeName)
+	if err != nil {
+		return nil, err
+	}
+	snap := Snapshot{Hash: contentHash(data), Reason: reason, Size: int64(len(data)), WithLayers: true}
+	blobs := map[string][]byte{snap.Hash: data}
+	list, err := os.ReadFile(s.layersPath(localeName))
+	switch {
+	case err == nil:
+		snap.LayerList = contentHash(list)
+		blobs[snap.LayerList] = list
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+	files, names, err := s.layerFiles(localeName)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		l := SnapshotLayer{Name: name, Hash: contentHash(files[name])}
+		snap.Layers = append(snap.Layers, l)
+		blobs[l.Hash] = files[name]
+	}
+	if n := len(idx.Snapshots); n > 0 && idx.Snapshots[n-1].sameState(snap) {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(s.historyDir(localeName), 0755); err != nil {
+		return nil, err
+	}
+	for hash, content := range blobs {
+		blob := s.blobPath(localeName, hash)
+		if _, err := os.Stat(blob); os.IsNotExist(err) {
+			if err := os.WriteFile(blob, content, 0644); err != nil {
+				return nil, fmt.Errorf("write snapshot failed: %w", err)
+			}
+		}
+	}
+	now := time.Now()
+	snap.ID = uniqueSnapshotID(idx, now)
+	snap.CreatedAt = now.Format(time.RFC3339)
+	idx.Snapshots = append(idx.Snapshots, snap)
+	s.prune(localeName, idx, now)
+	if err := s.saveIndex(localeName, idx); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// uniqueSnapshotID 以時間產生快照編號，同一毫秒內重複時加上序號
+func uniqueSnapshotID(idx *snapshotIndex, now time.Time) string {
+	base := now.Format(snapshotIDLayout)
+	id := base
+	for n := 2; ; n++ {
+		taken := false
+		for _, sn := range idx.Snapshots {
+			if sn.ID == id {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// prune 依保留原則刪除舊快照，並移除不再被參照的快照檔
+func (s *Store) prune(localeName string, idx *snapshotIndex, now time.Time) {
+	r := s.retention()
+	keep := idx.Snapshots[:0]
+	var dropped []Snapshot
+	for i, sn := range idx.Snapshots {
+		fromEnd := len(idx.Snapshots) - i
+		tooMany := r.MaxCount > 0 && fromEnd > r.MaxCount
+		tooOld := false
+		if t, err := time.Parse(time.RFC3339, sn.CreatedAt); err == nil && r.MaxAge > 0 {
+			tooOld = now.Sub(t) > r.MaxAge
+		}
+		if fromEnd > 1 && (tooMany || tooOld) {
+			dropped = append(dropped, sn)
+			continue
+		}
+		keep = append(keep, sn)
+	}
+	idx.Snapshots = keep
+	used := map[string]bool{}
+	for _, sn := range keep {
+		for _, h := range sn.hashes() {
+			used[h] = true
+		}
+	}
+	for _, d := range dropped {
+		for _, h := range d.hashes() {
+			if !used[h] {
+				_ = os.Remove(s.blobPath(localeName, h))
+			}
+		}
+	}
+}
+
+// Snapshots 列出語系的快照（新到舊）
+func (s *Store) Snapshots(localeName string) ([]Snapshot, error) {
+	idx, err := s.loadIndex(localeName)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Snapshot, len(idx.Snapshots))
+	copy(result, idx.Snapshots)
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
+
+// findSnapshot 依編號尋找快照並回傳快照檔路徑
+func (s *Store) findSnapshot(localeName, id string) (Snapshot, string, error) {
+	idx, err := s.loadIndex(localeName)
+	if err != nil {
+		return Snapshot{}, "", err
+	}
+	for _, sn := range idx.Snapshots {
+		if sn.ID == id {
+			for _, h := range sn.hashes() {
+				if _, err := os.Stat(s.blobPath(localeName, h)); err != nil {
+					return sn, "", fmt.Errorf("snapshot file missing: %s", id)
+				}
+			}
+			return sn, s.blobPath(localeName, sn.Hash), nil
+		}
+	}
+	return Snapshot{}, "", fmt.Errorf("snapshot not found: %s", id)
+}
+
+// SnapshotPath 回傳快照中基底 global.ini 的路徑（供檢視或匯出）
+func (s *Store) SnapshotPath(localeName, id string) (string, error) {
+	_, p, err := s.findSnapshot(localeName, id)
+	return p, err
+}
+
+// DiffSnapshot 比對快照與目前的語系檔（皆為基底疊加啟用圖層後的結果）
+func (s *Store) DiffSnapshot(localeName, id string) (SnapshotDiff, error) {
+	diff := SnapshotDiff{Added: []ini.KeyValue{}, Removed: []ini.KeyValue{}, Changed: []ChangedEntry{}}
+	sn, _, err := s.findSnapshot(localeName, id)
+	if err != nil {
+		return diff, err
+	}
+	diff.Snapshot = sn
+	oldDoc, err := s.composeSnapshot(localeName, sn)
+	if err != nil {
+		return diff, fmt.Errorf("read snapshot failed: %w", err)
+	}
+	curDoc, err := s.Compose(localeName)
+	if err != nil {
+		return diff, fmt.Errorf("read current file failed: %w", err)
+	}
+	old, oldOrder := valueMap(oldDoc)
+	cur, curOrder := valueMap(curDoc)
+	for _, k := range curOrder {
+		ov, ok := old[k]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, ini.KeyValue{Key: k, Value: cur[k]})
+		case ov != cur[k]:
+			diff.Changed = append(diff.Changed, ChangedEntry{Key: k, Snapshot: ov, Current: cur[k]})
+		}
+	}
+	for _, k := range oldOrder {
+		if _, ok := cur[k]; !ok {
+			diff.Removed = append(diff.Removed, ini.KeyValue{Key: k, Value: old[k]})
+		}
+	}
+	return diff, nil
+}
+
+// valueMap 回傳每個鍵第一次出現的值與鍵的順序
+func valueMap(doc *ini.Document) (map[string]string, []string) {
+	values := map[string]string{}
+	var order []string
+	for _, kv := range doc.Entries() {
+		if _, ok := values[kv.Key]; !ok {
+			values[kv.Key] = kv.Value
+			order = append(order, kv.Key)
+		}
+	}
+	return values, order
+}
+
+// composeSnapshot 以快照中的基底與圖層組合語系內容（與 Compose 相同）
+func (s *Store) composeSnapshot(localeName string, sn Snapshot) (*ini.Document, error) {
+	doc, err := ini.Load(s.blobPath(localeName, sn.Hash))
+	if err != nil {
+		return nil, err
+	}
+	if !sn.WithLayers || sn.LayerList == "" {
+		return doc, nil
+	}
+	ls, err := readLayerStack(s.blobPath(localeName, sn.LayerList))
+	if err != nil {
+		return nil, err
+	}
+	paths := map[string]string{}
+	for _, l := range sn.Layers {
+		paths[strings.ToLower(l.Name)] = s.blobPath(localeName, l.Hash)
+	}
+	err = applyLayers(doc, ls, func(name string) string {
+		return paths[strings.ToLower(name)]
+	})
+	return doc, err
+}
+
+// RestoreSnapshot 以快照取代目前的語系檔與圖層；取代前先替目前內容建立快照，因此還原本身也可復原
+// 只有基底的舊快照不變動圖層
+func (s *Store) RestoreSnapshot(localeName, id string) error {
+	sn, p, err := s.findSnapshot(localeName, id)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	if _, err := s.Snapshot(localeName, "restore"); err != nil {
+		return err
+	}
+	if err := replaceFile(s.Path(localeName), data); err != nil {
+		return fmt.Errorf("restore snapshot failed: %w", err)
+	}
+	if !sn.WithLayers {
+		return nil
+	}
+	if err := s.restoreLayers(localeName, sn); err != nil {
+		return fmt.Errorf("restore layers failed: %w", err)
+	}
+	return nil
+}
+
+// restoreLayers 使 layers.json 與 layers/ 中的圖層檔與快照相同
+func (s *Store) restoreLayers(localeName string, sn Snapshot) error {
+	keep := map[string]bool{}
+	for _, l := range sn.Layers {
+		keep[l.Name] = true
+		data, err := os.ReadFile(s.blobPath(localeName, l.Hash))
+		if err != nil {
+			return err
+		}
+		dest := s.LayerPath(localeName, l.Name)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := replaceFile(dest, data); err != nil {
+			return err
+		}
+	}
+	_, names, err := s.layerFiles(localeName)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if !keep[name] {
+			if err := os.Remove(s.LayerPath(localeName, name)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	if sn.LayerList == "" {
+		if err := os.Remove(s.layersPath(localeName)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	list, err := os.ReadFile(s.blobPath(localeName, sn.LayerList))
+	if err != nil {
+		return err
+	}
+	return replaceFile(s.layersPath(localeName), list)
+}
+
+// replaceFile 先寫入暫存檔再替換
+func replaceFile(dest string, data []byte) error {
+	tmp := dest + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
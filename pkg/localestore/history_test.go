@@ -0,0 +1,106 @@
+package localestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"zh-tool/pkg/ini"
+)
+
+func TestRestoreSnapshotRestoresOverrides(t *testing.T) {
+	dir := t.TempDir()
+	s := New(filepath.Join(dir, "Localization"))
+	if _, err := s.Import("chinese", writeFile(t, filepath.Join(dir, "base.ini"), "greeting=你好\r\nfarewell=再見\r\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetOverrides("chinese", []ini.KeyValue{{Key: "greeting", Value: "哈囉"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetOverrides("chinese", []ini.KeyValue{{Key: "greeting", Value: "嗨"}, {Key: "farewell", Value: "掰掰"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// 最新的快照為第二次修改前的狀態：只有第一次的覆寫
+	snaps, err := s.Snapshots("chinese")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) < 2 {
+		t.Fatalf("snapshots = %+v, want one per override save", snaps)
+	}
+	diff, err := s.DiffSnapshot("chinese", snaps[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Changed) != 2 {
+		t.Errorf("diff = %+v, want greeting and farewell changed", diff)
+	}
+
+	if err := s.RestoreSnapshot("chinese", snaps[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	got := composedValues(t, s, "chinese")
+	if got["greeting"] != "哈囉" || got["farewell"] != "再見" {
+		t.Errorf("after restore: %v, want the first override only", got)
+	}
+	// 基底不受影響
+	if base, _ := os.ReadFile(s.Path("chinese")); string(base) != "greeting=你好\r\nfarewell=再見\r\n" {
+		t.Errorf("base = %q", base)
+	}
+
+	// 還原到沒有任何圖層的狀態時移除覆寫圖層
+	oldest := snaps[len(snaps)-1]
+	if err := s.RestoreSnapshot("chinese", oldest.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := composedValues(t, s, "chinese")["greeting"]; got != "你好" {
+		t.Errorf("greeting = %q after restoring the first snapshot", got)
+	}
+	if _, err := os.Stat(s.LayerPath("chinese", OverrideLayerName)); !os.IsNotExist(err) {
+		t.Error("override layer still present")
+	}
+	if s.HasLayers("chinese") {
+		t.Error("layer list not restored")
+	}
+}
+
+func TestLayerChangesTakeSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	s := New(filepath.Join(dir, "Localization"))
+	if _, err := s.Import("chinese", writeFile(t, filepath.Join(dir, "base.ini"), "a=基底\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ImportLayer("chinese", "team", LayerPatch, writeFile(t, filepath.Join(dir, "patch.ini"), "a=修正\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetLayerEnabled("chinese", "team", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteLayer("chinese", "team"); err != nil {
+		t.Fatal(err)
+	}
+	snaps, err := s.Snapshots("chinese")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 匯入、停用、刪除前各一個
+	if len(snaps) != 3 {
+		t.Fatalf("snapshots = %+v, want 3", snaps)
+	}
+	// 還原刪除前的狀態：圖層回來但仍為停用
+	if err := s.RestoreSnapshot("chinese", snaps[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	ls, err := s.Layers("chinese")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls.Layers) != 1 || ls.Layers[0].Enabled {
+		t.Errorf("layers = %+v, want team disabled", ls.Layers)
+	}
+	if _, err := os.Stat(s.LayerPath("chinese", "team")); err != nil {
+		t.Errorf("layer file not restored: %v", err)
+	}
+}
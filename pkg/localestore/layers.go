@@ -0,0 +1,356 @@
+package localestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"zh-tool/pkg/ini"
+)
+
+// 語系由多個圖層組成：global.ini 為上游基底，其上依序疊加 layers/ 中的圖層，
+// 後面的圖層覆寫前面的值。更新基底（匯入新版官方譯文）時圖層保持不變。
+const (
+	layersDirName  = "layers"
+	layersFileName = "layers.json"
+)
+
+// 圖層種類
+const (
+	LayerPatch    = "patch"    // 團隊修正
+	LayerOverride = "override" // 個人覆寫，一律排在團隊修正之後
+)
+
+// OverrideLayerName 編輯器儲存個人修改時使用的圖層
+const OverrideLayerName = "user-overrides"
+
+// Layer 疊加在基底之上的單一圖層（layers/<name>.ini）
+type Layer struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Enabled   bool   `json:"enabled"`
+	Source    string `json:"source"` // 匯入來源，僅供顯示
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// LayerStack 語系的圖層清單（依套用順序，不含基底）
+type LayerStack struct {
+	Version int     `json:"version"`
+	Layers  []Layer `json:"layers"`
+}
+
+// find 回傳圖層的索引，不存在時回傳 -1
+func (ls *LayerStack) find(layerName string) int {
+	for i, l := range ls.Layers {
+		if strings.EqualFold(l.Name, layerName) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortKinds 維持團隊修正在前、個人覆寫在後，同種類保留原本順序
+func (ls *LayerStack) sortKinds() {
+	var patches, overrides []Layer
+	for _, l := range ls.Layers {
+		if l.Kind == LayerOverride {
+			overrides = append(overrides, l)
+		} else {
+			patches = append(patches, l)
+		}
+	}
+	ls.Layers = append(patches, overrides...)
+}
+
+// validLayerName 圖層名稱不得包含路徑
+func validLayerName(layerName string) error {
+	n := strings.TrimSpace(layerName)
+	if n == "" || n != filepath.Base(n) || n == "." || n == ".." || strings.ContainsAny(n, `/\:`) {
+		return fmt.Errorf("invalid layer name: %s", layerName)
+	}
+	return nil
+}
+
+// LayerPath 回傳圖層檔案路徑：<root>/<locale>/layers/<layer>.ini
+func (s *Store) LayerPath(localeName, layerName string) string {
+	return filepath.Join(s.Root, localeName, layersDirName, layerName+".ini")
+}
+
+func (s *Store) layersPath(localeName string) string {
+	return filepath.Join(s.Root, localeName, layersFileName)
+}
+
+// Layers 讀取語系的圖層清單；尚未建立時回傳空清單
+func (s *Store) Layers(localeName string) (LayerStack, error) {
+	ls, err := readLayerStack(s.layersPath(localeName))
+	if err != nil && !os.IsNotExist(err) {
+		return ls, fmt.Errorf("invalid layer list %s: %w", localeName, err)
+	}
+	return ls, nil
+}
+
+// readLayerStack 讀取圖層清單檔案；無法讀取時回傳空清單與錯誤
+func readLayerStack(path string) (LayerStack, error) {
+	ls := LayerStack{Version: 1, Layers: []Layer{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ls, err
+	}
+	if err := json.Unmarshal(data, &ls); err != nil {
+		return LayerStack{Version: 1, Layers: []Layer{}}, err
+	}
+	if ls.Layers == nil {
+		ls.Layers = []Layer{}
+	}
+	return ls, nil
+}
+
+func (s *Store) saveLayers(localeName string, ls LayerStack) error {
+	ls.sortKinds()
+	data, err := json.MarshalIndent(ls, "", "  ")
+	if err != nil {
+		return err
+	}
+	p := s.layersPath(localeName)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write layer list failed: %w", err)
+	}
+	return os.Rename(tmp, p)
+}
+
+// snapshotLayers 修改圖層前保留快照（見 Snapshot），使用者的修改也能還原
+func (s *Store) snapshotLayers(localeName, reason string) error {
+	if _, err := s.Snapshot(localeName, reason); err != nil {
+		return fmt.Errorf("snapshot before write failed: %w", err)
+	}
+	return nil
+}
+
+// putLayer 新增或更新圖層資訊；既有圖層沿用記錄的名稱，新圖層預設啟用
+func (s *Store) putLayer(localeName string, layer Layer) (Layer, error) {
+	ls, err := s.Layers(localeName)
+	if err != nil {
+		return layer, err
+	}
+	layer.UpdatedAt = time.Now().Format(time.RFC3339)
+	if i := ls.find(layer.Name); i >= 0 {
+		layer.Name = ls.Layers[i].Name
+		layer.Enabled = ls.Layers[i].Enabled
+		if layer.Source == "" {
+			layer.Source = ls.Layers[i].Source
+		}
+		ls.Layers[i] = layer
+	} else {
+		layer.Enabled = true
+		ls.Layers = append(ls.Layers, layer)
+	}
+	return layer, s.saveLayers(localeName, ls)
+}
+
+// ImportLayer 匯入圖層檔案（UTF-16 / Big5 / GBK 會轉為 UTF-8）；同名圖層會被取代，啟用狀態不變
+func (s *Store) ImportLayer(localeName, layerName, kind, sourceFilePath string) (Layer, error) {
+	if strings.TrimSpace(localeName) == "" {
+		return Layer{}, fmt.Errorf("locale name is required")
+	}
+	if err := validLayerName(layerName); err != nil {
+		return Layer{}, err
+	}
+	// 圖層名稱不分大小寫：重新匯入既有圖層時沿用記錄的名稱，避免舊檔案留在 layers/ 中
+	layerName = strings.TrimSpace(layerName)
+	ls, err := s.Layers(localeName)
+	if err != nil {
+		return Layer{}, err
+	}
+	if i := ls.find(layerName); i >= 0 {
+		layerName = ls.Layers[i].Name
+	}
+	switch kind {
+	case "":
+		kind = LayerPatch
+	case LayerPatch, LayerOverride:
+	default:
+		return Layer{}, fmt.Errorf("invalid layer kind: %s", kind)
+	}
+	data, _, err := ini.ReadFileAsUTF8(sourceFilePath)
+	if err != nil {
+		return Layer{}, fmt.Errorf("import %s failed: %w", filepath.Base(sourceFilePath), err)
+	}
+	if err := s.snapshotLayers(localeName, "layer"); err != nil {
+		return Layer{}, err
+	}
+	dest := s.LayerPath(localeName, layerName)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return Layer{}, err
+	}
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return Layer{}, err
+	}
+	return s.putLayer(localeName, Layer{Name: layerName, Kind: kind, Source: sourceFilePath})
+}
+
+// SetLayerEnabled 啟用或停用圖層
+func (s *Store) SetLayerEnabled(localeName, layerName string, enabled bool) error {
+	ls, err := s.Layers(localeName)
+	if err != nil {
+		return err
+	}
+	i := ls.find(layerName)
+	if i < 0 {
+		return fmt.Errorf("layer not found: %s", layerName)
+	}
+	if ls.Layers[i].Enabled == enabled {
+		return nil
+	}
+	if err := s.snapshotLayers(localeName, "layer"); err != nil {
+		return err
+	}
+	ls.Layers[i].Enabled = enabled
+	return s.saveLayers(localeName, ls)
+}
+
+// ReorderLayers 依 names 重新排列圖層；未列出的圖層保持原順序放在最後
+// 個人覆寫圖層一律排在團隊修正之後
+func (s *Store) ReorderLayers(localeName string, names []string) error {
+	ls, err := s.Layers(localeName)
+	if err != nil {
+		return err
+	}
+	ordered := make([]Layer, 0, len(ls.Layers))
+	used := make([]bool, len(ls.Layers))
+	for _, n := range names {
+		i := ls.find(n)
+		if i < 0 {
+			return fmt.Errorf("layer not found: %s", n)
+		}
+		if !used[i] {
+			used[i] = true
+			ordered = append(ordered, ls.Layers[i])
+		}
+	}
+	for i, l := range ls.Layers {
+		if !used[i] {
+			ordered = append(ordered, l)
+		}
+	}
+	if err := s.snapshotLayers(localeName, "layer"); err != nil {
+		return err
+	}
+	ls.Layers = ordered
+	return s.saveLayers(localeName, ls)
+}
+
+// DeleteLayer 刪除圖層與其檔案
+func (s *Store) DeleteLayer(localeName, layerName string) error {
+	ls, err := s.Layers(localeName)
+	if err != nil {
+		return err
+	}
+	i := ls.find(layerName)
+	if i < 0 {
+		return fmt.Errorf("layer not found: %s", layerName)
+	}
+	if err := s.snapshotLayers(localeName, "layer"); err != nil {
+		return err
+	}
+	if err := os.Remove(s.LayerPath(localeName, ls.Layers[i].Name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	ls.Layers = append(ls.Layers[:i], ls.Layers[i+1:]...)
+	return s.saveLayers(localeName, ls)
+}
+
+// Overrides 讀取個人覆寫圖層的項目
+func (s *Store) Overrides(localeName string) ([]ini.KeyValue, error) {
+	doc, err := ini.LoadOrEmpty(s.LayerPath(localeName, OverrideLayerName))
+	if err != nil {
+		return nil, err
+	}
+	return doc.Entries(), nil
+}
+
+// SetOverrides 將項目寫入個人覆寫圖層（既有鍵改值、新鍵附加）；remove 中的鍵自圖層移除
+func (s *Store) SetOverrides(localeName string, items []ini.KeyValue, remove []string) error {
+	if _, err := s.INIPath(localeName); err != nil {
+		return err
+	}
+	p := s.LayerPath(localeName, OverrideLayerName)
+	doc, err := ini.LoadOrEmpty(p)
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		doc.Set(it.Key, it.Value)
+	}
+	for _, k := range remove {
+		doc.Remove(doc.Lookup(k)...)
+	}
+	if err := s.snapshotLayers(localeName, "edit"); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	if err := doc.WriteFile(p, doc.Format); err != nil {
+		return fmt.Errorf("write overrides failed: %w", err)
+	}
+	_, err = s.putLayer(localeName, Layer{Name: OverrideLayerName, Kind: LayerOverride})
+	return err
+}
+
+// HasLayers 判斷語系是否有啟用的圖層
+func (s *Store) HasLayers(localeName string) bool {
+	ls, err := s.Layers(localeName)
+	if err != nil {
+		return false
+	}
+	for _, l := range ls.Layers {
+		if l.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// Compose 以基底 global.ini 依序疊加所有啟用的圖層，回傳組合後的文件（尚未寫入）
+func (s *Store) Compose(localeName string) (*ini.Document, error) {
+	base, err := s.INIPath(localeName)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := ini.Load(base)
+	if err != nil {
+		return nil, err
+	}
+	ls, err := s.Layers(localeName)
+	if err != nil {
+		return nil, err
+	}
+	err = applyLayers(doc, ls, func(name string) string {
+		return s.LayerPath(localeName, name)
+	})
+	return doc, err
+}
+
+// applyLayers 依序將啟用的圖層疊加到 doc；layerPath 回傳圖層檔案位置
+func applyLayers(doc *ini.Document, ls LayerStack, layerPath func(name string) string) error {
+	for _, l := range ls.Layers {
+		if !l.Enabled {
+			continue
+		}
+		entries, err := ini.ReadEntries(layerPath(l.Name))
+		if err != nil {
+			return fmt.Errorf("read layer %s failed: %w", l.Name, err)
+		}
+		for _, kv := range entries {
+			doc.Set(kv.Key, kv.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package localestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"zh-tool/pkg/ini"
+)
+
+func writeFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func composedValues(t *testing.T, s *Store, localeName string) map[string]string {
+	t.Helper()
+	doc, err := s.Compose(localeName)
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+	values := map[string]string{}
+	for _, kv := range doc.Entries() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestOverrideSurvivesBaseReimport(t *testing.T) {
+	dir := t.TempDir()
+	s := New(filepath.Join(dir, "Localization"))
+
+	v1 := writeFile(t, filepath.Join(dir, "v1.ini"), "greeting=你好\r\nfarewell=再見\r\n")
+	if _, err := s.Import("chinese", v1); err != nil {
+		t.Fatalf("Import v1: %v", err)
+	}
+	if err := s.SetOverrides("chinese", []ini.KeyValue{{Key: "greeting", Value: "哈囉"}}, nil); err != nil {
+		t.Fatalf("SetOverrides: %v", err)
+	}
+
+	// 個人覆寫不得改寫基底
+	base, err := os.ReadFile(s.Path("chinese"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(base) != "greeting=你好\r\nfarewell=再見\r\n" {
+		t.Fatalf("base changed by SetOverrides: %q", base)
+	}
+
+	// 匯入新版基底後，覆寫仍然生效，其他鍵使用新版的值
+	v2 := writeFile(t, filepath.Join(dir, "v2.ini"), "greeting=您好\r\nfarewell=再會\r\nnew_key=新項目\r\n")
+	if _, err := s.Import("chinese", v2); err != nil {
+		t.Fatalf("Import v2: %v", err)
+	}
+	got := composedValues(t, s, "chinese")
+	want := map[string]string{"greeting": "哈囉", "farewell": "再會", "new_key": "新項目"}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+
+	// 移除覆寫後回復為基底的值
+	if err := s.SetOverrides("chinese", nil, []string{"greeting"}); err != nil {
+		t.Fatalf("SetOverrides remove: %v", err)
+	}
+	if got := composedValues(t, s, "chinese")["greeting"]; got != "您好" {
+		t.Errorf("greeting after removing override = %q, want 您好", got)
+	}
+}
+
+func TestDisabledLayerIsNotComposed(t *testing.T) {
+	dir := t.TempDir()
+	s := New(filepath.Join(dir, "Localization"))
+	if _, err := s.Import("chinese", writeFile(t, filepath.Join(dir, "base.ini"), "a=基底\n")); err != nil {
+		t.Fatal(err)
+	}
+	patch := writeFile(t, filepath.Join(dir, "patch.ini"), "a=修正\n")
+	if _, err := s.ImportLayer("chinese", "team", LayerPatch, patch); err != nil {
+		t.Fatal(err)
+	}
+	if got := composedValues(t, s, "chinese")["a"]; got != "修正" {
+		t.Fatalf("a = %q, want 修正", got)
+	}
+	if err := s.SetLayerEnabled("chinese", "team", false); err != nil {
+		t.Fatal(err)
+	}
+	if got := composedValues(t, s, "chinese")["a"]; got != "基底" {
+		t.Fatalf("a with disabled layer = %q, want 基底", got)
+	}
+}
+
+func TestReimportLayerWithDifferentCase(t *testing.T) {
+	dir := t.TempDir()
+	s := New(filepath.Join(dir, "Localization"))
+	if _, err := s.Import("chinese", writeFile(t, filepath.Join(dir, "base.ini"), "a=基底\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ImportLayer("chinese", "Team", LayerPatch, writeFile(t, filepath.Join(dir, "v1.ini"), "a=第一版\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetLayerEnabled("chinese", "team", false); err != nil {
+		t.Fatal(err)
+	}
+	layer, err := s.ImportLayer("chinese", " TEAM ", LayerPatch, writeFile(t, filepath.Join(dir, "v2.ini"), "a=第二版\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 沿用第一次匯入的名稱與啟用狀態，只留下一個圖層檔案
+	if layer.Name != "Team" || layer.Enabled {
+		t.Errorf("layer = %+v", layer)
+	}
+	files, err := os.ReadDir(filepath.Dir(s.LayerPath("chinese", "Team")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "Team.ini" {
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+		t.Errorf("layer files = %v, want [Team.ini]", names)
+	}
+	ls, err := s.Layers("chinese")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls.Layers) != 1 || ls.Layers[0].Name != "Team" {
+		t.Errorf("layers = %+v", ls.Layers)
+	}
+
+	if err := s.SetLayerEnabled("chinese", "team", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := composedValues(t, s, "chinese")["a"]; got != "第二版" {
+		t.Errorf("a = %q, want the re-imported value", got)
+	}
+	// 刪除時也移除檔案
+	if err := s.DeleteLayer("chinese", "TEAM"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.LayerPath("chinese", "Team")); !os.IsNotExist(err) {
+		t.Error("layer file left after delete")
+	}
+}
@@ -0,0 +1,91 @@
+package localestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"zh-tool/pkg/gameinstall"
+)
+
+// metaFileName 語系資料夾中記錄語系資訊的檔案
+const metaFileName = "locale.json"
+
+// Meta 語系的附加資訊
+type Meta struct {
+	// GameBuild 語系檔對應的遊戲建置
+	GameBuild  gameinstall.BuildInfo `json:"gameBuild"`
+	RecordedAt string                `json:"recordedAt"`
+}
+
+// metaPath 回傳語系資訊檔路徑
+func (s *Store) metaPath(localeName string) string {
+	return filepath.Join(s.Root, localeName, metaFileName)
+}
+
+// Meta 讀取語系資訊；尚未記錄時回傳零值與 nil
+func (s *Store) Meta(localeName string) (Meta, error) {
+	var m Meta
+	data, err := os.ReadFile(s.metaPath(localeName))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Meta{}, fmt.Errorf("invalid locale metadata %s: %w", localeName, err)
+	}
+	return m, nil
+}
+
+// SetGameBuild 記錄語系檔對應的遊戲建置
+func (s *Store) SetGameBuild(localeName string, build gameinstall.BuildInfo) (Meta, error) {
+	if _, err := s.INIPath(localeName); err != nil {
+		return Meta{}, err
+	}
+	m, err := s.Meta(localeName)
+	if err != nil {
+		// 損毀的資訊檔直接覆寫
+		m = Meta{}
+	}
+	m.GameBuild = build
+	m.RecordedAt = time.Now().Format(time.RFC3339)
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return m, err
+	}
+	if err := os.WriteFile(s.metaPath(localeName), data, 0644); err != nil {
+		return m, fmt.Errorf("write locale metadata failed: %w", err)
+	}
+	return m, nil
+}
+
+// BuildCheck 語系檔與目前遊戲建置的比對結果
+type BuildCheck struct {
+	Locale      string                `json:"locale"`
+	LocaleBuild gameinstall.BuildInfo `json:"localeBuild"`
+	GameBuild   gameinstall.BuildInfo `json:"gameBuild"`
+	Recorded    bool                  `json:"recorded"` // 語系是否有記錄遊戲建置
+	Match       bool                  `json:"match"`
+	Warning     string                `json:"warning"`
+}
+
+// CheckBuild 比對語系記錄的遊戲建置與目前安裝的建置
+func CheckBuild(localeName string, m Meta, game gameinstall.BuildInfo) BuildCheck {
+	c := BuildCheck{Locale: localeName, LocaleBuild: m.GameBuild, GameBuild: game, Recorded: !m.GameBuild.Empty()}
+	switch {
+	case !c.Recorded:
+		c.Match = true
+	case game.Empty():
+		c.Match = true
+	default:
+		c.Match = m.GameBuild.SameBuild(game)
+	}
+	if !c.Match {
+		c.Warning = fmt.Sprintf("your translation was built for %s but the game is %s", m.GameBuild, game)
+	}
+	return c
+}
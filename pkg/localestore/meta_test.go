@@ -0,0 +1,67 @@
+package localestore
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"zh-tool/pkg/gameinstall"
+)
+
+func TestCheckBuild(t *testing.T) {
+	recorded := gameinstall.BuildInfo{Version: "3.24.1", ChangeNum: "9227512"}
+	cases := []struct {
+		name     string
+		locale   gameinstall.BuildInfo
+		game     gameinstall.BuildInfo
+		recorded bool
+		match    bool
+	}{
+		{"same build", recorded, gameinstall.BuildInfo{Version: "3.24.1", ChangeNum: "9227512"}, true, true},
+		{"newer game", recorded, gameinstall.BuildInfo{Version: "3.24.2", ChangeNum: "9300000"}, true, false},
+		{"hotfix with same version", recorded, gameinstall.BuildInfo{Version: "3.24.1", ChangeNum: "9230000"}, true, false},
+		// 語系未記錄或讀不到遊戲版本時無從比對，視為相符
+		{"locale not recorded", gameinstall.BuildInfo{}, gameinstall.BuildInfo{Version: "3.24.2"}, false, true},
+		{"game build unknown", recorded, gameinstall.BuildInfo{}, true, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			check := CheckBuild("chinese", Meta{GameBuild: c.locale}, c.game)
+			if check.Recorded != c.recorded || check.Match != c.match {
+				t.Errorf("check = %+v", check)
+			}
+			if c.match != (check.Warning == "") {
+				t.Errorf("warning = %q with match %v", check.Warning, check.Match)
+			}
+			if !c.match && (!strings.Contains(check.Warning, c.locale.String()) || !strings.Contains(check.Warning, c.game.String())) {
+				t.Errorf("warning %q does not name both builds", check.Warning)
+			}
+		})
+	}
+}
+
+func TestSetGameBuild(t *testing.T) {
+	dir := t.TempDir()
+	s := New(filepath.Join(dir, "Localization"))
+	build := gameinstall.BuildInfo{Version: "3.24.1", ChangeNum: "9227512"}
+	if _, err := s.SetGameBuild("chinese", build); err == nil {
+		t.Error("SetGameBuild succeeded for a missing locale")
+	}
+	if m, err := s.Meta("chinese"); err != nil || !m.GameBuild.Empty() {
+		t.Errorf("Meta of a missing locale = %+v, %v", m, err)
+	}
+
+	if _, err := s.Import("chinese", writeFile(t, filepath.Join(dir, "base.ini"), "a=甲\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SetGameBuild("chinese", build); err != nil {
+		t.Fatal(err)
+	}
+	m, err := s.Meta("chinese")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.GameBuild != build || m.RecordedAt == "" {
+		t.Errorf("meta = %+v", m)
+	}
+}
@@ -0,0 +1,152 @@
+// Package localestore 管理本機儲存區中的語系檔（<root>/<locale>/global.ini）
+//
+// 語系檔先存放於使用者本機資料夾，安裝時才複製到遊戲資料夾，避免直接寫入 Program Files。
+package localestore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"zh-tool/pkg/config"
+	"zh-tool/pkg/ini"
+)
+
+// INIFileName 語系資料夾中的語系檔名稱
+const INIFileName = "global.ini"
+
+// Store 本機語系儲存區
+type Store struct {
+	Root string
+	// Retention 語系檔快照的保留原則；零值使用 DefaultRetention
+	Retention Retention
+}
+
+// New 建立以 root 為根目錄的儲存區
+func New(root string) *Store {
+	return &Store{Root: root}
+}
+
+// Default 回傳預設的儲存區：%LOCALAPPDATA%\Squadron978\zh-tool\Localization
+func Default() *Store {
+	return New(filepath.Join(config.DataDir(), "Localization"))
+}
+
+// Path 回傳語系的 global.ini 路徑（不檢查是否存在）
+func (s *Store) Path(localeName string) string {
+	return filepath.Join(s.Root, localeName, INIFileName)
+}
+
+// EnsureRoot 建立儲存區根目錄
+func (s *Store) EnsureRoot() error {
+	return os.MkdirAll(s.Root, 0755)
+}
+
+// HasRoot 檢查儲存區根目錄是否存在
+func (s *Store) HasRoot() bool {
+	_, err := os.Stat(s.Root)
+	return err == nil
+}
+
+// List 列出儲存區中的語系資料夾名稱
+func (s *Store) List() []string {
+	entries, err := os.ReadDir(s.Root)
+	if err != nil {
+		return []string{}
+	}
+	var result []string
+	for _, e := range entries {
+		if e.IsDir() {
+			name := e.Name()
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// HasChinese 檢查儲存區中是否已有中文語系
+func (s *Store) HasChinese() bool {
+	for _, name := range s.List() {
+		name = strings.ToLower(name)
+		if strings.Contains(name, "chinese") || strings.Contains(name, "zh") {
+			return true
+		}
+	}
+	return false
+}
+
+// INIPath 回傳語系的 global.ini 路徑；檔案不存在時回傳錯誤
+func (s *Store) INIPath(localeName string) (string, error) {
+	if strings.TrimSpace(localeName) == "" {
+		return "", fmt.Errorf("invalid locale name")
+	}
+	p := s.Path(localeName)
+	if _, err := os.Stat(p); err == nil {
+		return p, nil
+	}
+	return "", fmt.Errorf("local locale ini not found: %s", localeName)
+}
+
+// Import 將來源檔案存入指定語系（UTF-16 / Big5 / GBK 會轉為 UTF-8），回傳寫入的路徑
+func (s *Store) Import(localeName, sourceFilePath string) (string, error) {
+	if strings.TrimSpace(localeName) == "" {
+		return "", fmt.Errorf("locale name is required")
+	}
+	if strings.TrimSpace(sourceFilePath) == "" {
+		return "", fmt.Errorf("source file path is required")
+	}
+	if _, err := os.Stat(sourceFilePath); err != nil {
+		return "", err
+	}
+	data, _, err := ini.ReadFileAsUTF8(sourceFilePath)
+	if err != nil {
+		return "", fmt.Errorf("import %s failed: %w", filepath.Base(sourceFilePath), err)
+	}
+	targetDir := filepath.Join(s.Root, localeName)
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", err
+	}
+	// 覆寫前保留目前內容的快照
+	if _, err := s.Snapshot(localeName, "import"); err != nil {
+		return "", fmt.Errorf("snapshot before import failed: %w", err)
+	}
+	dest := filepath.Join(targetDir, INIFileName)
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// Export 將語系的 global.ini 原樣複製到目標路徑
+func (s *Store) Export(localeName, destFile string) error {
+	if strings.TrimSpace(localeName) == "" {
+		return fmt.Errorf("invalid locale name")
+	}
+	if strings.TrimSpace(destFile) == "" {
+		return fmt.Errorf("invalid destination path")
+	}
+	data, err := os.ReadFile(s.Path(localeName))
+	if err != nil {
+		return fmt.Errorf("read source failed: %w", err)
+	}
+	if err := os.WriteFile(destFile, data, 0644); err != nil {
+		return fmt.Errorf("write destination failed: %w", err)
+	}
+	return nil
+}
+
+// Delete 刪除語系資料夾；不存在則跳過
+func (s *Store) Delete(localeName string) error {
+	if strings.TrimSpace(localeName) == "" {
+		return fmt.Errorf("invalid locale name")
+	}
+	dir := filepath.Join(s.Root, localeName)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return os.RemoveAll(dir)
+}
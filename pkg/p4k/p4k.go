@@ -0,0 +1,404 @@
+// Package p4k 讀取 Star Citizen 的 Data.p4k 封存檔
+//
+// Data.p4k 是 ZIP64 格式的封存檔，項目以 ZStd（壓縮方法 100）或 deflate 壓縮。
+// 檔案可達上百 GB、項目數十萬，因此只串流讀取中央目錄尋找需要的項目，不一次載入全部清單。
+// 部分項目的本機標頭簽章為 PK\x03\x14（非標準的 PK\x03\x04），兩者皆接受。
+package p4k
+
+import (
+	"bufio"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// EnglishGlobalINI 封存檔內英文 global.ini 的路徑
+const EnglishGlobalINI = "Data/Localization/english/global.ini"
+
+// 支援的壓縮方法
+const (
+	MethodStore   = 0
+	MethodDeflate = 8
+	MethodZstd    = 100
+)
+
+var (
+	// ErrNotFound 封存檔中沒有指定的項目
+	ErrNotFound = errors.New("p4k: entry not found")
+	// ErrFormat 不是有效的 p4k / ZIP 封存檔
+	ErrFormat = errors.New("p4k: not a valid archive")
+	// ErrEncrypted 項目已加密，無法讀取
+	ErrEncrypted = errors.New("p4k: entry is encrypted")
+)
+
+const (
+	sigLocalHeader    = 0x04034b50
+	sigLocalHeaderP4K = 0x14034b50
+	sigCentralDir     = 0x02014b50
+	sigEndOfDir       = 0x06054b50
+	sigEndOfDir64     = 0x06064b50
+	sigEndOfDir64Loc  = 0x07064b50
+	endOfDirLen       = 22
+	endOfDir64LocLen  = 20
+	endOfDir64Len     = 56
+	centralDirLen     = 46
+	localHeaderLen    = 30
+	maxCommentLen     = 0xffff
+	zip64ExtraID      = 0x0001
+	flagEncrypted     = 0x1
+	uint32Max         = 0xffffffff
+)
+
+// Entry 封存檔中的單一項目（取自中央目錄）
+type Entry struct {
+	Name             string `json:"name"`
+	Method           uint16 `json:"method"`
+	Flags            uint16 `json:"flags"`
+	CRC32            uint32 `json:"crc32"`
+	CompressedSize   uint64 `json:"compressedSize"`
+	UncompressedSize uint64 `json:"uncompressedSize"`
+	headerOffset     uint64
+}
+
+// Encrypted 判斷項目是否已加密
+func (e *Entry) Encrypted() bool {
+	return e.Flags&flagEncrypted != 0
+}
+
+// Reader 以 io.ReaderAt 讀取的封存檔；可直接用於記憶體中的小型測試封存檔
+type Reader struct {
+	r         io.ReaderAt
+	size      int64
+	dirOffset uint64
+	dirSize   uint64
+	entries   uint64
+}
+
+// ReadCloser 以檔案開啟的封存檔
+type ReadCloser struct {
+	Reader
+	f *os.File
+}
+
+// Open 開啟 Data.p4k 並讀取中央目錄位置
+func Open(path string) (*ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	rc := &ReadCloser{f: f}
+	if err := rc.init(f, st.Size()); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("open %s failed: %w", path, err)
+	}
+	return rc, nil
+}
+
+// Close 關閉封存檔
+func (rc *ReadCloser) Close() error {
+	return rc.f.Close()
+}
+
+// NewReader 自 io.ReaderAt 讀取封存檔
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	zr := &Reader{}
+	if err := zr.init(r, size); err != nil {
+		return nil, err
+	}
+	return zr, nil
+}
+
+// Len 回傳中央目錄記錄的項目數量
+func (z *Reader) Len() uint64 {
+	return z.entries
+}
+
+// init 找出中央目錄結尾（含 ZIP64 結尾）並記錄中央目錄的位置與大小
+func (z *Reader) init(r io.ReaderAt, size int64) error {
+	z.r = r
+	z.size = size
+	if size < endOfDirLen {
+		return ErrFormat
+	}
+
+	// 中央目錄結尾位於檔案最後，後面可能接最長 64KB 的註解
+	tailLen := int64(endOfDirLen + maxCommentLen + endOfDir64LocLen)
+	if tailLen > size {
+		tailLen = size
+	}
+	tail := make([]byte, tailLen)
+	if _, err := r.ReadAt(tail, size-tailLen); err != nil && err != io.EOF {
+		return err
+	}
+	eocd := -1
+	for i := len(tail) - endOfDirLen; i >= 0; i-- {
+		if binary.LittleEndian.Uint32(tail[i:]) != sigEndOfDir {
+			continue
+		}
+		commentLen := int(binary.LittleEndian.Uint16(tail[i+20:]))
+		if i+endOfDirLen+commentLen <= len(tail) {
+			eocd = i
+			break
+		}
+	}
+	if eocd < 0 {
+		return ErrFormat
+	}
+	b := tail[eocd:]
+	z.entries = uint64(binary.LittleEndian.Uint16(b[10:]))
+	z.dirSize = uint64(binary.LittleEndian.Uint32(b[12:]))
+	z.dirOffset = uint64(binary.LittleEndian.Uint32(b[16:]))
+
+	// ZIP64：結尾前緊接著 ZIP64 結尾定位記錄
+	if eocd >= endOfDir64LocLen {
+		loc := tail[eocd-endOfDir64LocLen:]
+		if binary.LittleEndian.Uint32(loc) == sigEndOfDir64Loc {
+			off := binary.LittleEndian.Uint64(loc[8:])
+			if off > uint64(size)-endOfDir64Len {
+				return ErrFormat
+			}
+			rec := make([]byte, endOfDir64Len)
+			if _, err := r.ReadAt(rec, int64(off)); err != nil {
+				return err
+			}
+			if binary.LittleEndian.Uint32(rec) != sigEndOfDir64 {
+				return ErrFormat
+			}
+			z.entries = binary.LittleEndian.Uint64(rec[32:])
+			z.dirSize = binary.LittleEndian.Uint64(rec[40:])
+			z.dirOffset = binary.LittleEndian.Uint64(rec[48:])
+		}
+	}
+	if z.dirOffset+z.dirSize > uint64(size) {
+		return ErrFormat
+	}
+	return nil
+}
+
+// Walk 依中央目錄順序逐一讀取項目；fn 回傳 io.EOF 時提前結束且 Walk 回傳 nil
+func (z *Reader) Walk(fn func(e *Entry) error) error {
+	br := bufio.NewReaderSize(io.NewSectionReader(z.r, int64(z.dirOffset), int64(z.dirSize)), 1<<20)
+	hdr := make([]byte, centralDirLen)
+	for i := uint64(0); i < z.entries; i++ {
+		if _, err := io.ReadFull(br, hdr); err != nil {
+			return fmt.Errorf("read central directory failed: %w", err)
+		}
+		if binary.LittleEndian.Uint32(hdr) != sigCentralDir {
+			return ErrFormat
+		}
+		e := &Entry{
+			Flags:            binary.LittleEndian.Uint16(hdr[8:]),
+			Method:           binary.LittleEndian.Uint16(hdr[10:]),
+			CRC32:            binary.LittleEndian.Uint32(hdr[16:]),
+			CompressedSize:   uint64(binary.LittleEndian.Uint32(hdr[20:])),
+			UncompressedSize: uint64(binary.LittleEndian.Uint32(hdr[24:])),
+			headerOffset:     uint64(binary.LittleEndian.Uint32(hdr[42:])),
+		}
+		nameLen := int(binary.LittleEndian.Uint16(hdr[28:]))
+		extraLen := int(binary.LittleEndian.Uint16(hdr[30:]))
+		commentLen := int(binary.LittleEndian.Uint16(hdr[32:]))
+		rest := make([]byte, nameLen+extraLen+commentLen)
+		if _, err := io.ReadFull(br, rest); err != nil {
+			return fmt.Errorf("read central directory failed: %w", err)
+		}
+		e.Name = string(rest[:nameLen])
+		if err := e.readZip64Extra(rest[nameLen : nameLen+extraLen]); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// readZip64Extra 依 ZIP64 額外欄位補上超過 4GB 的大小與位移
+func (e *Entry) readZip64Extra(extra []byte) error {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		n := int(binary.LittleEndian.Uint16(extra[2:]))
+		extra = extra[4:]
+		if n > len(extra) {
+			return ErrFormat
+		}
+		field := extra[:n]
+		extra = extra[n:]
+		if id != zip64ExtraID {
+			continue
+		}
+		next := func(v *uint64) error {
+			if *v != uint32Max {
+				return nil
+			}
+			if len(field) < 8 {
+				return ErrFormat
+			}
+			*v = binary.LittleEndian.Uint64(field)
+			field = field[8:]
+			return nil
+		}
+		for _, v := range []*uint64{&e.UncompressedSize, &e.CompressedSize, &e.headerOffset} {
+			if err := next(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeName 統一路徑分隔符號，供比對項目名稱
+func normalizeName(name string) string {
+	return strings.TrimPrefix(strings.ReplaceAll(name, "\\", "/"), "/")
+}
+
+// Find 依名稱尋找項目（不分大小寫，/ 與 \ 視為相同）
+func (z *Reader) Find(name string) (*Entry, error) {
+	want := normalizeName(name)
+	var found *Entry
+	err := z.Walk(func(e *Entry) error {
+		if strings.EqualFold(normalizeName(e.Name), want) {
+			found = e
+			return io.EOF
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return found, nil
+}
+
+// Open 開啟項目內容；讀到結尾時檢查長度與 CRC32
+func (z *Reader) Open(e *Entry) (io.ReadCloser, error) {
+	if e.Encrypted() {
+		return nil, fmt.Errorf("%w: %s", ErrEncrypted, e.Name)
+	}
+	hdr := make([]byte, localHeaderLen)
+	if _, err := z.r.ReadAt(hdr, int64(e.headerOffset)); err != nil {
+		return nil, fmt.Errorf("read local header failed: %w", err)
+	}
+	switch binary.LittleEndian.Uint32(hdr) {
+	case sigLocalHeader, sigLocalHeaderP4K:
+	default:
+		return nil, ErrFormat
+	}
+	nameLen := uint64(binary.LittleEndian.Uint16(hdr[26:]))
+	extraLen := uint64(binary.LittleEndian.Uint16(hdr[28:]))
+	dataOffset := e.headerOffset + localHeaderLen + nameLen + extraLen
+	if dataOffset+e.CompressedSize > uint64(z.size) {
+		return nil, ErrFormat
+	}
+	raw := io.NewSectionReader(z.r, int64(dataOffset), int64(e.CompressedSize))
+
+	var rc io.ReadCloser
+	switch e.Method {
+	case MethodStore:
+		rc = io.NopCloser(raw)
+	case MethodDeflate:
+		rc = flate.NewReader(raw)
+	case MethodZstd:
+		dec, err := zstd.NewReader(raw, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		rc = dec.IOReadCloser()
+	default:
+		return nil, fmt.Errorf("p4k: unsupported compression method %d: %s", e.Method, e.Name)
+	}
+	return &checksumReader{rc: rc, entry: e, hash: crc32.NewIEEE()}, nil
+}
+
+// checksumReader 讀取項目內容並於結尾驗證長度與 CRC32
+type checksumReader struct {
+	rc    io.ReadCloser
+	entry *Entry
+	hash  hash.Hash32
+	n     uint64
+	err   error
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.rc.Read(p)
+	c.hash.Write(p[:n])
+	c.n += uint64(n)
+	if c.n > c.entry.UncompressedSize {
+		err = fmt.Errorf("p4k: %s is larger than recorded size", c.entry.Name)
+	} else if err == io.EOF {
+		switch {
+		case c.n != c.entry.UncompressedSize:
+			err = fmt.Errorf("p4k: %s is truncated", c.entry.Name)
+		case c.entry.CRC32 != 0 && c.hash.Sum32() != c.entry.CRC32:
+			err = fmt.Errorf("p4k: %s checksum mismatch", c.entry.Name)
+		}
+	}
+	c.err = err
+	return n, err
+}
+
+func (c *checksumReader) Close() error {
+	return c.rc.Close()
+}
+
+// ExtractTo 將項目內容寫入 dest（先寫暫存檔再替換）
+func (z *Reader) ExtractTo(e *Entry, dest string) error {
+	src, err := z.Open(e)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp := dest + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("extract %s failed: %w", e.Name, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+// ExtractFile 自 p4kPath 取出名稱為 name 的項目並寫入 dest
+func ExtractFile(p4kPath, name, dest string) (*Entry, error) {
+	rc, err := Open(p4kPath)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	e, err := rc.Find(name)
+	if err != nil {
+		return nil, err
+	}
+	return e, rc.ExtractTo(e, dest)
+}
@@ -0,0 +1,264 @@
+package p4k
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// testEntry 測試封存檔中的項目
+type testEntry struct {
+	name     string
+	method   uint16
+	data     []byte
+	badCRC   bool // 記錄錯誤的 CRC32
+	p4kLocal bool // 本機標頭使用 PK\x03\x14 簽章
+}
+
+// compress 依壓縮方法壓縮內容
+func compress(t *testing.T, method uint16, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	switch method {
+	case MethodStore:
+		buf.Write(data)
+	case MethodDeflate:
+		w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+		w.Close()
+	case MethodZstd:
+		w, err := zstd.NewWriter(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+		w.Close()
+	default:
+		t.Fatalf("unsupported method %d", method)
+	}
+	return buf.Bytes()
+}
+
+// buildArchive 在記憶體中組出封存檔；zip64 為 true 時中央目錄的大小與位移記錄於 ZIP64 額外欄位，並寫入 ZIP64 結尾
+func buildArchive(t *testing.T, entries []testEntry, zip64 bool) []byte {
+	t.Helper()
+	var out, dir bytes.Buffer
+	le := binary.LittleEndian
+	for _, e := range entries {
+		packed := compress(t, e.method, e.data)
+		sum := crc32.ChecksumIEEE(e.data)
+		if e.badCRC {
+			sum ^= 0xdeadbeef
+		}
+		offset := uint32(out.Len())
+
+		sig := uint32(sigLocalHeader)
+		if e.p4kLocal {
+			sig = sigLocalHeaderP4K
+		}
+		local := make([]byte, localHeaderLen)
+		le.PutUint32(local, sig)
+		le.PutUint16(local[4:], 20)
+		le.PutUint16(local[8:], e.method)
+		le.PutUint32(local[14:], sum)
+		le.PutUint32(local[18:], uint32(len(packed)))
+		le.PutUint32(local[22:], uint32(len(e.data)))
+		le.PutUint16(local[26:], uint16(len(e.name)))
+		out.Write(local)
+		out.WriteString(e.name)
+		out.Write(packed)
+
+		var extra []byte
+		csize, usize, off := uint32(len(packed)), uint32(len(e.data)), offset
+		if zip64 {
+			extra = make([]byte, 4+24)
+			le.PutUint16(extra, zip64ExtraID)
+			le.PutUint16(extra[2:], 24)
+			le.PutUint64(extra[4:], uint64(len(e.data)))
+			le.PutUint64(extra[12:], uint64(len(packed)))
+			le.PutUint64(extra[20:], uint64(offset))
+			csize, usize, off = uint32Max, uint32Max, uint32Max
+		}
+		central := make([]byte, centralDirLen)
+		le.PutUint32(central, sigCentralDir)
+		le.PutUint16(central[4:], 45)
+		le.PutUint16(central[6:], 20)
+		le.PutUint16(central[10:], e.method)
+		le.PutUint32(central[16:], sum)
+		le.PutUint32(central[20:], csize)
+		le.PutUint32(central[24:], usize)
+		le.PutUint16(central[28:], uint16(len(e.name)))
+		le.PutUint16(central[30:], uint16(len(extra)))
+		le.PutUint32(central[42:], off)
+		dir.Write(central)
+		dir.WriteString(e.name)
+		dir.Write(extra)
+	}
+
+	dirOffset := out.Len()
+	out.Write(dir.Bytes())
+	count, dirSize, dirOff := uint16(len(entries)), uint32(dir.Len()), uint32(dirOffset)
+	if zip64 {
+		end64Offset := out.Len()
+		rec := make([]byte, endOfDir64Len)
+		le.PutUint32(rec, sigEndOfDir64)
+		le.PutUint64(rec[4:], endOfDir64Len-12)
+		le.PutUint16(rec[12:], 45)
+		le.PutUint16(rec[14:], 45)
+		le.PutUint64(rec[24:], uint64(len(entries)))
+		le.PutUint64(rec[32:], uint64(len(entries)))
+		le.PutUint64(rec[40:], uint64(dir.Len()))
+		le.PutUint64(rec[48:], uint64(dirOffset))
+		out.Write(rec)
+
+		loc := make([]byte, endOfDir64LocLen)
+		le.PutUint32(loc, sigEndOfDir64Loc)
+		le.PutUint64(loc[8:], uint64(end64Offset))
+		le.PutUint32(loc[16:], 1)
+		out.Write(loc)
+		count, dirSize, dirOff = 0xffff, uint32Max, uint32Max
+	}
+	end := make([]byte, endOfDirLen)
+	le.PutUint32(end, sigEndOfDir)
+	le.PutUint16(end[8:], count)
+	le.PutUint16(end[10:], count)
+	le.PutUint32(end[12:], dirSize)
+	le.PutUint32(end[16:], dirOff)
+	out.Write(end)
+	return out.Bytes()
+}
+
+// writeArchive 將封存檔寫入暫存資料夾並回傳路徑
+func writeArchive(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Data.p4k")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+var englishINI = []byte(strings.Repeat("vehicle_Name=Aurora\r\nui_Ok=OK\r\n", 50))
+
+func sampleEntries() []testEntry {
+	return []testEntry{
+		{name: "Data/readme.txt", method: MethodStore, data: []byte("stored entry")},
+		{name: "Data/Scripts/list.xml", method: MethodDeflate, data: []byte(strings.Repeat("<item/>", 100))},
+		// 實際封存檔以反斜線分隔，本機標頭使用 p4k 的簽章
+		{name: `Data\Localization\english\global.ini`, method: MethodZstd, data: englishINI, p4kLocal: true},
+	}
+}
+
+func TestExtractFile(t *testing.T) {
+	for _, zip64 := range []bool{false, true} {
+		name := "zip"
+		if zip64 {
+			name = "zip64"
+		}
+		t.Run(name, func(t *testing.T) {
+			path := writeArchive(t, buildArchive(t, sampleEntries(), zip64))
+			for _, want := range sampleEntries() {
+				dest := filepath.Join(t.TempDir(), "out")
+				e, err := ExtractFile(path, want.name, dest)
+				if err != nil {
+					t.Fatalf("ExtractFile(%s): %v", want.name, err)
+				}
+				if e.Method != want.method || e.UncompressedSize != uint64(len(want.data)) {
+					t.Errorf("%s: method %d size %d, want %d %d", want.name, e.Method, e.UncompressedSize, want.method, len(want.data))
+				}
+				got, err := os.ReadFile(dest)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, want.data) {
+					t.Errorf("%s: extracted content differs", want.name)
+				}
+			}
+		})
+	}
+}
+
+func TestFindEnglishGlobalINI(t *testing.T) {
+	data := buildArchive(t, sampleEntries(), true)
+	z, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", z.Len())
+	}
+	// 以 / 分隔、大小寫不同的名稱也能找到以反斜線記錄的項目
+	for _, name := range []string{EnglishGlobalINI, "data/localization/ENGLISH/global.ini", `\Data\Localization\english\global.ini`} {
+		e, err := z.Find(name)
+		if err != nil {
+			t.Fatalf("Find(%s): %v", name, err)
+		}
+		if e.Method != MethodZstd {
+			t.Errorf("Find(%s) returned %s", name, e.Name)
+		}
+	}
+	if _, err := z.Find("Data/Localization/german/global.ini"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find missing entry: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestExtractFileChecksumMismatch(t *testing.T) {
+	for _, method := range []uint16{MethodStore, MethodDeflate, MethodZstd} {
+		entries := []testEntry{{name: EnglishGlobalINI, method: method, data: englishINI, badCRC: true}}
+		path := writeArchive(t, buildArchive(t, entries, false))
+		dest := filepath.Join(t.TempDir(), "global.ini")
+		_, err := ExtractFile(path, EnglishGlobalINI, dest)
+		if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+			t.Fatalf("method %d: err = %v, want checksum mismatch", method, err)
+		}
+		// 驗證失敗時不留下目的檔案或暫存檔
+		for _, p := range []string{dest, dest + ".tmp"} {
+			if _, err := os.Stat(p); !os.IsNotExist(err) {
+				t.Errorf("method %d: %s should not exist", method, p)
+			}
+		}
+	}
+}
+
+func TestInvalidArchive(t *testing.T) {
+	valid := buildArchive(t, sampleEntries(), true)
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not a zip", []byte(strings.Repeat("not an archive ", 10))},
+		// 截掉開頭使中央目錄位移超出檔案
+		{"truncated", valid[len(valid)/2:]},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ExtractFile(writeArchive(t, c.data), EnglishGlobalINI, filepath.Join(t.TempDir(), "out"))
+			if !errors.Is(err, ErrFormat) {
+				t.Errorf("err = %v, want ErrFormat", err)
+			}
+		})
+	}
+}
+
+func TestEncryptedEntry(t *testing.T) {
+	data := buildArchive(t, []testEntry{{name: EnglishGlobalINI, method: MethodStore, data: englishINI}}, false)
+	// 在中央目錄的旗標設定加密位元
+	dirOffset := binary.LittleEndian.Uint32(data[len(data)-endOfDirLen+16:])
+	binary.LittleEndian.PutUint16(data[dirOffset+8:], flagEncrypted)
+	_, err := ExtractFile(writeArchive(t, data), EnglishGlobalINI, filepath.Join(t.TempDir(), "out"))
+	if !errors.Is(err, ErrEncrypted) {
+		t.Errorf("err = %v, want ErrEncrypted", err)
+	}
+}
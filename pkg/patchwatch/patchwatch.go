@@ -0,0 +1,238 @@
+// Package patchwatch 偵測遊戲更新是否覆蓋了已安裝的語系檔與語系設定
+//
+// 每次套用語系時記錄預期狀態（安裝的 global.ini 雜湊與 g_language），
+// 之後比對遊戲資料夾的實際狀態；遊戲更新會替換 data 目錄，使兩者不一致。
+package patchwatch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"zh-tool/pkg/config"
+	"zh-tool/pkg/gameinstall"
+	"zh-tool/pkg/installer"
+)
+
+// StateFileName 記錄各版本預期狀態的檔案（位於本機資料目錄）
+const StateFileName = "patch-watch.json"
+
+// Expected 上次套用語系後，遊戲資料夾應有的狀態
+type Expected struct {
+	Channel   string `json:"channel"`
+	Locale    string `json:"locale"`    // 安裝的語系資料夾名稱（本機儲存區中的同名語系為重新套用的來源）
+	INIHash   string `json:"iniHash"`   // 安裝的 global.ini 的 SHA-256；空字串表示不檢查
+	Language  string `json:"language"`  // system.cfg 的 g_language；空字串表示不檢查
+	AppliedAt string `json:"appliedAt"` // 最後一次記錄的時間
+}
+
+// Status 遊戲資料夾目前的狀態與預期狀態的比較
+type Status struct {
+	Expected         Expected `json:"expected"`
+	INIHash          string   `json:"iniHash"`
+	INIMissing       bool     `json:"iniMissing"`
+	Language         string   `json:"language"`
+	INIReverted      bool     `json:"iniReverted"`      // 語系檔遭刪除或內容已不同
+	LanguageReverted bool     `json:"languageReverted"` // g_language 遭移除或改變
+	Reverted         bool     `json:"reverted"`
+	CheckedAt        string   `json:"checkedAt"`
+}
+
+// State 各版本的預期狀態
+type State struct {
+	Version  int                 `json:"version"`
+	Channels map[string]Expected `json:"channels"`
+}
+
+// StatePath 回傳預期狀態檔的路徑
+func StatePath() string {
+	return filepath.Join(config.DataDir(), StateFileName)
+}
+
+// LoadState 讀取預期狀態；不存在或損毀時回傳空狀態
+func LoadState(path string) *State {
+	st := &State{Version: 1, Channels: map[string]Expected{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return st
+	}
+	if err := json.Unmarshal(data, st); err != nil || st.Channels == nil {
+		return &State{Version: 1, Channels: map[string]Expected{}}
+	}
+	return st
+}
+
+// Save 寫入預期狀態（先寫暫存檔再替換）
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ChannelNames 依名稱排序回傳有記錄的版本
+func (s *State) ChannelNames() []string {
+	names := make([]string, 0, len(s.Channels))
+	for ch := range s.Channels {
+		names = append(names, ch)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RecordINI 記錄某版本安裝的語系與 global.ini 內容（以安裝來源檔計算雜湊）
+func (s *State) RecordINI(channel, localeName, sourcePath string) error {
+	hash, err := HashFile(sourcePath)
+	if err != nil {
+		return err
+	}
+	exp := s.Channels[channel]
+	exp.Channel = channel
+	exp.Locale = localeName
+	exp.INIHash = hash
+	exp.AppliedAt = time.Now().Format(time.RFC3339)
+	s.Channels[channel] = exp
+	return nil
+}
+
+// RecordLanguage 記錄某版本設定的 g_language；空字串表示已重設為原版語系，不再檢查
+func (s *State) RecordLanguage(channel, language string) {
+	exp := s.Channels[channel]
+	exp.Channel = channel
+	exp.Language = language
+	exp.AppliedAt = time.Now().Format(time.RFC3339)
+	if exp.Language == "" && exp.INIHash == "" {
+		delete(s.Channels, channel)
+		return
+	}
+	s.Channels[channel] = exp
+}
+
+// Forget 移除某版本的記錄（例如解除安裝後不再檢查）
+func (s *State) Forget(channel string) {
+	delete(s.Channels, channel)
+}
+
+// HashFile 計算檔案內容的 SHA-256
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Check 比對遊戲資料夾目前的狀態與預期狀態
+func Check(scPath string, exp Expected) Status {
+	s := Status{Expected: exp, CheckedAt: time.Now().Format(time.RFC3339)}
+	if exp.INIHash != "" && exp.Locale != "" {
+		iniPath := filepath.Join(gameinstall.LocalizationDir(scPath, exp.Channel, exp.Locale), "global.ini")
+		hash, err := HashFile(iniPath)
+		if err != nil {
+			s.INIMissing = true
+			s.INIReverted = true
+		} else {
+			s.INIHash = hash
+			s.INIReverted = hash != exp.INIHash
+		}
+	}
+	s.Language = installer.GetLanguage(scPath, exp.Channel)
+	if exp.Language != "" {
+		s.LanguageReverted = s.Language != exp.Language
+	}
+	s.Reverted = s.INIReverted || s.LanguageReverted
+	return s
+}
+
+// CheckAll 比對所有有記錄的版本（依名稱排序）
+func CheckAll(scPath string, st *State) []Status {
+	result := []Status{}
+	for _, ch := range st.ChannelNames() {
+		result = append(result, Check(scPath, st.Channels[ch]))
+	}
+	return result
+}
+
+// Watcher 定期檢查各版本是否被遊戲更新還原
+//
+// 遊戲更新期間檔案會陸續被替換，為避免在更新途中重新套用，
+// 同一版本需連續 Settle 次檢查都是相同的還原狀態才呼叫 OnRevert。
+type Watcher struct {
+	Interval time.Duration
+	Settle   int
+	Check    func() ([]Status, error)
+	OnRevert func(Status)
+	OnError  func(error)
+}
+
+// Run 依 Interval 檢查直到 ctx 結束
+func (w *Watcher) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	settle := w.Settle
+	if settle < 1 {
+		settle = 1
+	}
+	type pending struct {
+		key   string
+		count int
+	}
+	seen := map[string]*pending{}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		statuses, err := w.Check()
+		if err != nil {
+			if w.OnError != nil {
+				w.OnError(err)
+			}
+		} else {
+			for _, s := range statuses {
+				ch := s.Expected.Channel
+				if !s.Reverted {
+					delete(seen, ch)
+					continue
+				}
+				// 同一次還原只通知一次；狀態改變（例如更新仍在進行）時重新計數
+				key := fmt.Sprintf("%s|%t|%s", s.INIHash, s.INIMissing, s.Language)
+				p := seen[ch]
+				if p == nil || p.key != key {
+					p = &pending{key: key}
+					seen[ch] = p
+				}
+				p.count++
+				if p.count == settle && w.OnRevert != nil {
+					w.OnRevert(s)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package patchwatch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// reverted 建立某版本被還原的狀態；language 不同代表更新途中的不同階段
+func reverted(channel, language string) Status {
+	return Status{Expected: Expected{Channel: channel}, Language: language, LanguageReverted: true, Reverted: true}
+}
+
+func intact(channel string) Status {
+	return Status{Expected: Expected{Channel: channel}, Language: "chinese"}
+}
+
+// runWatcher 依序回傳 rounds 中每一輪的檢查結果，全部檢查完後結束，回傳 OnRevert 收到的狀態
+func runWatcher(t *testing.T, settle int, rounds [][]Status) []Status {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var notified []Status
+	i := 0
+	w := &Watcher{
+		Interval: time.Millisecond,
+		Settle:   settle,
+		Check: func() ([]Status, error) {
+			if i == len(rounds)-1 {
+				cancel()
+			}
+			r := rounds[i]
+			i++
+			return r, nil
+		},
+		OnRevert: func(s Status) { notified = append(notified, s) },
+	}
+	w.Run(ctx)
+	if i != len(rounds) {
+		t.Fatalf("watcher checked %d of %d rounds", i, len(rounds))
+	}
+	return notified
+}
+
+// notifiedChannels 回傳 OnRevert 收到的版本與語系，方便比對
+func notifiedChannels(statuses []Status) []string {
+	out := []string{}
+	for _, s := range statuses {
+		out = append(out, s.Expected.Channel+":"+s.Language)
+	}
+	return out
+}
+
+func TestWatcherRun(t *testing.T) {
+	cases := []struct {
+		name   string
+		settle int
+		rounds [][]Status
+		want   []string
+	}{
+		{
+			name:   "waits for settle count",
+			settle: 3,
+			rounds: [][]Status{{reverted("LIVE", "")}, {reverted("LIVE", "")}},
+			want:   []string{},
+		},
+		{
+			name:   "one notification per revert",
+			settle: 2,
+			rounds: [][]Status{{reverted("LIVE", "")}, {reverted("LIVE", "")}, {reverted("LIVE", "")}, {reverted("LIVE", "")}},
+			want:   []string{"LIVE:"},
+		},
+		{
+			// 更新途中狀態仍在變動：重新計數
+			name:   "state change resets count",
+			settle: 2,
+			rounds: [][]Status{{reverted("LIVE", "")}, {reverted("LIVE", "english")}, {reverted("LIVE", "")}, {reverted("LIVE", "")}},
+			want:   []string{"LIVE:"},
+		},
+		{
+			// 恢復正常後再次被還原，視為新的還原
+			name:   "revert after recovery notifies again",
+			settle: 1,
+			rounds: [][]Status{{reverted("LIVE", "")}, {intact("LIVE")}, {reverted("LIVE", "")}},
+			want:   []string{"LIVE:", "LIVE:"},
+		},
+		{
+			name:   "channels counted separately",
+			settle: 2,
+			rounds: [][]Status{
+				{reverted("LIVE", ""), intact("PTU")},
+				{reverted("LIVE", ""), reverted("PTU", "")},
+				{intact("LIVE"), reverted("PTU", "")},
+			},
+			want: []string{"LIVE:", "PTU:"},
+		},
+		{
+			name:   "settle below one treated as one",
+			settle: 0,
+			rounds: [][]Status{{reverted("LIVE", "")}},
+			want:   []string{"LIVE:"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := notifiedChannels(runWatcher(t, c.settle, c.rounds))
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("OnRevert = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWatcherReportsCheckErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var errs []error
+	w := &Watcher{
+		Interval: time.Millisecond,
+		Check: func() ([]Status, error) {
+			cancel()
+			return nil, errors.New("game folder unavailable")
+		},
+		OnRevert: func(Status) { t.Error("OnRevert called after a failed check") },
+		OnError:  func(err error) { errs = append(errs, err) },
+	}
+	w.Run(ctx)
+	if len(errs) != 1 {
+		t.Errorf("OnError called %d times", len(errs))
+	}
+}
+
+func TestCheck(t *testing.T) {
+	scPath := t.TempDir()
+	source := writeFile(t, filepath.Join(t.TempDir(), "global.ini"), "ui_Ok=確定\r\n")
+	ini := writeFile(t, filepath.Join(scPath, "LIVE", "data", "Localization", "chinese", "global.ini"), "ui_Ok=確定\r\n")
+	cfg := writeFile(t, filepath.Join(scPath, "LIVE", "data", "system.cfg"), "g_language = chinese\r\n")
+	// Bin64 讓路徑被視為有效的遊戲資料夾
+	if err := os.MkdirAll(filepath.Join(scPath, "LIVE", "Bin64"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	st := &State{Version: 1, Channels: map[string]Expected{}}
+	if err := st.RecordINI("LIVE", "chinese", source); err != nil {
+		t.Fatal(err)
+	}
+	st.RecordLanguage("LIVE", "chinese")
+	exp := st.Channels["LIVE"]
+	if exp.Channel != "LIVE" || exp.Locale != "chinese" || exp.INIHash == "" || exp.Language != "chinese" {
+		t.Fatalf("expected = %+v", exp)
+	}
+
+	if s := Check(scPath, exp); s.Reverted || s.Language != "chinese" || s.INIHash != exp.INIHash {
+		t.Errorf("intact install: %+v", s)
+	}
+
+	// 遊戲更新換回英文設定並替換語系檔
+	writeFile(t, cfg, "g_language = english\r\n")
+	writeFile(t, ini, "ui_Ok=OK\r\n")
+	s := Check(scPath, exp)
+	if !s.Reverted || !s.INIReverted || !s.LanguageReverted || s.INIMissing || s.Language != "english" {
+		t.Errorf("patched install: %+v", s)
+	}
+
+	if err := os.Remove(ini); err != nil {
+		t.Fatal(err)
+	}
+	if s := Check(scPath, exp); !s.INIMissing || !s.INIReverted {
+		t.Errorf("deleted global.ini: %+v", s)
+	}
+
+	// 未記錄語系檔時只檢查 g_language
+	if s := Check(scPath, Expected{Channel: "LIVE", Language: "english"}); s.Reverted || s.INIMissing {
+		t.Errorf("language only: %+v", s)
+	}
+}
+
+func TestStateSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", StateFileName)
+	st := LoadState(path)
+	if len(st.Channels) != 0 {
+		t.Fatalf("missing state file: %+v", st)
+	}
+	st.RecordLanguage("PTU", "chinese")
+	st.RecordLanguage("LIVE", "chinese")
+	if err := st.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded := LoadState(path)
+	if got := loaded.ChannelNames(); !reflect.DeepEqual(got, []string{"LIVE", "PTU"}) {
+		t.Errorf("channels = %v", got)
+	}
+
+	// 重設為原版語系且沒有語系檔記錄時移除該版本
+	loaded.RecordLanguage("PTU", "")
+	loaded.Forget("LIVE")
+	if len(loaded.Channels) != 0 {
+		t.Errorf("channels after reset = %v", loaded.ChannelNames())
+	}
+
+	// 損毀的狀態檔視為空狀態
+	writeFile(t, path, "{broken")
+	if st := LoadState(path); len(st.Channels) != 0 || st.Channels == nil {
+		t.Errorf("corrupt state file: %+v", st)
+	}
+}
@@ -0,0 +1,66 @@
+package vehicleorder
+
+import (
+	"fmt"
+	"strings"
+
+	"zh-tool/pkg/ini"
+)
+
+// BaseKey 與前端一致：_short,p -> ,P；_short -> 去除；其他維持
+func BaseKey(key string) string {
+	kl := strings.ToLower(key)
+	if strings.HasSuffix(kl, "_short,p") {
+		return key[:len(key)-len("_short,p")] + ",P"
+	}
+	if strings.HasSuffix(kl, "_short") {
+		return key[:len(key)-len("_short")]
+	}
+	return key
+}
+
+// StripPrefix 若值為 NNN␠ 開頭則去除
+func StripPrefix(val string) string {
+	if len(val) >= 4 && val[0] >= '0' && val[0] <= '9' && val[1] >= '0' && val[1] <= '9' && val[2] >= '0' && val[2] <= '9' && (val[3] == ' ' || val[3] == '\t') {
+		return val[4:]
+	}
+	return val
+}
+
+// isVehicleName 判斷是否為載具名稱項目
+func isVehicleName(ln *ini.Line) bool {
+	return ln.Kind == ini.LineEntry && strings.Contains(strings.ToLower(ln.Key), "vehicle_name")
+}
+
+// Apply 就地替 vehicle_Name 項目加上 NNN 排序前綴（不在清單者移除前綴）
+func Apply(doc *ini.Document, baseKeys []string) {
+	orderMap := map[string]int{}
+	for i, k := range baseKeys {
+		orderMap[k] = i + 1
+	}
+	for _, ln := range doc.Lines {
+		if !isVehicleName(ln) {
+			continue
+		}
+		clean := StripPrefix(ln.Value)
+		if ord, ok := orderMap[BaseKey(ln.Key)]; ok {
+			// 加前綴
+			ln.SetValue(fmt.Sprintf("%03d %s", ord, clean))
+			continue
+		}
+		// 其他移除前綴
+		ln.SetValue(clean)
+	}
+}
+
+// Strip 就地移除 baseKey 在 set 中的 vehicle_Name 項目排序前綴
+func Strip(doc *ini.Document, set map[string]struct{}) {
+	for _, ln := range doc.Lines {
+		if !isVehicleName(ln) {
+			continue
+		}
+		if _, ok := set[BaseKey(ln.Key)]; ok {
+			ln.SetValue(StripPrefix(ln.Value))
+		}
+	}
+}
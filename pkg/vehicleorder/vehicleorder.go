@@ -0,0 +1,299 @@
+// Package vehicleorder 管理載具排序清單（Sort/active.json 與 Sort/save/*.json），
+// 並將排序以 NNN 前綴套用到語系檔中的 vehicle_Name 項目
+package vehicleorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"zh-tool/pkg/config"
+	"zh-tool/pkg/gameinstall"
+)
+
+// Order 載具排序檔內容
+type Order struct {
+	Type     string   `json:"type"`
+	Version  int      `json:"version"`
+	BaseKeys []string `json:"baseKeys"`
+}
+
+// valid 檢查排序檔的型別與版本
+func (o Order) valid() bool {
+	return o.Type == "vehicle_order" && o.Version > 0
+}
+
+// Store 排序檔存放位置：<Base>/active.json 與 <Base>/save/*.json
+type Store struct {
+	Base string
+}
+
+// New 建立以 base 為 Sort 目錄的儲存區
+func New(base string) *Store {
+	return &Store{Base: base}
+}
+
+// Default 回傳預設的 Sort 目錄（存放於使用者本機資料夾，避免寫入 Program Files 需提權）
+func Default() *Store {
+	return New(filepath.Join(config.DataDir(), "Sort"))
+}
+
+// EnsureDirs 確保 Sort 與 Sort/save 目錄存在；legacyGamePath 非空時嘗試從舊的遊戲資料夾位置遷移
+func (s *Store) EnsureDirs(legacyGamePath string) (string, string, error) {
+	if err := os.MkdirAll(s.Base, 0755); err != nil {
+		return "", "", err
+	}
+	save := filepath.Join(s.Base, "save")
+	if err := os.MkdirAll(save, 0755); err != nil {
+		return "", "", err
+	}
+	// 嘗試從舊位置遷移（僅第一次，若新位置為空）
+	_ = s.migrate(legacyGamePath)
+	return s.Base, save, nil
+}
+
+// writeOrder 寫入排序檔
+func writeOrder(dest string, baseKeys []string) error {
+	vo := Order{Type: "vehicle_order", Version: 1, BaseKeys: baseKeys}
+	data, err := json.MarshalIndent(vo, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, 0644)
+}
+
+// parseOrder 解析並驗證排序檔內容
+func parseOrder(data []byte) (Order, error) {
+	var vo Order
+	if err := json.Unmarshal(data, &vo); err != nil {
+		return vo, fmt.Errorf("invalid json: %w", err)
+	}
+	if !vo.valid() {
+		return vo, fmt.Errorf("unsupported vehicle_order json")
+	}
+	return vo, nil
+}
+
+// SaveActive 寫入 active.json
+func (s *Store) SaveActive(legacyGamePath string, baseKeys []string) (string, error) {
+	base, _, err := s.EnsureDirs(legacyGamePath)
+	if err != nil {
+		return "", err
+	}
+	dest := filepath.Join(base, "active.json")
+	if err := writeOrder(dest, baseKeys); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// SaveAs 另存新檔到 save 目錄，回傳完整路徑
+func (s *Store) SaveAs(legacyGamePath, name string, baseKeys []string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	// 簡單過濾檔名
+	safe := strings.NewReplacer("\\", "_", "/", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_").
+		Replace(strings.TrimSpace(name))
+
+	_, saveDir, err := s.EnsureDirs(legacyGamePath)
+	if err != nil {
+		return "", err
+	}
+	dest := filepath.Join(saveDir, safe+".json")
+	if err := writeOrder(dest, baseKeys); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// Active 讀取 active.json 並回傳 BaseKeys（不存在或格式不符時回傳空陣列）
+func (s *Store) Active(legacyGamePath string) ([]string, error) {
+	base, _, err := s.EnsureDirs(legacyGamePath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(base, "active.json"))
+	if err != nil {
+		// 不存在或讀取失敗即回傳空
+		return []string{}, nil
+	}
+	vo, err := parseOrder(data)
+	if err != nil {
+		return []string{}, nil
+	}
+	return vo.BaseKeys, nil
+}
+
+// ActiveSet 讀取 active.json（不建立目錄），回傳 BaseKeys 集合
+func (s *Store) ActiveSet() map[string]struct{} {
+	set := map[string]struct{}{}
+	data, err := os.ReadFile(filepath.Join(s.Base, "active.json"))
+	if err != nil {
+		return set
+	}
+	if vo, err := parseOrder(data); err == nil {
+		for _, k := range vo.BaseKeys {
+			set[k] = struct{}{}
+		}
+	}
+	return set
+}
+
+// List 列出 save 目錄下的檔名（不含副檔名）
+func (s *Store) List(legacyGamePath string) ([]string, error) {
+	_, saveDir, err := s.EnsureDirs(legacyGamePath)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(saveDir)
+	if err != nil {
+		return []string{}, nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if strings.HasSuffix(strings.ToLower(name), ".json") {
+			names = append(names, strings.TrimSuffix(name, filepath.Ext(name)))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Export 將 save/<name>.json 匯出到指定路徑
+func (s *Store) Export(legacyGamePath, name, destFile string) error {
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(destFile) == "" {
+		return fmt.Errorf("invalid params")
+	}
+	_, saveDir, err := s.EnsureDirs(legacyGamePath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(saveDir, name+".json"))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(destFile, data, 0644)
+}
+
+// Import 複製外部 JSON 到 save 目錄（使用原檔名），回傳完整路徑
+func (s *Store) Import(legacyGamePath, sourceFilePath string) (string, error) {
+	if strings.TrimSpace(sourceFilePath) == "" {
+		return "", fmt.Errorf("source path required")
+	}
+	data, err := os.ReadFile(sourceFilePath)
+	if err != nil {
+		return "", err
+	}
+	// 簡單驗證型別
+	if _, err := parseOrder(data); err != nil {
+		return "", err
+	}
+	_, saveDir, err := s.EnsureDirs(legacyGamePath)
+	if err != nil {
+		return "", err
+	}
+	base := filepath.Base(sourceFilePath)
+	if strings.ToLower(filepath.Ext(base)) != ".json" {
+		base = base + ".json"
+	}
+	dest := filepath.Join(saveDir, base)
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// Activate 以 save/<name>.json 覆蓋 active.json，回傳 BaseKeys
+func (s *Store) Activate(legacyGamePath, name string) ([]string, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	base, saveDir, err := s.EnsureDirs(legacyGamePath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(saveDir, name+".json"))
+	if err != nil {
+		return nil, err
+	}
+	vo, err := parseOrder(data)
+	if err != nil {
+		return nil, err
+	}
+	// 寫入 active.json
+	if err := os.WriteFile(filepath.Join(base, "active.json"), data, 0644); err != nil {
+		return nil, err
+	}
+	return vo.BaseKeys, nil
+}
+
+// Delete 刪除 save/<name>.json；不存在則跳過
+func (s *Store) Delete(legacyGamePath, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	_, saveDir, err := s.EnsureDirs(legacyGamePath)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(saveDir, name+".json")
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		return nil
+	}
+	return os.Remove(target)
+}
+
+// migrate 若新路徑下無 active.json/save，嘗試從舊的遊戲資料夾位置（<scPath>/<channel>/data/Sort）搬移
+func (s *Store) migrate(scPath string) error {
+	// 新位置已有資料則不動
+	newActive := filepath.Join(s.Base, "active.json")
+	newSave := filepath.Join(s.Base, "save")
+	if _, err := os.Stat(newActive); err == nil {
+		return nil
+	}
+	if entries, err := os.ReadDir(newSave); err == nil && len(entries) > 0 {
+		return nil
+	}
+	if scPath == "" {
+		return nil
+	}
+	var oldBase string
+	for _, ch := range gameinstall.Channels {
+		b := filepath.Join(gameinstall.DataDir(scPath, ch), "Sort")
+		if _, err := os.Stat(b); err == nil {
+			oldBase = b
+			break
+		}
+	}
+	if oldBase == "" {
+		return nil
+	}
+
+	// 搬移 active.json 與 save/*.json
+	_ = os.MkdirAll(newSave, 0755)
+	if data, err := os.ReadFile(filepath.Join(oldBase, "active.json")); err == nil {
+		_ = os.WriteFile(newActive, data, 0644)
+	}
+	if entries, err := os.ReadDir(filepath.Join(oldBase, "save")); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			name := e.Name()
+			if strings.HasSuffix(strings.ToLower(name), ".json") {
+				if data, err := os.ReadFile(filepath.Join(oldBase, "save", name)); err == nil {
+					_ = os.WriteFile(filepath.Join(newSave, name), data, 0644)
+				}
+			}
+		}
+	}
+	return nil
+}